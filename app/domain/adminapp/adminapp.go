@@ -0,0 +1,335 @@
+package adminapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/sergdort/Social/app/shared/errs"
+	"github.com/sergdort/Social/app/shared/mid"
+	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/cursor"
+	"github.com/sergdort/Social/foundation/jsn"
+	"github.com/sergdort/Social/foundation/slices"
+	"github.com/sergdort/Social/foundation/web"
+)
+
+type adminApp struct {
+	useCase *domain.AdminUseCase
+	audit   domain.AuditRepository
+	cursors *cursor.Codec
+}
+
+// ListRoles godoc
+//
+//	@Summary		Lists the roles
+//	@Description	Lists the roles users can have, ordered by level
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	RolesData
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/admin/roles [get]
+func (app *adminApp) listRolesHandler(ctx context.Context, r *http.Request) web.Encoder {
+	roles, err := app.useCase.ListRoles(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+	return web.NewResponse(roles)
+}
+
+// ListUsers godoc
+//
+//	@Summary		Lists users
+//	@Description	Lists users ordered by id, optionally searching their username and email
+//	@Tags			admin
+//	@Produce		json
+//	@Param			search	query		string	false	"Search"
+//	@Param			limit	query		int		false	"Limit"
+//	@Param			offset	query		int		false	"Offset"
+//	@Success		200		{object}	UsersResponse
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		403		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users [get]
+func (app *adminApp) listUsersHandler(ctx context.Context, r *http.Request) web.Encoder {
+	query := domain.UsersQuery{Limit: 20}
+	parseUsersQuery(&query, r)
+
+	if err := domain.Validate.Struct(query); err != nil {
+		return errs.Newf(errs.InvalidArgument, err.Error())
+	}
+
+	page, err := app.useCase.ListUsers(ctx, query)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	return UsersResponse{
+		Data:   slices.Map(page.Users, toAppUser),
+		Total:  page.Total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+}
+
+// GetUser godoc
+//
+//	@Summary		Fetches a user
+//	@Description	Fetches a user with their role, activation and ban
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	User
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{id} [get]
+func (app *adminApp) getUserHandler(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := getUserID(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	user, err := app.useCase.GetUser(ctx, userID)
+	if err != nil {
+		return adminError(err)
+	}
+	return toAppUser(*user)
+}
+
+// ChangeRole godoc
+//
+//	@Summary		Changes the role of a user
+//	@Description	Changes the role of a user, admins cannot change their own role
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int							true	"User ID"
+//	@Param			payload	body		domain.ChangeRolePayload	true	"Role"
+//	@Success		200		{object}	User
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		403		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{id}/role [put]
+func (app *adminApp) changeRoleHandler(ctx context.Context, r *http.Request) web.Encoder {
+	actorID, userID, errResp := actorAndUserID(ctx, r)
+	if errResp != nil {
+		return errResp
+	}
+
+	var payload domain.ChangeRolePayload
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "invalid payload %s", err.Error())
+	}
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, err.Error())
+	}
+
+	user, err := app.useCase.ChangeRole(ctx, actorID, userID, payload.Role)
+	if err != nil {
+		return adminError(err)
+	}
+	return toAppUser(*user)
+}
+
+// Activate godoc
+//
+//	@Summary		Activates a user
+//	@Description	Activates a user without their invitation
+//	@Tags			admin
+//	@Param			id	path	int	true	"User ID"
+//	@Success		204
+//	@Failure		400	{object}	error
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{id}/activate [put]
+func (app *adminApp) activateHandler(ctx context.Context, r *http.Request) web.Encoder {
+	return app.act(ctx, r, app.useCase.Activate)
+}
+
+// Deactivate godoc
+//
+//	@Summary		Deactivates a user
+//	@Description	Deactivates a user, admins cannot deactivate themselves
+//	@Tags			admin
+//	@Param			id	path	int	true	"User ID"
+//	@Success		204
+//	@Failure		400	{object}	error
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{id}/deactivate [put]
+func (app *adminApp) deactivateHandler(ctx context.Context, r *http.Request) web.Encoder {
+	return app.act(ctx, r, app.useCase.Deactivate)
+}
+
+// Ban godoc
+//
+//	@Summary		Bans a user
+//	@Description	Bans a user from logging in and revokes their refresh tokens, admins cannot ban themselves
+//	@Tags			admin
+//	@Param			id	path	int	true	"User ID"
+//	@Success		204
+//	@Failure		400	{object}	error
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{id}/ban [put]
+func (app *adminApp) banHandler(ctx context.Context, r *http.Request) web.Encoder {
+	return app.act(ctx, r, app.useCase.Ban)
+}
+
+// Unban godoc
+//
+//	@Summary		Unbans a user
+//	@Description	Lifts the ban of a user
+//	@Tags			admin
+//	@Param			id	path	int	true	"User ID"
+//	@Success		204
+//	@Failure		400	{object}	error
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{id}/unban [put]
+func (app *adminApp) unbanHandler(ctx context.Context, r *http.Request) web.Encoder {
+	return app.act(ctx, r, app.useCase.Unban)
+}
+
+// DeleteUser godoc
+//
+//	@Summary		Deletes a user
+//	@Description	Deletes a user with their posts, comments and reactions, admins cannot delete themselves
+//	@Tags			admin
+//	@Param			id	path	int	true	"User ID"
+//	@Success		204
+//	@Failure		400	{object}	error
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/admin/users/{id} [delete]
+func (app *adminApp) deleteUserHandler(ctx context.Context, r *http.Request) web.Encoder {
+	return app.act(ctx, r, app.useCase.Delete)
+}
+
+// ListAuditEvents godoc
+//
+//	@Summary		Queries the audit log
+//	@Description	Lists audit events newest first, filtered by actor, action, target and time
+//	@Tags			admin
+//	@Produce		json
+//	@Param			actor_id	query		int		false	"Actor ID"
+//	@Param			action		query		string	false	"Action"		example(user.banned)
+//	@Param			target_type	query		string	false	"Target type"	example(user)
+//	@Param			target_id	query		int		false	"Target ID"
+//	@Param			since		query		string	false	"Oldest time, RFC 3339"
+//	@Param			until		query		string	false	"Newest time, RFC 3339"
+//	@Param			limit		query		int		false	"Limit"
+//	@Param			cursor		query		string	false	"Cursor of the next page"
+//	@Success		200			{object}	AuditEventsData
+//	@Failure		400			{object}	error
+//	@Failure		401			{object}	error
+//	@Failure		403			{object}	error
+//	@Failure		500			{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/admin/audit-events [get]
+func (app *adminApp) listAuditEventsHandler(ctx context.Context, r *http.Request) web.Encoder {
+	query := domain.AuditQuery{Limit: 50}
+	if err := parseAuditQuery(&query, r); err != nil {
+		return errs.Newf(errs.InvalidArgument, err.Error())
+	}
+
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		var position domain.Cursor
+		if err := app.cursors.Decode(c, &position); err != nil {
+			return errs.Newf(errs.InvalidArgument, "cursor: %s", err.Error())
+		}
+		query.Cursor = &position
+	}
+
+	if err := domain.Validate.Struct(query); err != nil {
+		return errs.Newf(errs.InvalidArgument, err.Error())
+	}
+
+	page, err := app.audit.Query(ctx, query)
+	if err != nil {
+		return errs.Newf(errs.Internal, "could not query audit events %s", err.Error())
+	}
+
+	var nextCursor string
+	if page.NextCursor != nil {
+		if nextCursor, err = app.cursors.Encode(page.NextCursor); err != nil {
+			return errs.Newf(errs.Internal, "could not encode audit cursor %s", err.Error())
+		}
+	}
+
+	return web.NewPageResponse(page.Events, nextCursor)
+}
+
+// act performs an action of the authenticated admin on the user of the
+// request.
+func (app *adminApp) act(
+	ctx context.Context,
+	r *http.Request,
+	action func(ctx context.Context, actorID int64, userID int64) error,
+) web.Encoder {
+	actorID, userID, errResp := actorAndUserID(ctx, r)
+	if errResp != nil {
+		return errResp
+	}
+
+	if err := action(ctx, actorID, userID); err != nil {
+		return adminError(err)
+	}
+	return web.NewNoResponse()
+}
+
+func actorAndUserID(ctx context.Context, r *http.Request) (int64, int64, *errs.Error) {
+	actorID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return 0, 0, errs.New(errs.Internal, err)
+	}
+	userID, err := getUserID(r)
+	if err != nil {
+		return 0, 0, errs.New(errs.InvalidArgument, err)
+	}
+	return actorID, userID, nil
+}
+
+func getUserID(r *http.Request) (int64, error) {
+	return strconv.ParseInt(web.Param(r, "userID"), 10, 64)
+}
+
+func adminError(err error) *errs.Error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return errs.Newf(errs.NotFound, "user not found")
+	case errors.Is(err, domain.ErrInvalidRole):
+		return errs.Newf(errs.InvalidArgument, "invalid role")
+	case errors.Is(err, domain.ErrSelfAdministration):
+		return errs.New(errs.FailedPrecondition, err)
+	default:
+		return errs.New(errs.Internal, err)
+	}
+}
@@ -0,0 +1,108 @@
+package adminapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sergdort/Social/business/domain"
+)
+
+type User struct {
+	ID        int64       `json:"id" example:"38"`
+	Username  string      `json:"username" example:"GendryBaratheon"`
+	Email     string      `json:"email" example:"gendry@stormsend.com"`
+	CreatedAt string      `json:"created_at" example:"2025-03-19 10:08:25 +0000 UTC"`
+	IsActive  bool        `json:"is_active" example:"true"`
+	BannedAt  *time.Time  `json:"banned_at" example:"2025-03-19T10:08:25Z"`
+	Role      domain.Role `json:"role"`
+}
+
+func toAppUser(user domain.User) User {
+	return User{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		CreatedAt: user.CreatedAt,
+		IsActive:  user.IsActive,
+		BannedAt:  user.BannedAt,
+		Role:      user.Role,
+	}
+}
+
+func (usr User) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(usr)
+	return data, "application/json", err
+}
+
+// UsersResponse is an offset paginated page of users.
+type UsersResponse struct {
+	Data   []User `json:"data"`
+	Total  int64  `json:"total" example:"120"`
+	Limit  int    `json:"limit" example:"20"`
+	Offset int    `json:"offset" example:"40"`
+}
+
+func (resp UsersResponse) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(resp)
+	return data, "application/json", err
+}
+
+// Needed for swagger docs, should not be used
+type RolesData struct {
+	Data []domain.Role `json:"data"`
+}
+
+// Needed for swagger docs, should not be used
+type AuditEventsData struct {
+	Data       []domain.AuditEvent `json:"data"`
+	NextCursor string              `json:"next_cursor" example:"eyJjcmVhdGVkX2F0Ijo...Rk"`
+}
+
+// parseAuditQuery reports malformed filters rather than dropping them, an
+// ignored filter would widen the query silently.
+func parseAuditQuery(q *domain.AuditQuery, r *http.Request) error {
+	qs := r.URL.Query()
+
+	if limit, err := strconv.Atoi(qs.Get("limit")); err == nil {
+		q.Limit = limit
+	}
+	q.Action = qs.Get("action")
+	q.TargetType = qs.Get("target_type")
+
+	for name, dst := range map[string]**int64{"actor_id": &q.ActorID, "target_id": &q.TargetID} {
+		if v := qs.Get(name); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			*dst = &id
+		}
+	}
+
+	for name, dst := range map[string]**time.Time{"since": &q.Since, "until": &q.Until} {
+		if v := qs.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			*dst = &t
+		}
+	}
+
+	return nil
+}
+
+func parseUsersQuery(q *domain.UsersQuery, r *http.Request) {
+	qs := r.URL.Query()
+
+	if limit, err := strconv.Atoi(qs.Get("limit")); err == nil {
+		q.Limit = limit
+	}
+	if offset, err := strconv.Atoi(qs.Get("offset")); err == nil {
+		q.Offset = offset
+	}
+	q.Search = qs.Get("search")
+}
@@ -0,0 +1,42 @@
+package adminapp
+
+import (
+	"github.com/sergdort/Social/app/shared/mid"
+	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/cursor"
+	"github.com/sergdort/Social/foundation/web"
+	"net/http"
+)
+
+type Config struct {
+	Auth       *domain.AuthUseCase
+	Authorizer *domain.Authorizer
+	Users      *domain.UsersUseCase
+	UseCase    *domain.AdminUseCase
+	Audit      domain.AuditRepository
+	Cursors    *cursor.Codec
+}
+
+func Routes(app *web.App, config Config) {
+	const version = "v1"
+
+	api := adminApp{useCase: config.UseCase, audit: config.Audit, cursors: config.Cursors}
+	auth := mid.Bearer(config.Auth)
+	user := mid.LoadUser(config.Users)
+	canRead := mid.Authorize(config.Authorizer, domain.PermissionUsersRead, nil)
+	canUpdate := mid.Authorize(config.Authorizer, domain.PermissionUsersUpdate, nil)
+	canBan := mid.Authorize(config.Authorizer, domain.PermissionUsersBan, nil)
+	canDelete := mid.Authorize(config.Authorizer, domain.PermissionUsersDelete, nil)
+	canReadAudit := mid.Authorize(config.Authorizer, domain.PermissionAuditRead, nil)
+
+	app.HandlerFunc(http.MethodGet, version, "/admin/roles", api.listRolesHandler, auth, user, canRead)
+	app.HandlerFunc(http.MethodGet, version, "/admin/users", api.listUsersHandler, auth, user, canRead)
+	app.HandlerFunc(http.MethodGet, version, "/admin/users/{userID}", api.getUserHandler, auth, user, canRead)
+	app.HandlerFunc(http.MethodPut, version, "/admin/users/{userID}/role", api.changeRoleHandler, auth, user, canUpdate)
+	app.HandlerFunc(http.MethodPut, version, "/admin/users/{userID}/activate", api.activateHandler, auth, user, canUpdate)
+	app.HandlerFunc(http.MethodPut, version, "/admin/users/{userID}/deactivate", api.deactivateHandler, auth, user, canUpdate)
+	app.HandlerFunc(http.MethodPut, version, "/admin/users/{userID}/ban", api.banHandler, auth, user, canBan)
+	app.HandlerFunc(http.MethodPut, version, "/admin/users/{userID}/unban", api.unbanHandler, auth, user, canBan)
+	app.HandlerFunc(http.MethodDelete, version, "/admin/users/{userID}", api.deleteUserHandler, auth, user, canDelete)
+	app.HandlerFunc(http.MethodGet, version, "/admin/audit-events", api.listAuditEventsHandler, auth, user, canReadAudit)
+}
@@ -2,15 +2,22 @@ package authapp
 
 import (
 	"context"
+	"errors"
 	"github.com/sergdort/Social/app/shared/errs"
+	"github.com/sergdort/Social/app/shared/mid"
 	"github.com/sergdort/Social/business/domain"
 	"github.com/sergdort/Social/foundation/jsn"
 	"github.com/sergdort/Social/foundation/web"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 type authApp struct {
-	useCase *domain.AuthUseCase
+	useCase               *domain.AuthUseCase
+	exposeInvitationToken bool
+	jwks                  JWKS
+	oidc                  *domain.OIDCUseCase
 }
 
 func (app *authApp) registerUserHandler(ctx context.Context, r *http.Request) web.Encoder {
@@ -27,20 +34,27 @@ func (app *authApp) registerUserHandler(ctx context.Context, r *http.Request) we
 	if error != nil {
 		return errs.Newf(errs.Internal, "Failed to register user: %s", error.Error())
 	}
+	if !app.exposeInvitationToken {
+		// The token is only delivered by email.
+		return domain.InvitationToken{}
+	}
 	return token
 }
 
 // createTokenHandler godoc
 //
 //	@Summary		Creates a token
-//	@Description	Creates a token for a user
+//	@Description	Creates a token for a user. Users with two-factor authentication get a challenge to complete at /authentication/mfa/verify instead. Users who are not active get a failed_precondition error.
 //	@Tags			authentication
 //	@Accept			json
 //	@Produce		json
 //	@Param			payload	body		domain.CreateUserTokenPayload	true	"User credentials"
 //	@Success		200		{object}	TokenResponse
+//	@Success		202		{object}	MFAChallengeResponse
 //	@Failure		400		{object}	error
 //	@Failure		401		{object}	error
+//	@Failure		403		{object}	error
+//	@Failure		429		{object}	error
 //	@Failure		500		{object}	error
 //	@Router			/authentication/token [post]
 func (app *authApp) createTokenHandler(ctx context.Context, r *http.Request) web.Encoder {
@@ -48,9 +62,435 @@ func (app *authApp) createTokenHandler(ctx context.Context, r *http.Request) web
 	var payload domain.CreateUserTokenPayload
 	err := jsn.ReadJSON(r, &payload)
 	// check if the user exists
-	token, err := app.useCase.CreateToken(ctx, payload)
+	login, err := app.useCase.CreateToken(ctx, payload)
 	if err != nil {
+		if errors.Is(err, domain.ErrUserBanned) {
+			return errs.Newf(errs.PermissionDenied, "user is banned")
+		}
+		if errors.Is(err, domain.ErrUserInactive) {
+			return errs.Newf(errs.FailedPrecondition, "user is not active")
+		}
+		var locked *domain.LoginLockedError
+		if errors.As(err, &locked) {
+			retryAfter := int64(math.Ceil(locked.RetryAfter.Seconds()))
+			web.GetWriter(ctx).Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+			return errs.Newf(errs.TooManyRequests, "Too many failed logins, retry in %d seconds", retryAfter)
+		}
 		return errs.Newf(errs.InvalidArgument, "Invalid email or password")
 	}
-	return TokenResponse{Token: token}
+	if login.Challenge != nil {
+		return MFAChallengeResponse{
+			MFARequired:  true,
+			MFAChallenge: login.Challenge.Token,
+			ExpiresAt:    login.Challenge.ExpiresAt,
+		}
+	}
+	return TokenResponse{Token: login.Tokens.AccessToken, RefreshToken: login.Tokens.RefreshToken}
+}
+
+// refreshTokenHandler godoc
+//
+//	@Summary		Refreshes a token
+//	@Description	Exchanges a refresh token for a new access and refresh token. A refresh token can only be used once.
+//	@Tags			authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			payload	body		domain.RefreshTokenPayload	true	"Refresh token"
+//	@Success		200		{object}	TokenResponse
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		403		{object}	error
+//	@Failure		429		{object}	error
+//	@Failure		500		{object}	error
+//	@Router			/authentication/refresh [post]
+func (app *authApp) refreshTokenHandler(ctx context.Context, r *http.Request) web.Encoder {
+	var payload domain.RefreshTokenPayload
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	tokens, err := app.useCase.RefreshToken(ctx, payload.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidRefreshToken), errors.Is(err, domain.ErrRefreshTokenReused):
+			return errs.Newf(errs.Unauthenticated, "invalid refresh token")
+		case errors.Is(err, domain.ErrUserBanned):
+			return errs.Newf(errs.PermissionDenied, "user is banned")
+		case errors.Is(err, domain.ErrUserInactive):
+			return errs.Newf(errs.FailedPrecondition, "user is not active")
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+	return TokenResponse{Token: tokens.AccessToken, RefreshToken: tokens.RefreshToken}
+}
+
+// logoutHandler godoc
+//
+//	@Summary		Logs out
+//	@Description	Revokes the access token and, when given, the refresh token issued with it
+//	@Tags			authentication
+//	@Accept			json
+//	@Param			payload	body	domain.LogoutPayload	false	"Refresh token"
+//	@Success		204
+//	@Failure		400	{object}	error
+//	@Failure		401	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/authentication/logout [post]
+func (app *authApp) logoutHandler(ctx context.Context, r *http.Request) web.Encoder {
+	claims, err := mid.GetClaims(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	var payload domain.LogoutPayload
+	if r.ContentLength != 0 {
+		if err := jsn.ReadJSON(r, &payload); err != nil {
+			return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+		}
+	}
+
+	if err := app.useCase.Logout(ctx, claims, payload.RefreshToken); err != nil {
+		return errs.New(errs.Internal, err)
+	}
+	return web.NewNoResponse()
+}
+
+// resendInvitationHandler godoc
+//
+//	@Summary		Resends the invitation
+//	@Description	Emails a new activation link to a user who has not activated their account yet, the previous link stops working. Responds the same whether or not the email is registered.
+//	@Tags			authentication
+//	@Accept			json
+//	@Param			payload	body	domain.ResendInvitationPayload	true	"Email of the account"
+//	@Success		204
+//	@Failure		400	{object}	error
+//	@Failure		429	{object}	error
+//	@Failure		500	{object}	error
+//	@Router			/authentication/invitation/resend [post]
+func (app *authApp) resendInvitationHandler(ctx context.Context, r *http.Request) web.Encoder {
+	var payload domain.ResendInvitationPayload
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	if err := app.useCase.ResendInvitation(ctx, payload); err != nil {
+		return errs.New(errs.Internal, err)
+	}
+	return web.NewNoResponse()
+}
+
+// requestPasswordResetHandler godoc
+//
+//	@Summary		Requests a password reset
+//	@Description	Emails a single-use link to reset the password. Responds the same whether or not the email is registered.
+//	@Tags			authentication
+//	@Accept			json
+//	@Param			payload	body	domain.RequestPasswordResetPayload	true	"Email of the account"
+//	@Success		204
+//	@Failure		400	{object}	error
+//	@Failure		429	{object}	error
+//	@Failure		500	{object}	error
+//	@Router			/authentication/password-reset [post]
+func (app *authApp) requestPasswordResetHandler(ctx context.Context, r *http.Request) web.Encoder {
+	var payload domain.RequestPasswordResetPayload
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	if err := app.useCase.RequestPasswordReset(ctx, payload); err != nil {
+		return errs.New(errs.Internal, err)
+	}
+	return web.NewNoResponse()
+}
+
+// resetPasswordHandler godoc
+//
+//	@Summary		Resets the password
+//	@Description	Sets a new password with the token from the password reset email and logs the user out of all sessions
+//	@Tags			authentication
+//	@Accept			json
+//	@Param			payload	body	domain.ResetPasswordPayload	true	"Reset token and new password"
+//	@Success		204
+//	@Failure		400	{object}	error
+//	@Failure		429	{object}	error
+//	@Failure		500	{object}	error
+//	@Router			/authentication/password-reset/confirm [post]
+func (app *authApp) resetPasswordHandler(ctx context.Context, r *http.Request) web.Encoder {
+	var payload domain.ResetPasswordPayload
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	if err := app.useCase.ResetPassword(ctx, payload); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidPasswordResetToken):
+			return errs.Newf(errs.InvalidArgument, "invalid or expired password reset token")
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+	return web.NewNoResponse()
+}
+
+// verifyMFAHandler godoc
+//
+//	@Summary		Completes a two-factor login
+//	@Description	Exchanges the challenge returned by /authentication/token and a code from the authenticator app, or a recovery code, for the tokens
+//	@Tags			authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			payload	body		domain.VerifyMFAPayload	true	"Challenge and code"
+//	@Success		200		{object}	TokenResponse
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		403		{object}	error
+//	@Failure		429		{object}	error
+//	@Failure		500		{object}	error
+//	@Router			/authentication/mfa/verify [post]
+func (app *authApp) verifyMFAHandler(ctx context.Context, r *http.Request) web.Encoder {
+	var payload domain.VerifyMFAPayload
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	tokens, err := app.useCase.VerifyMFA(ctx, payload)
+	if err != nil {
+		return mfaError(err)
+	}
+	return TokenResponse{Token: tokens.AccessToken, RefreshToken: tokens.RefreshToken}
+}
+
+// enrollTOTPHandler godoc
+//
+//	@Summary		Starts the two-factor authentication enrollment
+//	@Description	Generates a TOTP secret and its otpauth:// URI to add to an authenticator app. It must be confirmed with a code to be enabled.
+//	@Tags			authentication
+//	@Produce		json
+//	@Success		200	{object}	TOTPEnrollmentResponse
+//	@Failure		401	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/authentication/mfa/totp [post]
+func (app *authApp) enrollTOTPHandler(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	enrollment, err := app.useCase.EnrollTOTP(ctx, userID)
+	if err != nil {
+		return mfaError(err)
+	}
+	return TOTPEnrollmentResponse{enrollment}
+}
+
+// confirmTOTPHandler godoc
+//
+//	@Summary		Enables two-factor authentication
+//	@Description	Confirms the enrollment with a code from the authenticator app and returns the recovery codes. They are not shown again.
+//	@Tags			authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			payload	body		domain.MFACodePayload	true	"Code from the authenticator app"
+//	@Success		200		{object}	RecoveryCodesResponse
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/authentication/mfa/totp/confirm [post]
+func (app *authApp) confirmTOTPHandler(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	var payload domain.MFACodePayload
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	codes, err := app.useCase.ConfirmTOTP(ctx, userID, payload.Code)
+	if err != nil {
+		return mfaError(err)
+	}
+	return RecoveryCodesResponse{RecoveryCodes: codes}
+}
+
+// disableTOTPHandler godoc
+//
+//	@Summary		Disables two-factor authentication
+//	@Description	Disables two-factor authentication with a code from the authenticator app or a recovery code
+//	@Tags			authentication
+//	@Accept			json
+//	@Param			payload	body	domain.MFACodePayload	true	"Code from the authenticator app or recovery code"
+//	@Success		204
+//	@Failure		400	{object}	error
+//	@Failure		401	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/authentication/mfa/totp [delete]
+func (app *authApp) disableTOTPHandler(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	var payload domain.MFACodePayload
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	if err := app.useCase.DisableTOTP(ctx, userID, payload.Code); err != nil {
+		return mfaError(err)
+	}
+	return web.NewNoResponse()
+}
+
+func mfaError(err error) *errs.Error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidMFAChallenge):
+		return errs.Newf(errs.Unauthenticated, "invalid or expired challenge")
+	case errors.Is(err, domain.ErrInvalidMFACode):
+		return errs.Newf(errs.InvalidArgument, "invalid code")
+	case errors.Is(err, domain.ErrMFANotEnrolled), errors.Is(err, domain.ErrMFAAlreadyEnabled):
+		return errs.New(errs.FailedPrecondition, err)
+	case errors.Is(err, domain.ErrUserBanned):
+		return errs.Newf(errs.PermissionDenied, "user is banned")
+	case errors.Is(err, domain.ErrUserInactive):
+		return errs.Newf(errs.FailedPrecondition, "user is not active")
+	default:
+		return errs.New(errs.Internal, err)
+	}
+}
+
+// startOIDCHandler godoc
+//
+//	@Summary		Starts a login with an identity provider
+//	@Description	Returns the URL of the OpenID Connect provider to send the user to. The provider sends the user back with a code and the state to complete the login at /authentication/oidc/{provider}/callback.
+//	@Tags			authentication
+//	@Produce		json
+//	@Param			provider	path		string	true	"Provider name"
+//	@Success		200			{object}	OIDCAuthorizationResponse
+//	@Failure		404			{object}	error
+//	@Failure		429			{object}	error
+//	@Failure		500			{object}	error
+//	@Router			/authentication/oidc/{provider}/start [post]
+func (app *authApp) startOIDCHandler(ctx context.Context, r *http.Request) web.Encoder {
+	authorization, err := app.oidc.Start(ctx, web.Param(r, "provider"))
+	if err != nil {
+		return oidcError(err)
+	}
+	return OIDCAuthorizationResponse{authorization}
+}
+
+// oidcCallbackHandler godoc
+//
+//	@Summary		Completes a login with an identity provider
+//	@Description	Exchanges the code the provider sent back for tokens. The identity is linked to the user with the same verified email, or a new user is created. Users with two-factor authentication get a challenge to complete at /authentication/mfa/verify instead.
+//	@Tags			authentication
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider	path		string						true	"Provider name"
+//	@Param			payload		body		domain.OIDCCallbackPayload	true	"Code and state from the provider"
+//	@Success		200			{object}	TokenResponse
+//	@Success		202			{object}	MFAChallengeResponse
+//	@Failure		400			{object}	error
+//	@Failure		401			{object}	error
+//	@Failure		403			{object}	error
+//	@Failure		404			{object}	error
+//	@Failure		429			{object}	error
+//	@Failure		500			{object}	error
+//	@Router			/authentication/oidc/{provider}/callback [post]
+func (app *authApp) oidcCallbackHandler(ctx context.Context, r *http.Request) web.Encoder {
+	var payload domain.OIDCCallbackPayload
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	login, err := app.oidc.Callback(ctx, web.Param(r, "provider"), payload)
+	if err != nil {
+		return oidcError(err)
+	}
+	if login.Challenge != nil {
+		return MFAChallengeResponse{
+			MFARequired:  true,
+			MFAChallenge: login.Challenge.Token,
+			ExpiresAt:    login.Challenge.ExpiresAt,
+		}
+	}
+	return TokenResponse{Token: login.Tokens.AccessToken, RefreshToken: login.Tokens.RefreshToken}
+}
+
+func oidcError(err error) *errs.Error {
+	switch {
+	case errors.Is(err, domain.ErrUnknownOIDCProvider):
+		return errs.New(errs.NotFound, err)
+	case errors.Is(err, domain.ErrInvalidOIDCState):
+		return errs.New(errs.InvalidArgument, err)
+	case errors.Is(err, domain.ErrOIDCEmailNotVerified):
+		return errs.New(errs.PermissionDenied, err)
+	case errors.Is(err, domain.ErrUserBanned):
+		return errs.Newf(errs.PermissionDenied, "user is banned")
+	case errors.Is(err, domain.ErrUserInactive):
+		return errs.Newf(errs.FailedPrecondition, "user is not active")
+	case errors.Is(err, domain.ErrOIDCLoginFailed):
+		return errs.Newf(errs.Unauthenticated, "login with the identity provider failed")
+	default:
+		return errs.New(errs.Internal, err)
+	}
+}
+
+// jwksHandler godoc
+//
+//	@Summary		Lists the token signing keys
+//	@Description	Returns the public keys access tokens are signed with as a JSON Web Key Set, for other services to verify tokens
+//	@Tags			authentication
+//	@Produce		json
+//	@Success		200	{object}	object
+//	@Failure		500	{object}	error
+//	@Router			/.well-known/jwks.json [get]
+func (app *authApp) jwksHandler(ctx context.Context, r *http.Request) web.Encoder {
+	data, err := app.jwks.JWKS()
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	// Keys only change on deploys, let verifiers cache them for a while.
+	web.GetWriter(ctx).Header().Set("Cache-Control", "public, max-age=300")
+
+	return JWKSResponse(data)
 }
@@ -1,12 +1,70 @@
 package authapp
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"github.com/sergdort/Social/business/domain"
+	"net/http"
+	"time"
+)
 
 type TokenResponse struct {
-	Token string `json:"token" binding:"required" example:"JWT_TOKEN"`
+	Token        string `json:"token" binding:"required" example:"JWT_TOKEN"`
+	RefreshToken string `json:"refresh_token" binding:"required" example:"REFRESH_TOKEN"`
 }
 
 func (token TokenResponse) Encode() (data []byte, contentType string, err error) {
 	data, err = json.Marshal(token)
 	return data, "application/json", err
 }
+
+// MFAChallengeResponse is returned by the token endpoint instead of the
+// tokens when the user has two-factor authentication.
+type MFAChallengeResponse struct {
+	MFARequired  bool      `json:"mfa_required" example:"true"`
+	MFAChallenge string    `json:"mfa_challenge"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// HTTPStatus tells the client the login is not complete yet.
+func (challenge MFAChallengeResponse) HTTPStatus() int {
+	return http.StatusAccepted
+}
+
+func (challenge MFAChallengeResponse) Encode() (data []byte, contentType string, err error) {
+	data, err = json.Marshal(challenge)
+	return data, "application/json", err
+}
+
+type TOTPEnrollmentResponse struct {
+	domain.TOTPEnrollment
+}
+
+func (enrollment TOTPEnrollmentResponse) Encode() (data []byte, contentType string, err error) {
+	data, err = json.Marshal(enrollment)
+	return data, "application/json", err
+}
+
+type OIDCAuthorizationResponse struct {
+	domain.OIDCAuthorization
+}
+
+func (authorization OIDCAuthorizationResponse) Encode() (data []byte, contentType string, err error) {
+	data, err = json.Marshal(authorization)
+	return data, "application/json", err
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func (codes RecoveryCodesResponse) Encode() (data []byte, contentType string, err error) {
+	data, err = json.Marshal(codes)
+	return data, "application/json", err
+}
+
+// JWKSResponse is an encoded JSON Web Key Set.
+type JWKSResponse json.RawMessage
+
+func (jwks JWKSResponse) Encode() (data []byte, contentType string, err error) {
+	return jwks, "application/json", nil
+}
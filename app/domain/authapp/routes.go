@@ -1,20 +1,75 @@
 package authapp
 
 import (
+	"github.com/sergdort/Social/app/shared/mid"
 	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/ratelimit"
 	"github.com/sergdort/Social/foundation/web"
 	"net/http"
+	"time"
 )
 
 type Config struct {
 	UseCase *domain.AuthUseCase
+	// Users loads the signed in user, so inactive and banned users cannot
+	// manage their second factor.
+	Users *domain.UsersUseCase
+	// ExposeInvitationToken returns the invitation token in the registration
+	// response, so it can be activated without reading the email. Only meant
+	// for development.
+	ExposeInvitationToken bool
+	// JWKS publishes the public keys tokens are verified with.
+	JWKS JWKS
+	// RateLimits counts the requests of the rate limited routes.
+	RateLimits ratelimit.Store
+	// OIDC logs users in with identity providers, the routes are only
+	// registered when it is set.
+	OIDC *domain.OIDCUseCase
+}
+
+// JWKS provides the JSON Web Key Set of the token signing keys.
+type JWKS interface {
+	JWKS() ([]byte, error)
 }
 
 func Routes(app *web.App, config Config) {
 	const version = "v1"
 
-	api := authApp{useCase: config.UseCase}
+	api := authApp{useCase: config.UseCase, exposeInvitationToken: config.ExposeInvitationToken, jwks: config.JWKS, oidc: config.OIDC}
+	auth := mid.Bearer(config.UseCase)
+	interactive := mid.DenyPersonalTokens()
+	user := mid.LoadUser(config.Users)
+	registerLimit := mid.RateLimit(config.RateLimits, "register", ratelimit.Limit{
+		Algorithm: ratelimit.SlidingWindow, Requests: 10, Period: time.Hour,
+	})
+	loginLimit := mid.RateLimit(config.RateLimits, "login", ratelimit.Limit{
+		Algorithm: ratelimit.SlidingWindow, Requests: 20, Period: time.Minute,
+	})
+	refreshLimit := mid.RateLimit(config.RateLimits, "refresh", ratelimit.Limit{
+		Algorithm: ratelimit.TokenBucket, Requests: 30, Period: time.Minute, Burst: 10,
+	})
+	invitationLimit := mid.RateLimit(config.RateLimits, "invitation-resend", ratelimit.Limit{
+		Algorithm: ratelimit.SlidingWindow, Requests: 5, Period: time.Hour,
+	})
+	passwordResetLimit := mid.RateLimit(config.RateLimits, "password-reset", ratelimit.Limit{
+		Algorithm: ratelimit.SlidingWindow, Requests: 5, Period: time.Hour,
+	})
+
+	app.HandlerFunc(http.MethodGet, "", "/.well-known/jwks.json", api.jwksHandler)
+	app.HandlerFunc(http.MethodPost, version, "/authentication/user", api.registerUserHandler, registerLimit)
+	app.HandlerFunc(http.MethodPost, version, "/authentication/invitation/resend", api.resendInvitationHandler, invitationLimit)
+	app.HandlerFunc(http.MethodPost, version, "/authentication/token", api.createTokenHandler, loginLimit)
+	app.HandlerFunc(http.MethodPost, version, "/authentication/refresh", api.refreshTokenHandler, refreshLimit)
+	app.HandlerFunc(http.MethodPost, version, "/authentication/password-reset", api.requestPasswordResetHandler, passwordResetLimit)
+	app.HandlerFunc(http.MethodPost, version, "/authentication/password-reset/confirm", api.resetPasswordHandler, passwordResetLimit)
+	app.HandlerFunc(http.MethodPost, version, "/authentication/logout", api.logoutHandler, auth, interactive)
+	app.HandlerFunc(http.MethodPost, version, "/authentication/mfa/verify", api.verifyMFAHandler, loginLimit)
+	app.HandlerFunc(http.MethodPost, version, "/authentication/mfa/totp", api.enrollTOTPHandler, auth, interactive, user)
+	app.HandlerFunc(http.MethodPost, version, "/authentication/mfa/totp/confirm", api.confirmTOTPHandler, auth, interactive, user)
+	app.HandlerFunc(http.MethodDelete, version, "/authentication/mfa/totp", api.disableTOTPHandler, auth, interactive, user)
 
-	app.HandlerFunc(http.MethodPost, version, "/authentication/user", api.registerUserHandler)
-	app.HandlerFunc(http.MethodPost, version, "/authentication/token", api.createTokenHandler)
+	if config.OIDC != nil {
+		app.HandlerFunc(http.MethodPost, version, "/authentication/oidc/{provider}/start", api.startOIDCHandler, loginLimit)
+		app.HandlerFunc(http.MethodPost, version, "/authentication/oidc/{provider}/callback", api.oidcCallbackHandler, loginLimit)
+	}
 }
@@ -0,0 +1,492 @@
+package commentsapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/sergdort/Social/app/shared/errs"
+	"github.com/sergdort/Social/app/shared/mid"
+	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/cursor"
+	"github.com/sergdort/Social/foundation/jsn"
+	"github.com/sergdort/Social/foundation/web"
+)
+
+type commentsApp struct {
+	posts     domain.PostsRepository
+	comments  domain.CommentsRepository
+	reactions domain.ReactionsRepository
+	audit     domain.AuditLogger
+	cursors   *cursor.Codec
+}
+
+type ctxKey string
+
+const (
+	postCtx    ctxKey = "post"
+	commentCtx ctxKey = "comment"
+)
+
+// CreateComment godoc
+//
+//	@Summary		Comments a post
+//	@Description	Creates a comment on a post, or a reply to one of its comments when parent_id is set
+//	@Tags			comments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"Post ID"
+//	@Param			payload	body		CreateCommentPayload	true	"Comment payload"
+//	@Success		200		{object}	domain.Comment
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		429		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/posts/{id}/comments [post]
+func (app *commentsApp) createCommentHandler(ctx context.Context, r *http.Request) web.Encoder {
+	var payload CreateCommentPayload
+
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "invalid payload %s", err.Error())
+	}
+
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, err.Error())
+	}
+
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	post, err := getPostFromContext(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	comment := &domain.Comment{
+		PostID:  post.ID,
+		UserID:  userID,
+		Content: payload.Content,
+	}
+
+	if payload.ParentID != nil {
+		parent, err := app.comments.GetByID(ctx, *payload.ParentID)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrNotFound):
+				return errs.Newf(errs.InvalidArgument, "parent comment %d not found", *payload.ParentID)
+			default:
+				return errs.New(errs.Internal, err)
+			}
+		}
+		if parent.PostID != post.ID {
+			return errs.Newf(errs.InvalidArgument, "parent comment %d belongs to another post", parent.ID)
+		}
+		if comment, err = parent.Reply(userID, payload.Content); err != nil {
+			return errs.New(errs.FailedPrecondition, err)
+		}
+	}
+
+	if err := app.comments.Create(ctx, comment); err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	return web.NewResponse(comment)
+}
+
+// GetComments godoc
+//
+//	@Summary		Fetches the comments of a post
+//	@Description	Fetches the top level comments of a post, newest first
+//	@Tags			comments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int		true	"Post ID"
+//	@Param			limit	query		int		false	"Limit"
+//	@Param			cursor	query		string	false	"Cursor of the next page"
+//	@Success		200		{object}	CommentsData
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/posts/{id}/comments [get]
+func (app *commentsApp) getCommentsHandler(ctx context.Context, r *http.Request) web.Encoder {
+	query, queryErr := app.parseCommentsQuery(ctx, r)
+	if queryErr != nil {
+		return queryErr
+	}
+
+	post, err := getPostFromContext(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	page, err := app.comments.GetPageByPostID(ctx, post.ID, query)
+	if err != nil {
+		return errs.Newf(errs.Internal, "could not get comments %s", err.Error())
+	}
+
+	return app.pageResponse(page)
+}
+
+// GetReplies godoc
+//
+//	@Summary		Fetches the replies to a comment
+//	@Description	Fetches the direct replies to a comment, oldest first
+//	@Tags			comments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int		true	"Comment ID"
+//	@Param			limit	query		int		false	"Limit"
+//	@Param			cursor	query		string	false	"Cursor of the next page"
+//	@Success		200		{object}	CommentsData
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/comments/{id}/replies [get]
+func (app *commentsApp) getRepliesHandler(ctx context.Context, r *http.Request) web.Encoder {
+	query, queryErr := app.parseCommentsQuery(ctx, r)
+	if queryErr != nil {
+		return queryErr
+	}
+
+	comment, err := getCommentFromContext(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	page, err := app.comments.GetRepliesPage(ctx, comment.ID, query)
+	if err != nil {
+		return errs.Newf(errs.Internal, "could not get replies %s", err.Error())
+	}
+
+	return app.pageResponse(page)
+}
+
+// GetThread godoc
+//
+//	@Summary		Fetches the thread of a comment
+//	@Description	Fetches the replies to a comment at every depth, each reply after its parent and the siblings oldest first
+//	@Tags			comments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Comment ID"
+//	@Success		200	{object}	domain.CommentThread
+//	@Failure		401	{object}	error
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/comments/{id}/thread [get]
+func (app *commentsApp) getThreadHandler(ctx context.Context, r *http.Request) web.Encoder {
+	viewerID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	comment, err := getCommentFromContext(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	thread, err := app.comments.GetThread(ctx, comment.ID, viewerID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "could not get thread %s", err.Error())
+	}
+
+	return web.NewResponse(thread)
+}
+
+// UpdateComment godoc
+//
+//	@Summary		Updates a comment
+//	@Description	Updates a comment by ID, allowed for the owner and moderators
+//	@Tags			comments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"Comment ID"
+//	@Param			payload	body		UpdateCommentPayload	true	"Comment payload"
+//	@Success		200		{object}	domain.Comment
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		403		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/comments/{id} [patch]
+func (app *commentsApp) updateCommentHandler(ctx context.Context, r *http.Request) web.Encoder {
+	var payload UpdateCommentPayload
+
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "invalid payload %s", err.Error())
+	}
+
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, err.Error())
+	}
+
+	comment, err := getCommentFromContext(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	comment.Content = payload.Content
+
+	if err := app.comments.Update(ctx, comment); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+
+	return web.NewResponse(comment)
+}
+
+// DeleteComment godoc
+//
+//	@Summary		Deletes a comment
+//	@Description	Deletes a comment by ID, allowed for the owner and moderators
+//	@Tags			comments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Comment ID"
+//	@Success		204	{string}	No	Content
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/comments/{id} [delete]
+func (app *commentsApp) deleteCommentHandler(ctx context.Context, r *http.Request) web.Encoder {
+	comment, err := getCommentFromContext(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	if err := app.comments.Delete(ctx, comment.ID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+
+	// Only moderation is audited, not users deleting their own comments.
+	if userID != comment.UserID {
+		app.audit.Record(ctx, domain.AuditEvent{
+			ActorID:    userID,
+			Action:     domain.AuditCommentDeleted,
+			TargetType: domain.AuditTargetComment,
+			TargetID:   comment.ID,
+			Metadata:   map[string]any{"owner_id": comment.UserID, "post_id": comment.PostID},
+		})
+	}
+
+	return web.NewNoResponse()
+}
+
+// ReactToComment godoc
+//
+//	@Summary		Reacts to a comment
+//	@Description	Adds a reaction of the authenticated user to a comment, reacting twice is a no-op
+//	@Tags			comments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int		true	"Comment ID"
+//	@Param			type	path		string	true	"Reaction type"	Enums(like, love, laugh, wow, sad, angry)
+//	@Success		204		{string}	No		Content
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/comments/{id}/reactions/{type} [put]
+func (app *commentsApp) reactToCommentHandler(ctx context.Context, r *http.Request) web.Encoder {
+	reaction := domain.ReactionType(web.Param(r, "type"))
+	if !reaction.IsValid() {
+		return errs.Newf(errs.InvalidArgument, "unknown reaction %q", reaction)
+	}
+
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	comment, err := getCommentFromContext(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	if err := app.reactions.AddToComment(ctx, comment.ID, userID, reaction); err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	return web.NewNoResponse()
+}
+
+// UnreactToComment godoc
+//
+//	@Summary		Removes a reaction from a comment
+//	@Description	Removes a reaction of the authenticated user from a comment
+//	@Tags			comments
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int		true	"Comment ID"
+//	@Param			type	path		string	true	"Reaction type"	Enums(like, love, laugh, wow, sad, angry)
+//	@Success		204		{string}	No		Content
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/comments/{id}/reactions/{type} [delete]
+func (app *commentsApp) unreactToCommentHandler(ctx context.Context, r *http.Request) web.Encoder {
+	reaction := domain.ReactionType(web.Param(r, "type"))
+	if !reaction.IsValid() {
+		return errs.Newf(errs.InvalidArgument, "unknown reaction %q", reaction)
+	}
+
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	comment, err := getCommentFromContext(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	if err := app.reactions.RemoveFromComment(ctx, comment.ID, userID, reaction); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+
+	return web.NewNoResponse()
+}
+
+func (app *commentsApp) parseCommentsQuery(ctx context.Context, r *http.Request) (domain.CommentsQuery, *errs.Error) {
+	viewerID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return domain.CommentsQuery{}, errs.New(errs.Internal, err)
+	}
+
+	query := domain.CommentsQuery{
+		Limit:    20,
+		ViewerID: viewerID,
+	}
+	parseCommentsQuery(&query, r)
+
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		var position domain.Cursor
+		if err := app.cursors.Decode(c, &position); err != nil {
+			return query, errs.Newf(errs.InvalidArgument, "cursor: %s", err.Error())
+		}
+		query.Cursor = &position
+	}
+
+	if err := domain.Validate.Struct(query); err != nil {
+		return query, errs.Newf(errs.InvalidArgument, err.Error())
+	}
+
+	return query, nil
+}
+
+func (app *commentsApp) pageResponse(page domain.CommentsPage) web.Encoder {
+	var nextCursor string
+	if page.NextCursor != nil {
+		var err error
+		if nextCursor, err = app.cursors.Encode(page.NextCursor); err != nil {
+			return errs.Newf(errs.Internal, "could not encode comments cursor %s", err.Error())
+		}
+	}
+
+	return web.NewPageResponse(page.Comments, nextCursor)
+}
+
+func (app *commentsApp) postContextMiddleware() web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			postID, err := strconv.ParseInt(web.Param(r, "postId"), 10, 64)
+			if err != nil {
+				return errs.Newf(errs.InvalidArgument, "invalid postId %s", err.Error())
+			}
+			post, err := app.posts.GetByID(ctx, postID)
+			if err != nil {
+				switch {
+				case errors.Is(err, domain.ErrNotFound):
+					return errs.New(errs.NotFound, domain.ErrNotFound)
+				default:
+					return errs.New(errs.Internal, err)
+				}
+			}
+			return next(context.WithValue(ctx, postCtx, post), r)
+		}
+		return h
+	}
+	return m
+}
+
+func (app *commentsApp) commentContextMiddleware() web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			commentID, err := strconv.ParseInt(web.Param(r, "commentId"), 10, 64)
+			if err != nil {
+				return errs.Newf(errs.InvalidArgument, "invalid commentId %s", err.Error())
+			}
+			comment, err := app.comments.GetByID(ctx, commentID)
+			if err != nil {
+				switch {
+				case errors.Is(err, domain.ErrNotFound):
+					return errs.New(errs.NotFound, domain.ErrNotFound)
+				default:
+					return errs.New(errs.Internal, err)
+				}
+			}
+			return next(context.WithValue(ctx, commentCtx, comment), r)
+		}
+		return h
+	}
+	return m
+}
+
+func getPostFromContext(ctx context.Context) (*domain.Post, error) {
+	post, ok := ctx.Value(postCtx).(*domain.Post)
+	if !ok {
+		return nil, errors.New("no post found in context")
+	}
+	return post, nil
+}
+
+func getCommentFromContext(ctx context.Context) (*domain.Comment, error) {
+	comment, ok := ctx.Value(commentCtx).(*domain.Comment)
+	if !ok {
+		return nil, errors.New("no comment found in context")
+	}
+	return comment, nil
+}
+
+func commentOwner(ctx context.Context) (int64, error) {
+	comment, err := getCommentFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return comment.UserID, nil
+}
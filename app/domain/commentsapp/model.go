@@ -0,0 +1,31 @@
+package commentsapp
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/sergdort/Social/business/domain"
+)
+
+type CreateCommentPayload struct {
+	Content  string `json:"content" validate:"required,max=1000"`
+	ParentID *int64 `json:"parent_id" validate:"omitempty,gte=1"`
+}
+
+type UpdateCommentPayload struct {
+	Content string `json:"content" validate:"required,max=1000"`
+}
+
+// Needed for swagger docs, should not be used
+type CommentsData struct {
+	Data       []domain.Comment `json:"data"`
+	NextCursor string           `json:"next_cursor" example:"eyJjcmVhdGVkX2F0Ijo...Rk"`
+}
+
+func parseCommentsQuery(q *domain.CommentsQuery, r *http.Request) {
+	qs := r.URL.Query()
+
+	if limit, err := strconv.Atoi(qs.Get("limit")); err == nil {
+		q.Limit = limit
+	}
+}
@@ -0,0 +1,55 @@
+package commentsapp
+
+import (
+	"github.com/sergdort/Social/app/shared/mid"
+	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/cursor"
+	"github.com/sergdort/Social/foundation/ratelimit"
+	"github.com/sergdort/Social/foundation/web"
+	"net/http"
+	"time"
+)
+
+type Config struct {
+	Auth         *domain.AuthUseCase
+	Users        *domain.UsersUseCase
+	Authorizer   *domain.Authorizer
+	Audit        domain.AuditLogger
+	PostsRepo    domain.PostsRepository
+	CommentsRepo domain.CommentsRepository
+	Reactions    domain.ReactionsRepository
+	Cursors      *cursor.Codec
+	RateLimits   ratelimit.Store
+}
+
+func Routes(app *web.App, config Config) {
+	const version = "v1"
+
+	api := commentsApp{
+		posts:     config.PostsRepo,
+		comments:  config.CommentsRepo,
+		reactions: config.Reactions,
+		audit:     config.Audit,
+		cursors:   config.Cursors,
+	}
+	auth := mid.Bearer(config.Auth)
+	user := mid.LoadUser(config.Users)
+	postContext := api.postContextMiddleware()
+	commentContext := api.commentContextMiddleware()
+	createLimit := mid.RateLimit(config.RateLimits, "comments", ratelimit.Limit{
+		Algorithm: ratelimit.TokenBucket, Requests: 120, Period: time.Hour, Burst: 20,
+	})
+	canCreate := mid.Authorize(config.Authorizer, domain.PermissionCommentsCreate, nil)
+	canUpdate := mid.Authorize(config.Authorizer, domain.PermissionCommentsUpdate, commentOwner)
+	canDelete := mid.Authorize(config.Authorizer, domain.PermissionCommentsDelete, commentOwner)
+	canReact := mid.Authorize(config.Authorizer, domain.PermissionReactionsWrite, nil)
+
+	app.HandlerFunc(http.MethodPost, version, "/posts/{postId}/comments", api.createCommentHandler, auth, user, createLimit, postContext, canCreate)
+	app.HandlerFunc(http.MethodGet, version, "/posts/{postId}/comments", api.getCommentsHandler, auth, user, postContext)
+	app.HandlerFunc(http.MethodGet, version, "/comments/{commentId}/replies", api.getRepliesHandler, auth, user, commentContext)
+	app.HandlerFunc(http.MethodGet, version, "/comments/{commentId}/thread", api.getThreadHandler, auth, user, commentContext)
+	app.HandlerFunc(http.MethodPatch, version, "/comments/{commentId}", api.updateCommentHandler, auth, user, commentContext, canUpdate)
+	app.HandlerFunc(http.MethodDelete, version, "/comments/{commentId}", api.deleteCommentHandler, auth, user, commentContext, canDelete)
+	app.HandlerFunc(http.MethodPut, version, "/comments/{commentId}/reactions/{type}", api.reactToCommentHandler, auth, user, commentContext, canReact)
+	app.HandlerFunc(http.MethodDelete, version, "/comments/{commentId}/reactions/{type}", api.unreactToCommentHandler, auth, user, commentContext, canReact)
+}
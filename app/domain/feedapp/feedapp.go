@@ -8,12 +8,14 @@ import (
 
 	"github.com/sergdort/Social/app/shared/errs"
 	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/cursor"
 	"github.com/sergdort/Social/foundation/slices"
 	"github.com/sergdort/Social/foundation/web"
 )
 
 type feedApp struct {
 	feedUseCase domain.FeedRepository
+	cursors     *cursor.Codec
 }
 
 // getUserFeedHandler godoc
@@ -23,11 +25,12 @@ type feedApp struct {
 //	@Tags			feed
 //	@Accept			json
 //	@Produce		json
-//	@Param			since	query		string	false	"Since"
-//	@Param			until	query		string	false	"Until"
+//	@Param			since	query		string	false	"Oldest creation time, YYYY-MM-DD HH:MM:SS UTC"
+//	@Param			until	query		string	false	"Newest creation time, YYYY-MM-DD HH:MM:SS UTC"
 //	@Param			limit	query		int		false	"Limit"
-//	@Param			offset	query		int		false	"Offset"
-//	@Param			sort_by	query		string	false	"Sort"
+//	@Param			offset	query		int		false	"Offset, prefer cursor"
+//	@Param			cursor	query		string	false	"Cursor of the next page"
+//	@Param			sort_by	query		string	false	"Sort"	Enums(asc, desc)
 //	@Param			tags	query		string	false	"Tags"
 //	@Param			search	query		string	false	"Search"
 //	@Success		200		{object}	FeedData
@@ -44,6 +47,14 @@ func (app *feedApp) getFeedHandler(ctx context.Context, r *http.Request) web.Enc
 	}
 	parsePaginatedFeedQuery(&query, r)
 
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		var position domain.Cursor
+		if err := app.cursors.Decode(c, &position); err != nil {
+			return errs.Newf(errs.InvalidArgument, "cursor: %s", err.Error())
+		}
+		query.Cursor = &position
+	}
+
 	if err := domain.Validate.Struct(query); err != nil {
 		return errs.Newf(errs.InvalidArgument, err.Error())
 	}
@@ -57,6 +68,13 @@ func (app *feedApp) getFeedHandler(ctx context.Context, r *http.Request) web.Enc
 		return errs.Newf(errs.Internal, "could not get user feed %s", err.Error())
 	}
 
-	feedItems := slices.Map(feed, toPostFeedItem)
-	return web.NewResponse(feedItems)
+	var nextCursor string
+	if feed.NextCursor != nil {
+		if nextCursor, err = app.cursors.Encode(feed.NextCursor); err != nil {
+			return errs.Newf(errs.Internal, "could not encode feed cursor %s", err.Error())
+		}
+	}
+
+	feedItems := slices.Map(feed.Posts, toPostFeedItem)
+	return web.NewPageResponse(feedItems, nextCursor)
 }
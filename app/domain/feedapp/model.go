@@ -4,26 +4,36 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/sergdort/Social/business/domain"
 )
 
 type PostFeedItem struct {
-	ID            int64    `json:"id" example:"117"`
-	Content       string   `json:"content" example:"I will not become a queen of ashes."`
-	Title         string   `json:"title" example:"The King of Ashes"`
-	UserID        int      `json:"user_id" example:"38"`
-	CreatedAt     string   `json:"created_at" example:"2025-03-19 10:08:25 +0000 UTC"`
-	UpdatedAt     string   `json:"updated_at" example:"2025-03-19 10:08:25 +0000 UTC"`
-	Tags          []string `json:"tags" example:"Dothraki,Lannister,BattleOfBastards,KingsLanding"`
-	CommentsCount int64    `json:"comments_count" example:"4"`
-	User          FeedUser `json:"user"`
+	ID            int64         `json:"id" example:"117"`
+	Content       string        `json:"content" example:"I will not become a queen of ashes."`
+	Title         string        `json:"title" example:"The King of Ashes"`
+	UserID        int           `json:"user_id" example:"38"`
+	CreatedAt     string        `json:"created_at" example:"2025-03-19 10:08:25 +0000 UTC"`
+	UpdatedAt     string        `json:"updated_at" example:"2025-03-19 10:08:25 +0000 UTC"`
+	Tags          []string      `json:"tags" example:"Dothraki,Lannister,BattleOfBastards,KingsLanding"`
+	CommentsCount int64         `json:"comments_count" example:"4"`
+	Reactions     FeedReactions `json:"reactions"`
+	User          FeedUser      `json:"user"`
+}
+
+// FeedReactions are the reaction counts of a post and the reactions the
+// authenticated user left on it.
+type FeedReactions struct {
+	Total       int64            `json:"total" example:"5"`
+	Counts      map[string]int64 `json:"counts" example:"like:3,love:2"`
+	ReactedByMe bool             `json:"reacted_by_me" example:"true"`
+	Mine        []string         `json:"mine" example:"like"`
 }
 
 // Needed for swagger docs, should not be used
 type FeedData struct {
-	Data []PostFeedItem `json:"data"`
+	Data       []PostFeedItem `json:"data"`
+	NextCursor string         `json:"next_cursor" example:"eyJjcmVhdGVkX2F0Ijo...Rk"`
 }
 
 type FeedUser struct {
@@ -44,9 +54,8 @@ func parsePaginatedFeedQuery(fq *domain.PaginatedFeedQuery, r *http.Request) {
 			fq.Offset = offset
 		}
 	}
-	sortBy := qs.Get("sort_by")
-	if sortBy == "ask" || sortBy == "desc" {
-		fq.SortBy = sortBy
+	if sortBy := qs.Get("sort_by"); sortBy != "" {
+		fq.SortBy = strings.ToLower(sortBy)
 	}
 
 	tags := qs.Get("tags")
@@ -59,23 +68,10 @@ func parsePaginatedFeedQuery(fq *domain.PaginatedFeedQuery, r *http.Request) {
 		fq.Search = search
 	}
 
-	since := qs.Get("since")
-	if since != "" {
-		fq.Since = parseTime(since)
-	}
-
-	until := qs.Get("until")
-	if until != "" {
-		fq.Until = parseTime(until)
-	}
-}
-
-func parseTime(since string) string {
-	t, err := time.Parse(time.DateTime, since)
-	if err != nil {
-		return ""
-	}
-	return t.Format(time.DateTime)
+	// Since and Until are validated by domain.Validate, malformed values
+	// must be reported rather than dropped.
+	fq.Since = qs.Get("since")
+	fq.Until = qs.Get("until")
 }
 
 func toPostFeedItem(p domain.PostWithMetadata) PostFeedItem {
@@ -88,10 +84,27 @@ func toPostFeedItem(p domain.PostWithMetadata) PostFeedItem {
 		UpdatedAt:     p.UpdatedAt,
 		Tags:          p.Tags,
 		CommentsCount: p.CommentsCount,
+		Reactions:     toFeedReactions(p.Reactions),
 		User:          toFeedUser(p.User),
 	}
 }
 
+func toFeedReactions(r domain.Reactions) FeedReactions {
+	reactions := FeedReactions{
+		Total:       r.Total,
+		Counts:      make(map[string]int64, len(r.Counts)),
+		ReactedByMe: r.ReactedByMe,
+		Mine:        make([]string, 0, len(r.Mine)),
+	}
+	for reaction, count := range r.Counts {
+		reactions.Counts[string(reaction)] = count
+	}
+	for _, reaction := range r.Mine {
+		reactions.Mine = append(reactions.Mine, string(reaction))
+	}
+	return reactions
+}
+
 func toFeedUser(u domain.User) FeedUser {
 	return FeedUser{
 		ID:       int(u.ID),
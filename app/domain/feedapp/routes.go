@@ -3,20 +3,24 @@ package feedapp
 import (
 	"github.com/sergdort/Social/app/shared/mid"
 	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/cursor"
 	"github.com/sergdort/Social/foundation/web"
 	"net/http"
 )
 
 type Config struct {
 	Auth        *domain.AuthUseCase
+	Users       *domain.UsersUseCase
 	FeedUseCase domain.FeedRepository
+	Cursors     *cursor.Codec
 }
 
 func Routes(app *web.App, config Config) {
 	const version = "v1"
 
-	api := feedApp{feedUseCase: config.FeedUseCase}
+	api := feedApp{feedUseCase: config.FeedUseCase, cursors: config.Cursors}
 	auth := mid.Bearer(config.Auth)
+	user := mid.LoadUser(config.Users)
 
-	app.HandlerFunc(http.MethodGet, version, "/user/feed", api.getFeedHandler, auth)
+	app.HandlerFunc(http.MethodGet, version, "/user/feed", api.getFeedHandler, auth, user)
 }
@@ -1,5 +1,7 @@
 package postsapp
 
+import "github.com/sergdort/Social/business/domain"
+
 type CreatePostPayload struct {
 	Title   string   `json:"title" validate:"required,max=100"`
 	Content string   `json:"content" validate:"required,max=1000"`
@@ -9,6 +11,21 @@ type CreatePostPayload struct {
 type UpdatePostPayload struct {
 	Title   *string `json:"title" validate:"omitempty,max=100"`
 	Content *string `json:"content" validate:"omitempty,max=1000"`
+	// Version the client last read, the update is rejected when the post
+	// changed since. Defaults to the current version of the post.
+	Version *int64 `json:"version" validate:"omitempty,gte=0"`
+}
+
+func (p *UpdatePostPayload) update(post *domain.Post) {
+	if p.Title != nil {
+		post.Title = *p.Title
+	}
+	if p.Content != nil {
+		post.Content = *p.Content
+	}
+	if p.Version != nil {
+		post.Version = *p.Version
+	}
 }
 
 type CreatePostResponse struct {
@@ -13,7 +13,9 @@ import (
 )
 
 type postsApp struct {
-	repo domain.PostsRepository
+	repo      domain.PostsRepository
+	reactions domain.ReactionsRepository
+	audit     domain.AuditLogger
 }
 
 type postKey string
@@ -31,6 +33,7 @@ const postCtx postKey = "post"
 //	@Success		201		{object}	domain.Post
 //	@Failure		400		{object}	error
 //	@Failure		401		{object}	error
+//	@Failure		429		{object}	error
 //	@Failure		500		{object}	error
 //	@Security		ApiKeyAuth
 //	@Router			/posts/ [post]
@@ -87,6 +90,188 @@ func (app *postsApp) getPostHandler(ctx context.Context, r *http.Request) web.En
 	return web.NewResponse(post)
 }
 
+// UpdatePost godoc
+//
+//	@Summary		Updates a post
+//	@Description	Updates a post by ID, allowed for the owner and moderators
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"Post ID"
+//	@Param			payload	body		UpdatePostPayload	true	"Post payload"
+//	@Success		200		{object}	domain.Post
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		403		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		409		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/posts/{id} [patch]
+func (app *postsApp) updatePostHandler(ctx context.Context, r *http.Request) web.Encoder {
+	var payload UpdatePostPayload
+
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "invalid payload %s", err.Error())
+	}
+
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, err.Error())
+	}
+
+	post, err := getPostFromContext(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, err.Error())
+	}
+
+	payload.update(post)
+
+	if err := app.repo.Update(ctx, post); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		case errors.Is(err, domain.ErrEditConflict):
+			return errs.Newf(errs.Aborted, "post was modified, fetch it again and retry")
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+
+	return web.NewResponse(post)
+}
+
+// DeletePost godoc
+//
+//	@Summary		Deletes a post
+//	@Description	Deletes a post by ID, allowed for the owner and admins
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Post ID"
+//	@Success		204	{string}	No	Content
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/posts/{id} [delete]
+func (app *postsApp) deletePostHandler(ctx context.Context, r *http.Request) web.Encoder {
+	post, err := getPostFromContext(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, err.Error())
+	}
+
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	if err := app.repo.Delete(ctx, post.ID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+
+	// Only moderation is audited, not users deleting their own posts.
+	if userID != post.UserID {
+		app.audit.Record(ctx, domain.AuditEvent{
+			ActorID:    userID,
+			Action:     domain.AuditPostDeleted,
+			TargetType: domain.AuditTargetPost,
+			TargetID:   post.ID,
+			Metadata:   map[string]any{"owner_id": post.UserID, "title": post.Title},
+		})
+	}
+
+	return web.NewNoResponse()
+}
+
+// ReactToPost godoc
+//
+//	@Summary		Reacts to a post
+//	@Description	Adds a reaction of the authenticated user to a post, reacting twice is a no-op
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int		true	"Post ID"
+//	@Param			type	path		string	true	"Reaction type"	Enums(like, love, laugh, wow, sad, angry)
+//	@Success		204		{string}	No		Content
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/posts/{id}/reactions/{type} [put]
+func (app *postsApp) reactToPostHandler(ctx context.Context, r *http.Request) web.Encoder {
+	reaction := domain.ReactionType(web.Param(r, "type"))
+	if !reaction.IsValid() {
+		return errs.Newf(errs.InvalidArgument, "unknown reaction %q", reaction)
+	}
+
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, err.Error())
+	}
+
+	post, err := getPostFromContext(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, err.Error())
+	}
+
+	if err := app.reactions.AddToPost(ctx, post.ID, userID, reaction); err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	return web.NewNoResponse()
+}
+
+// UnreactToPost godoc
+//
+//	@Summary		Removes a reaction from a post
+//	@Description	Removes a reaction of the authenticated user from a post
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int		true	"Post ID"
+//	@Param			type	path		string	true	"Reaction type"	Enums(like, love, laugh, wow, sad, angry)
+//	@Success		204		{string}	No		Content
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		404		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/posts/{id}/reactions/{type} [delete]
+func (app *postsApp) unreactToPostHandler(ctx context.Context, r *http.Request) web.Encoder {
+	reaction := domain.ReactionType(web.Param(r, "type"))
+	if !reaction.IsValid() {
+		return errs.Newf(errs.InvalidArgument, "unknown reaction %q", reaction)
+	}
+
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, err.Error())
+	}
+
+	post, err := getPostFromContext(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, err.Error())
+	}
+
+	if err := app.reactions.RemoveFromPost(ctx, post.ID, userID, reaction); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+
+	return web.NewNoResponse()
+}
+
 func (app *postsApp) postsContextMiddleware() web.MidFunc {
 	m := func(next web.HandlerFunc) web.HandlerFunc {
 		h := func(ctx context.Context, r *http.Request) web.Encoder {
@@ -117,3 +302,11 @@ func getPostFromContext(ctx context.Context) (*domain.Post, error) {
 	}
 	return post, nil
 }
+
+func postOwner(ctx context.Context) (int64, error) {
+	post, err := getPostFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return post.UserID, nil
+}
@@ -3,22 +3,41 @@ package postsapp
 import (
 	"github.com/sergdort/Social/app/shared/mid"
 	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/ratelimit"
 	"github.com/sergdort/Social/foundation/web"
 	"net/http"
+	"time"
 )
 
 type Config struct {
-	Auth      *domain.AuthUseCase
-	PostsRepo domain.PostsRepository
+	Auth       *domain.AuthUseCase
+	Users      *domain.UsersUseCase
+	Authorizer *domain.Authorizer
+	PostsRepo  domain.PostsRepository
+	Reactions  domain.ReactionsRepository
+	Audit      domain.AuditLogger
+	RateLimits ratelimit.Store
 }
 
 func Routes(app *web.App, config Config) {
 	const version = "v1"
 
-	api := postsApp{repo: config.PostsRepo}
+	api := postsApp{repo: config.PostsRepo, reactions: config.Reactions, audit: config.Audit}
 	auth := mid.Bearer(config.Auth)
+	user := mid.LoadUser(config.Users)
 	postContext := api.postsContextMiddleware()
+	createLimit := mid.RateLimit(config.RateLimits, "posts", ratelimit.Limit{
+		Algorithm: ratelimit.TokenBucket, Requests: 30, Period: time.Hour, Burst: 10,
+	})
+	canCreate := mid.Authorize(config.Authorizer, domain.PermissionPostsCreate, nil)
+	canUpdate := mid.Authorize(config.Authorizer, domain.PermissionPostsUpdate, postOwner)
+	canDelete := mid.Authorize(config.Authorizer, domain.PermissionPostsDelete, postOwner)
+	canReact := mid.Authorize(config.Authorizer, domain.PermissionReactionsWrite, nil)
 
-	app.HandlerFunc(http.MethodPost, version, "/posts", api.createPostsHandler, auth)
-	app.HandlerFunc(http.MethodGet, version, "/posts/{postId}", api.getPostHandler, auth, postContext)
+	app.HandlerFunc(http.MethodPost, version, "/posts", api.createPostsHandler, auth, user, createLimit, canCreate)
+	app.HandlerFunc(http.MethodGet, version, "/posts/{postId}", api.getPostHandler, auth, user, postContext)
+	app.HandlerFunc(http.MethodPatch, version, "/posts/{postId}", api.updatePostHandler, auth, user, postContext, canUpdate)
+	app.HandlerFunc(http.MethodDelete, version, "/posts/{postId}", api.deletePostHandler, auth, user, postContext, canDelete)
+	app.HandlerFunc(http.MethodPut, version, "/posts/{postId}/reactions/{type}", api.reactToPostHandler, auth, user, postContext, canReact)
+	app.HandlerFunc(http.MethodDelete, version, "/posts/{postId}/reactions/{type}", api.unreactToPostHandler, auth, user, postContext, canReact)
 }
@@ -6,22 +6,32 @@ import (
 )
 
 type User struct {
-	ID        int64  `json:"id"`
-	Username  string `json:"username"`
-	Email     string `json:"email"`
-	CreatedAt string `json:"created_at"`
-	IsActive  bool   `json:"is_active"`
-	Role      string `json:"role"`
+	ID          int64  `json:"id"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	CreatedAt   string `json:"created_at"`
+	IsActive    bool   `json:"is_active"`
+	Role        string `json:"role"`
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	Location    string `json:"location"`
+	Website     string `json:"website"`
+	AvatarURL   string `json:"avatar_url"`
 }
 
 func toAppUser(domain *domain.User) User {
 	return User{
-		ID:        domain.ID,
-		Username:  domain.Username,
-		Email:     domain.Email,
-		CreatedAt: domain.CreatedAt,
-		IsActive:  domain.IsActive,
-		Role:      domain.Role.Name,
+		ID:          domain.ID,
+		Username:    domain.Username,
+		Email:       domain.Email,
+		CreatedAt:   domain.CreatedAt,
+		IsActive:    domain.IsActive,
+		Role:        domain.Role.Name,
+		DisplayName: domain.Profile.DisplayName,
+		Bio:         domain.Profile.Bio,
+		Location:    domain.Profile.Location,
+		Website:     domain.Profile.Website,
+		AvatarURL:   domain.Profile.AvatarURL,
 	}
 }
 
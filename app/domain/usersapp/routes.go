@@ -8,18 +8,30 @@ import (
 )
 
 type Config struct {
-	Auth    *domain.AuthUseCase
-	UseCase *domain.UsersUseCase
+	Auth       *domain.AuthUseCase
+	Authorizer *domain.Authorizer
+	UseCase    *domain.UsersUseCase
 }
 
 func Routes(app *web.App, config Config) {
 	const version = "v1"
 
-	api := newApp(config.UseCase)
+	api := newApp(config.UseCase, config.Auth)
 	auth := mid.Bearer(config.Auth)
+	user := mid.LoadUser(config.UseCase)
+	interactive := mid.DenyPersonalTokens()
 	userContext := api.userContextMiddleware(config.UseCase)
+	canFollow := mid.Authorize(config.Authorizer, domain.PermissionFollowsWrite, nil)
 
-	app.HandlerFunc(http.MethodGet, version, "/users/{userID}", api.getUserHandler, auth, userContext)
-	app.HandlerFunc(http.MethodPut, version, "/users/{userID}/follow", api.followUserHandler, auth, userContext)
-	app.HandlerFunc(http.MethodPut, version, "/users/{userID}/unfollow", api.unfollowUserHandler, auth, userContext)
+	app.HandlerFunc(http.MethodPut, version, "/users/activate/{token}", api.activateUserHandler)
+	app.HandlerFunc(http.MethodPatch, version, "/users/me", api.updateProfileHandler, auth, interactive, user)
+	app.HandlerFunc(http.MethodGet, version, "/users/me/tokens", api.listPersonalTokensHandler, auth, interactive, user)
+	app.HandlerFunc(http.MethodPost, version, "/users/me/tokens", api.createPersonalTokenHandler, auth, interactive, user)
+	app.HandlerFunc(http.MethodDelete, version, "/users/me/tokens/{tokenID}", api.revokePersonalTokenHandler, auth, interactive, user)
+	app.HandlerFunc(http.MethodGet, version, "/users/me/sessions", api.listSessionsHandler, auth, interactive, user)
+	app.HandlerFunc(http.MethodDelete, version, "/users/me/sessions", api.revokeOtherSessionsHandler, auth, interactive, user)
+	app.HandlerFunc(http.MethodDelete, version, "/users/me/sessions/{sessionID}", api.revokeSessionHandler, auth, interactive, user)
+	app.HandlerFunc(http.MethodGet, version, "/users/{userID}", api.getUserHandler, auth, user, userContext)
+	app.HandlerFunc(http.MethodPut, version, "/users/{userID}/follow", api.followUserHandler, auth, user, userContext, canFollow)
+	app.HandlerFunc(http.MethodPut, version, "/users/{userID}/unfollow", api.unfollowUserHandler, auth, user, userContext, canFollow)
 }
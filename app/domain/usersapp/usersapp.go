@@ -6,9 +6,11 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/google/uuid"
 	"github.com/sergdort/Social/app/shared/errs"
 	"github.com/sergdort/Social/app/shared/mid"
 	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/jsn"
 	"github.com/sergdort/Social/foundation/web"
 )
 
@@ -26,11 +28,13 @@ type FollowUser struct {
 
 type userApp struct {
 	usersUseCase *domain.UsersUseCase
+	auth         *domain.AuthUseCase
 }
 
-func newApp(usersUseCase *domain.UsersUseCase) *userApp {
+func newApp(usersUseCase *domain.UsersUseCase, auth *domain.AuthUseCase) *userApp {
 	return &userApp{
 		usersUseCase: usersUseCase,
+		auth:         auth,
 	}
 }
 
@@ -57,6 +61,47 @@ func (app *userApp) getUserHandler(ctx context.Context, r *http.Request) web.Enc
 	return web.Response[User]{Data: toAppUser(user)}
 }
 
+// UpdateProfile godoc
+//
+//	@Summary		Updates the profile
+//	@Description	Updates the profile of the authenticated user. Only the fields in the payload change, an empty string clears one.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			payload	body		domain.UpdateProfilePayload	true	"Profile fields"
+//	@Success		200		{object}	User
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		403		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/users/me [patch]
+func (app *userApp) updateProfileHandler(ctx context.Context, r *http.Request) web.Encoder {
+	var payload domain.UpdateProfilePayload
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	usr, err := mid.GetUser(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	user, err := app.usersUseCase.UpdateProfile(ctx, usr.ID, payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errs.Newf(errs.NotFound, "user not found")
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+	return web.Response[User]{Data: toAppUser(user)}
+}
+
 // FollowUser godoc
 //
 //	@Summary		Follows a user
@@ -110,11 +155,220 @@ func (app *userApp) unfollowUserHandler(ctx context.Context, r *http.Request) we
 	return web.NewNoResponse()
 }
 
+// ActivateUser godoc
+//
+//	@Summary		Activates a user
+//	@Description	Activates a user with the token of their invitation
+//	@Tags			users
+//	@Param			token	path	string	true	"Invitation token"
+//	@Success		204
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Router			/users/activate/{token} [put]
 func (app *userApp) activateUserHandler(ctx context.Context, r *http.Request) web.Encoder {
 	token := web.Param(r, "token")
 
 	err := app.usersUseCase.ActivateUser(ctx, token)
 	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errs.Newf(errs.NotFound, "invitation not found or expired")
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+	return web.NewNoResponse()
+}
+
+// CreatePersonalToken godoc
+//
+//	@Summary		Creates a personal access token
+//	@Description	Creates a personal access token for scripts and bots, used in place of a JWT. The token is only returned by this response. Scopes limit the permissions it grants.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			payload	body		domain.CreatePersonalTokenPayload	true	"Token"
+//	@Success		200		{object}	domain.NewPersonalToken
+//	@Failure		400		{object}	error
+//	@Failure		401		{object}	error
+//	@Failure		403		{object}	error
+//	@Failure		500		{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/tokens [post]
+func (app *userApp) createPersonalTokenHandler(ctx context.Context, r *http.Request) web.Encoder {
+	var payload domain.CreatePersonalTokenPayload
+	if err := jsn.ReadJSON(r, &payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+	if err := domain.Validate.Struct(payload); err != nil {
+		return errs.Newf(errs.InvalidArgument, "Bad Request %s", err.Error())
+	}
+
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	token, err := app.auth.CreatePersonalToken(ctx, userID, payload)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidScope):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, domain.ErrInvalidTokenExpiry):
+			return errs.Newf(errs.InvalidArgument, "expires_at must be in the future, within %s", domain.MaxPersonalTokenLifetime)
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+	return web.NewResponse(token)
+}
+
+// ListPersonalTokens godoc
+//
+//	@Summary		Lists personal access tokens
+//	@Description	Lists the personal access tokens of the authenticated user, without their value
+//	@Tags			users
+//	@Produce		json
+//	@Success		200	{object}	[]domain.PersonalToken
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/tokens [get]
+func (app *userApp) listPersonalTokensHandler(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	tokens, err := app.auth.ListPersonalTokens(ctx, userID)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+	if tokens == nil {
+		tokens = []domain.PersonalToken{}
+	}
+	return web.NewResponse(tokens)
+}
+
+// RevokePersonalToken godoc
+//
+//	@Summary		Revokes a personal access token
+//	@Description	Revokes a personal access token of the authenticated user
+//	@Tags			users
+//	@Param			tokenID	path	int	true	"Token ID"
+//	@Success		204
+//	@Failure		400	{object}	error
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/tokens/{tokenID} [delete]
+func (app *userApp) revokePersonalTokenHandler(ctx context.Context, r *http.Request) web.Encoder {
+	tokenID, err := strconv.ParseInt(web.Param(r, "tokenID"), 10, 64)
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "invalid token id")
+	}
+
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	if err := app.auth.RevokePersonalToken(ctx, userID, tokenID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errs.Newf(errs.NotFound, "token not found")
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+	return web.NewNoResponse()
+}
+
+// ListSessions godoc
+//
+//	@Summary		Lists sessions
+//	@Description	Lists the devices the authenticated user is logged in on, the last used first
+//	@Tags			users
+//	@Produce		json
+//	@Success		200	{object}	[]domain.Session
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/sessions [get]
+func (app *userApp) listSessionsHandler(ctx context.Context, r *http.Request) web.Encoder {
+	claims, err := mid.GetClaims(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	sessions, err := app.auth.ListSessions(ctx, claims)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+	if sessions == nil {
+		sessions = []domain.Session{}
+	}
+	return web.NewResponse(sessions)
+}
+
+// RevokeSession godoc
+//
+//	@Summary		Revokes a session
+//	@Description	Logs the authenticated user out of a device, its tokens stop working at once
+//	@Tags			users
+//	@Param			sessionID	path	string	true	"Session ID"
+//	@Success		204
+//	@Failure		400	{object}	error
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		404	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/sessions/{sessionID} [delete]
+func (app *userApp) revokeSessionHandler(ctx context.Context, r *http.Request) web.Encoder {
+	sessionID, err := uuid.Parse(web.Param(r, "sessionID"))
+	if err != nil {
+		return errs.Newf(errs.InvalidArgument, "invalid session id")
+	}
+
+	userID, err := mid.GetAuthUserID(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	if err := app.auth.RevokeSession(ctx, userID, sessionID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			return errs.Newf(errs.NotFound, "session not found")
+		default:
+			return errs.New(errs.Internal, err)
+		}
+	}
+	return web.NewNoResponse()
+}
+
+// RevokeOtherSessions godoc
+//
+//	@Summary		Revokes the other sessions
+//	@Description	Logs the authenticated user out of every device but the one of the request
+//	@Tags			users
+//	@Success		204
+//	@Failure		401	{object}	error
+//	@Failure		403	{object}	error
+//	@Failure		500	{object}	error
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/sessions [delete]
+func (app *userApp) revokeOtherSessionsHandler(ctx context.Context, r *http.Request) web.Encoder {
+	claims, err := mid.GetClaims(ctx)
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	if err := app.auth.RevokeOtherSessions(ctx, claims); err != nil {
 		return errs.New(errs.Internal, err)
 	}
 	return web.NewNoResponse()
@@ -3,6 +3,7 @@ package mid
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"github.com/sergdort/Social/app/shared/errs"
 	"github.com/sergdort/Social/business/domain"
 	"github.com/sergdort/Social/foundation/web"
@@ -10,7 +11,8 @@ import (
 	"strings"
 )
 
-// Bearer processes JWT authentication logic.
+// Bearer authenticates the request with a JWT or a personal access token.
+// The scopes of personal access tokens are added to the context.
 func Bearer(ath *domain.AuthUseCase) web.MidFunc {
 	m := func(next web.HandlerFunc) web.HandlerFunc {
 		h := func(ctx context.Context, r *http.Request) web.Encoder {
@@ -28,6 +30,10 @@ func Bearer(ath *domain.AuthUseCase) web.MidFunc {
 			}
 
 			ctx = setAuthUserID(ctx, calaims.UserID)
+			ctx = setClaims(ctx, calaims)
+			if calaims.PersonalTokenID != 0 {
+				ctx = setScopes(ctx, calaims.Scopes)
+			}
 
 			return next(ctx, r)
 		}
@@ -38,6 +44,59 @@ func Bearer(ath *domain.AuthUseCase) web.MidFunc {
 	return m
 }
 
+// DenyPersonalTokens rejects requests authenticated with a personal access
+// token, for actions only meant for interactive logins, like managing the
+// tokens themselves. It must run after Bearer.
+func DenyPersonalTokens() web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			if _, ok := GetScopes(ctx); ok {
+				return errs.Newf(errs.PermissionDenied, "not allowed with a personal access token")
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// LoadUser adds the authenticated user to the context, for GetUser. Users
+// who are not active or are banned are rejected, so the routes using it are
+// only reachable by users allowed to act. It must run after Bearer.
+func LoadUser(users *domain.UsersUseCase) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			userID, err := GetAuthUserID(ctx)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, err)
+			}
+
+			usr, err := users.GetActiveUser(ctx, userID)
+			if err != nil {
+				switch {
+				case errors.Is(err, domain.ErrNotFound):
+					return errs.Newf(errs.Unauthenticated, "user not found")
+				case errors.Is(err, domain.ErrUserInactive):
+					return errs.Newf(errs.FailedPrecondition, "user is not active")
+				case errors.Is(err, domain.ErrUserBanned):
+					return errs.Newf(errs.PermissionDenied, "user is banned")
+				default:
+					return errs.New(errs.Internal, err)
+				}
+			}
+
+			return next(setUser(ctx, *usr), r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
 func Basic(username string, pass string) web.MidFunc {
 	m := func(next web.HandlerFunc) web.HandlerFunc {
 		h := func(ctx context.Context, r *http.Request) web.Encoder {
@@ -0,0 +1,59 @@
+package mid
+
+import (
+	"context"
+	"errors"
+	"github.com/sergdort/Social/app/shared/errs"
+	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/web"
+	"net/http"
+	"slices"
+)
+
+// OwnerFunc returns the id of the user owning the resource the request
+// operates on.
+type OwnerFunc func(ctx context.Context) (int64, error)
+
+// Authorize allows the request when the role of the authenticated user is
+// granted the permission. For actions on a resource owned by a user, owner
+// returns the owner so permissions scoped to own resources apply; it is nil
+// otherwise. It must run after Bearer and after the middleware loading the
+// resource. Requests authenticated with a personal access token also need
+// the permission among the scopes of the token.
+func Authorize(az *domain.Authorizer, permission domain.Permission, owner OwnerFunc) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			userID, err := GetAuthUserID(ctx)
+			if err != nil {
+				return errs.New(errs.Unauthenticated, err)
+			}
+			if scopes, ok := GetScopes(ctx); ok && !slices.Contains(scopes, permission) {
+				return errs.Newf(errs.PermissionDenied, "token is missing the %s scope", permission)
+			}
+
+			var resource *domain.Resource
+			if owner != nil {
+				ownerID, err := owner(ctx)
+				if err != nil {
+					return errs.New(errs.Internal, err)
+				}
+				resource = &domain.Resource{OwnerID: ownerID}
+			}
+
+			if err := az.Authorize(ctx, userID, permission, resource); err != nil {
+				switch {
+				case errors.Is(err, domain.ErrForbidden):
+					return errs.Newf(errs.PermissionDenied, "forbidden")
+				default:
+					return errs.New(errs.Internal, err)
+				}
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
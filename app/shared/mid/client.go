@@ -0,0 +1,46 @@
+package mid
+
+import (
+	"context"
+	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/web"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientInfo adds the IP address and user agent of the client to the
+// context. Behind a proxy, trustProxy takes the address the proxy appended
+// to X-Forwarded-For instead of the address of the proxy itself; it must
+// stay off otherwise, as clients can set the header.
+func ClientInfo(trustProxy bool) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			ctx = domain.WithClientInfo(ctx, domain.ClientInfo{
+				IP:        clientIP(r, trustProxy),
+				UserAgent: r.UserAgent(),
+			})
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			addrs := strings.Split(forwarded, ",")
+			return strings.TrimSpace(addrs[len(addrs)-1])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -11,6 +11,8 @@ type ctxKey int
 const (
 	userIDKey = iota + 1
 	userKey
+	claimsKey
+	scopesKey
 )
 
 func setAuthUserID(ctx context.Context, userID int64) context.Context {
@@ -27,6 +29,32 @@ func GetAuthUserID(ctx context.Context) (int64, error) {
 	return v, nil
 }
 
+func setClaims(ctx context.Context, claims domain.Claims) context.Context {
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// GetClaims returns the claims of the bearer token from the context.
+func GetClaims(ctx context.Context) (domain.Claims, error) {
+	v, ok := ctx.Value(claimsKey).(domain.Claims)
+	if !ok {
+		return domain.Claims{}, errors.New("claims not found in context")
+	}
+
+	return v, nil
+}
+
+func setScopes(ctx context.Context, scopes []domain.Permission) context.Context {
+	return context.WithValue(ctx, scopesKey, scopes)
+}
+
+// GetScopes returns the scopes of the personal access token the request was
+// authenticated with. It returns false for requests authenticated otherwise,
+// which are not limited by scopes.
+func GetScopes(ctx context.Context) ([]domain.Permission, bool) {
+	v, ok := ctx.Value(scopesKey).([]domain.Permission)
+	return v, ok
+}
+
 func setUser(ctx context.Context, usr domain.User) context.Context {
 	return context.WithValue(ctx, userKey, usr)
 }
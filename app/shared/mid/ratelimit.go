@@ -0,0 +1,69 @@
+package mid
+
+import (
+	"context"
+	"fmt"
+	"github.com/sergdort/Social/app/shared/errs"
+	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/ratelimit"
+	"github.com/sergdort/Social/foundation/web"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit limits the requests of the route named name. Requests are
+// counted per authenticated user, so it must follow Bearer on authenticated
+// routes, and per client IP otherwise. The quota is reported in the
+// RateLimit-* headers of the response. Requests are let through when the
+// store fails, and a nil store does not limit.
+func RateLimit(store ratelimit.Store, name string, limit ratelimit.Limit) web.MidFunc {
+	if store == nil {
+		return nil
+	}
+
+	policy := fmt.Sprintf("%d;w=%d", limit.Requests, int64(limit.Period.Seconds()))
+	if limit.Algorithm == ratelimit.TokenBucket && limit.Burst > 0 {
+		policy = fmt.Sprintf("%s;burst=%d", policy, limit.Burst)
+	}
+
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			result, err := store.Allow(ctx, rateLimitKey(ctx, name), limit)
+			if err != nil {
+				return next(ctx, r)
+			}
+
+			header := web.GetWriter(ctx).Header()
+			header.Set("RateLimit-Policy", policy)
+			header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("RateLimit-Reset", strconv.FormatInt(seconds(result.Reset), 10))
+
+			if !result.Allowed {
+				retryAfter := seconds(result.RetryAfter)
+				header.Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+				return errs.Newf(errs.TooManyRequests, "rate limit exceeded, retry in %d seconds", retryAfter)
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+func rateLimitKey(ctx context.Context, name string) string {
+	if userID, err := GetAuthUserID(ctx); err == nil {
+		return fmt.Sprintf("ratelimit:%s:user:%d", name, userID)
+	}
+	return fmt.Sprintf("ratelimit:%s:ip:%s", name, domain.GetClientInfo(ctx).IP)
+}
+
+// seconds rounds d up to whole seconds, as the headers expect.
+func seconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrUserBanned = errors.New("user banned")
+var ErrInvalidRole = errors.New("invalid role")
+
+// ErrSelfAdministration is returned when admins change their own role,
+// activation or ban, or delete themselves, which could lock every admin out.
+var ErrSelfAdministration = errors.New("admins cannot administer themselves")
+
+type UsersQuery struct {
+	Search string `json:"search" validate:"max=100"`
+	Limit  int    `json:"limit" validate:"gte=1,lte=100"`
+	Offset int    `json:"offset" validate:"gte=0"`
+}
+
+// UsersPage is a page of users ordered by id. Total counts every user
+// matching the search.
+type UsersPage struct {
+	Users []User
+	Total int64
+}
+
+type ChangeRolePayload struct {
+	Role RoleType `json:"role" validate:"required"`
+}
+
+// AdminUseCase manages users on behalf of admins. Every change is recorded
+// with the AuditLogger.
+type AdminUseCase struct {
+	users UsersRepository
+	roles RolesRepository
+	cache UsersCache
+	audit AuditLogger
+	now   func() time.Time
+}
+
+func NewAdminUseCase(users UsersRepository, roles RolesRepository, cache UsersCache, audit AuditLogger) *AdminUseCase {
+	return &AdminUseCase{
+		users: users,
+		roles: roles,
+		cache: cache,
+		audit: audit,
+		now:   time.Now,
+	}
+}
+
+func (uc *AdminUseCase) ListUsers(ctx context.Context, query UsersQuery) (UsersPage, error) {
+	return uc.users.List(ctx, query)
+}
+
+func (uc *AdminUseCase) GetUser(ctx context.Context, id int64) (*User, error) {
+	return uc.users.GetByID(ctx, id)
+}
+
+func (uc *AdminUseCase) ListRoles(ctx context.Context) ([]Role, error) {
+	return uc.roles.List(ctx)
+}
+
+func (uc *AdminUseCase) ChangeRole(ctx context.Context, actorID int64, userID int64, roleType RoleType) (*User, error) {
+	if !roleType.IsValid() {
+		return nil, ErrInvalidRole
+	}
+	user, err := uc.target(ctx, actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := uc.roles.GetByRoleType(ctx, roleType)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.users.UpdateRole(ctx, user.ID, role.ID); err != nil {
+		return nil, err
+	}
+
+	uc.changed(ctx, actorID, AuditUserRoleChanged, user.ID, map[string]any{
+		"from": user.Role.Name,
+		"to":   role.Name,
+	})
+	return uc.users.GetByID(ctx, user.ID)
+}
+
+// Activate activates the user without the invitation, for users that lost
+// it or deactivated ones.
+func (uc *AdminUseCase) Activate(ctx context.Context, actorID int64, userID int64) error {
+	return uc.setActive(ctx, actorID, userID, true)
+}
+
+// Deactivate prevents the user from logging in and acting, and logs them out
+// everywhere: their sessions, refresh tokens and personal access tokens are
+// revoked.
+func (uc *AdminUseCase) Deactivate(ctx context.Context, actorID int64, userID int64) error {
+	return uc.setActive(ctx, actorID, userID, false)
+}
+
+// Ban prevents the user from logging in and logs them out everywhere: their
+// sessions, refresh tokens and personal access tokens are revoked, so their
+// access tokens are rejected right away.
+func (uc *AdminUseCase) Ban(ctx context.Context, actorID int64, userID int64) error {
+	user, err := uc.target(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.users.Ban(ctx, user.ID, uc.now()); err != nil {
+		return err
+	}
+
+	uc.changed(ctx, actorID, AuditUserBanned, user.ID, nil)
+	return nil
+}
+
+func (uc *AdminUseCase) Unban(ctx context.Context, actorID int64, userID int64) error {
+	user, err := uc.target(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.users.Unban(ctx, user.ID); err != nil {
+		return err
+	}
+
+	uc.changed(ctx, actorID, AuditUserUnbanned, user.ID, nil)
+	return nil
+}
+
+// Delete deletes the user with their posts, comments and reactions.
+func (uc *AdminUseCase) Delete(ctx context.Context, actorID int64, userID int64) error {
+	user, err := uc.target(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.users.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+
+	uc.changed(ctx, actorID, AuditUserDeleted, user.ID, map[string]any{
+		"username": user.Username,
+		"email":    user.Email,
+	})
+	return nil
+}
+
+func (uc *AdminUseCase) setActive(ctx context.Context, actorID int64, userID int64, active bool) error {
+	user, err := uc.target(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.users.SetActive(ctx, user.ID, active); err != nil {
+		return err
+	}
+
+	action := AuditUserDeactivated
+	if active {
+		action = AuditUserActivated
+	}
+	uc.changed(ctx, actorID, action, user.ID, nil)
+	return nil
+}
+
+// target returns the user an admin acts on.
+func (uc *AdminUseCase) target(ctx context.Context, actorID int64, userID int64) (*User, error) {
+	if actorID == userID {
+		return nil, ErrSelfAdministration
+	}
+	return uc.users.GetByID(ctx, userID)
+}
+
+// changed evicts the cached user, so the change applies to their next
+// request, and records the action.
+func (uc *AdminUseCase) changed(ctx context.Context, actorID int64, action string, userID int64, metadata map[string]any) {
+	// A user that could not be evicted expires from the cache on its own.
+	_ = uc.cache.Delete(ctx, userID)
+	uc.audit.Record(ctx, AuditEvent{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: AuditTargetUser,
+		TargetID:   userID,
+		Metadata:   metadata,
+	})
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAdminUseCase(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	const adminID = 1
+	user := &User{ID: 42, Username: "arya", Role: Role{ID: 1, Name: "user"}}
+
+	type mocks struct {
+		users *MockUsersRepository
+		roles *MockRolesRepository
+		cache *MockUsersCache
+		audit *MockAuditLogger
+	}
+	newUseCase := func(t *testing.T) (*AdminUseCase, mocks) {
+		m := mocks{
+			users: NewMockUsersRepository(t),
+			roles: NewMockRolesRepository(t),
+			cache: NewMockUsersCache(t),
+			audit: NewMockAuditLogger(t),
+		}
+		useCase := NewAdminUseCase(m.users, m.roles, m.cache, m.audit)
+		useCase.now = func() time.Time { return now }
+		return useCase, m
+	}
+
+	t.Run("it should change the role and record it", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		moderator := &Role{ID: 2, Name: "moderator"}
+		updated := &User{ID: 42, Username: "arya", Role: *moderator}
+		m.users.On("GetByID", mock.Anything, int64(42)).Return(user, nil).Once()
+		m.roles.On("GetByRoleType", mock.Anything, RoleTypeModerator).Return(moderator, nil)
+		m.users.On("UpdateRole", mock.Anything, int64(42), int64(2)).Return(nil)
+		m.cache.On("Delete", mock.Anything, int64(42)).Return(nil)
+		m.audit.On("Record", mock.Anything, AuditEvent{
+			ActorID:    adminID,
+			Action:     AuditUserRoleChanged,
+			TargetType: AuditTargetUser,
+			TargetID:   42,
+			Metadata:   map[string]any{"from": "user", "to": "moderator"},
+		})
+		m.users.On("GetByID", mock.Anything, int64(42)).Return(updated, nil).Once()
+
+		got, err := useCase.ChangeRole(context.Background(), adminID, 42, RoleTypeModerator)
+
+		assert.NoError(t, err)
+		assert.Equal(t, updated, got)
+	})
+
+	t.Run("it should reject unknown roles", func(t *testing.T) {
+		useCase, _ := newUseCase(t)
+
+		_, err := useCase.ChangeRole(context.Background(), adminID, 42, RoleType("king"))
+
+		assert.ErrorIs(t, err, ErrInvalidRole)
+	})
+
+	t.Run("it should not let admins administer themselves", func(t *testing.T) {
+		useCase, _ := newUseCase(t)
+
+		assert.ErrorIs(t, useCase.Ban(context.Background(), adminID, adminID), ErrSelfAdministration)
+		assert.ErrorIs(t, useCase.Delete(context.Background(), adminID, adminID), ErrSelfAdministration)
+		assert.ErrorIs(t, useCase.Deactivate(context.Background(), adminID, adminID), ErrSelfAdministration)
+		_, err := useCase.ChangeRole(context.Background(), adminID, adminID, RoleTypeUser)
+		assert.ErrorIs(t, err, ErrSelfAdministration)
+	})
+
+	t.Run("it should ban the user and record it", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.users.On("GetByID", mock.Anything, int64(42)).Return(user, nil)
+		m.users.On("Ban", mock.Anything, int64(42), now).Return(nil)
+		m.cache.On("Delete", mock.Anything, int64(42)).Return(nil)
+		m.audit.On("Record", mock.Anything, mock.MatchedBy(func(event AuditEvent) bool {
+			return event.Action == AuditUserBanned && event.ActorID == adminID && event.TargetID == 42
+		}))
+
+		assert.NoError(t, useCase.Ban(context.Background(), adminID, 42))
+	})
+
+	t.Run("it should not record actions on unknown users", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.users.On("GetByID", mock.Anything, int64(7)).Return(nil, ErrNotFound)
+
+		assert.ErrorIs(t, useCase.Delete(context.Background(), adminID, 7), ErrNotFound)
+	})
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Audit actions, named after what they act on.
+const (
+	AuditLoginSucceeded       = "auth.login_succeeded"
+	AuditLoginFailed          = "auth.login_failed"
+	AuditAccountLocked        = "auth.account_locked"
+	AuditTokenRefreshed       = "auth.token_refreshed"
+	AuditRefreshTokenReused   = "auth.refresh_token_reused"
+	AuditPasswordReset        = "auth.password_reset"
+	AuditMFAEnabled           = "auth.mfa_enabled"
+	AuditMFADisabled          = "auth.mfa_disabled"
+	AuditPersonalTokenCreated = "auth.personal_token_created"
+	AuditPersonalTokenRevoked = "auth.personal_token_revoked"
+	AuditIdentityLinked       = "auth.identity_linked"
+	AuditSessionRevoked       = "auth.session_revoked"
+	AuditUserRoleChanged      = "user.role_changed"
+	AuditUserActivated        = "user.activated"
+	AuditUserDeactivated      = "user.deactivated"
+	AuditUserBanned           = "user.banned"
+	AuditUserUnbanned         = "user.unbanned"
+	AuditUserDeleted          = "user.deleted"
+	AuditPostDeleted          = "post.deleted"
+	AuditCommentDeleted       = "comment.deleted"
+)
+
+// Audit target types.
+const (
+	AuditTargetUser    = "user"
+	AuditTargetPost    = "post"
+	AuditTargetComment = "comment"
+)
+
+// AuditEvent records an action a user performed on a resource. ActorID is 0
+// for anonymous actions, like failed logins, and TargetID is 0 when the
+// target is unknown.
+type AuditEvent struct {
+	ID         int64          `json:"id"`
+	ActorID    int64          `json:"actor_id"`
+	Action     string         `json:"action"`
+	TargetType string         `json:"target_type"`
+	TargetID   int64          `json:"target_id"`
+	IP         string         `json:"ip"`
+	UserAgent  string         `json:"user_agent"`
+	TraceID    string         `json:"trace_id"`
+	Metadata   map[string]any `json:"metadata"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// AuditLogger records audit events. Recording never fails the audited
+// action, implementations report their own failures.
+type AuditLogger interface {
+	Record(ctx context.Context, event AuditEvent)
+}
+
+type AuditQuery struct {
+	ActorID    *int64
+	Action     string `validate:"max=64"`
+	TargetType string `validate:"max=32"`
+	TargetID   *int64
+	Since      *time.Time
+	Until      *time.Time
+	Limit      int `validate:"gte=1,lte=100"`
+	Cursor     *Cursor
+}
+
+// AuditPage is a page of audit events, newest first. NextCursor is nil when
+// there are no more events to fetch.
+type AuditPage struct {
+	Events     []AuditEvent
+	NextCursor *Cursor
+}
+
+// AuditRepository stores audit events, which cannot be updated or deleted.
+type AuditRepository interface {
+	Append(ctx context.Context, event AuditEvent) error
+	Query(ctx context.Context, query AuditQuery) (AuditPage, error)
+}
+
+// ClientInfo describes the client performing a request.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type clientInfoKey struct{}
+
+// WithClientInfo returns a context carrying the client of the request, for
+// audit events recorded while handling it.
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// GetClientInfo returns the client of the request, empty outside of one.
+func GetClientInfo(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
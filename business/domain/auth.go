@@ -5,9 +5,11 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"strings"
 	"time"
 )
 
@@ -15,6 +17,7 @@ var Validate *validator.Validate
 
 func init() {
 	Validate = validator.New(validator.WithRequiredStructEnabled())
+	Validate.RegisterStructValidation(validatePaginatedFeedQuery, PaginatedFeedQuery{})
 }
 
 type RegisterUserPayload struct {
@@ -24,8 +27,8 @@ type RegisterUserPayload struct {
 }
 
 type InvitationToken struct {
-	Token         string `json:"token"`
-	InvitationURL string `json:"invitation_url"`
+	Token         string `json:"token,omitempty"`
+	InvitationURL string `json:"invitation_url,omitempty"`
 }
 
 func (token InvitationToken) Encode() (data []byte, contentType string, err error) {
@@ -38,17 +41,48 @@ type CreateUserTokenPayload struct {
 	Password string `json:"password" validate:"required,min=3,max=72"`
 }
 
+type ResendInvitationPayload struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+type RequestPasswordResetPayload struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
+
+type ResetPasswordPayload struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=3,max=72"`
+}
+
+var ErrInvalidPasswordResetToken = errors.New("invalid password reset token")
+
 type AuthConfig struct {
-	InvitationExp time.Duration
-	FrontendURL   string
+	InvitationExp    time.Duration
+	RefreshTokenExp  time.Duration
+	PasswordResetExp time.Duration
+	MFAChallengeExp  time.Duration
+	// MFAIssuer is the name authenticator apps show next to the codes.
+	MFAIssuer   string
+	FrontendURL string
 }
 
 type Claims struct {
 	UserID int64
+	// ID is the unique id of the token (jti), used to revoke it.
+	ID        string
+	ExpiresAt time.Time
+	// PersonalTokenID is the id of the personal access token the request was
+	// authenticated with, 0 for JWTs. Scopes are the permissions the token
+	// is limited to.
+	PersonalTokenID int64
+	Scopes          []Permission
+	// SessionID is the session the access token was issued for, the zero
+	// UUID for personal access tokens.
+	SessionID uuid.UUID
 }
 
 type TokenGenerator interface {
-	GenerateToken(ctx context.Context, userID int64) (string, error)
+	GenerateToken(ctx context.Context, userID int64, sessionID uuid.UUID) (string, error)
 }
 
 type TokenValidator interface {
@@ -56,20 +90,52 @@ type TokenValidator interface {
 }
 
 type AuthUseCase struct {
-	config     AuthConfig
-	roles      RolesRepository
-	users      UsersRepository
-	token      TokenGenerator
-	tokenValid TokenValidator
+	config         AuthConfig
+	roles          RolesRepository
+	users          UsersRepository
+	refreshTokens  RefreshTokensRepository
+	revokedTokens  RevokedTokensRepository
+	mfa            MFARepository
+	token          TokenGenerator
+	tokenValid     TokenValidator
+	mailer         Mailer
+	audit          AuditLogger
+	throttle       *LoginThrottle
+	personalTokens PersonalTokensRepository
+	sessions       SessionsRepository
+	now            func() time.Time
 }
 
-func NewAuthUseCase(config AuthConfig, roles RolesRepository, users UsersRepository, token TokenGenerator, tokenValid TokenValidator) *AuthUseCase {
+func NewAuthUseCase(
+	config AuthConfig,
+	roles RolesRepository,
+	users UsersRepository,
+	refreshTokens RefreshTokensRepository,
+	revokedTokens RevokedTokensRepository,
+	mfa MFARepository,
+	token TokenGenerator,
+	tokenValid TokenValidator,
+	mailer Mailer,
+	audit AuditLogger,
+	throttle *LoginThrottle,
+	personalTokens PersonalTokensRepository,
+	sessions SessionsRepository,
+) *AuthUseCase {
 	return &AuthUseCase{
-		config:     config,
-		roles:      roles,
-		users:      users,
-		token:      token,
-		tokenValid: tokenValid,
+		config:         config,
+		roles:          roles,
+		users:          users,
+		refreshTokens:  refreshTokens,
+		revokedTokens:  revokedTokens,
+		mfa:            mfa,
+		token:          token,
+		tokenValid:     tokenValid,
+		mailer:         mailer,
+		audit:          audit,
+		throttle:       throttle,
+		personalTokens: personalTokens,
+		sessions:       sessions,
+		now:            time.Now,
 	}
 }
 
@@ -89,38 +155,433 @@ func (auth *AuthUseCase) RegisterUser(ctx context.Context, payload RegisterUserP
 		return nil, err
 	}
 
+	response, invitation, err := auth.newInvitation(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := auth.users.CreateAndInvite(ctx, user, hashToken(response.Token), auth.config.InvitationExp, invitation); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// newInvitation returns a new invitation token of the user and the outbox
+// message delivering it.
+func (auth *AuthUseCase) newInvitation(user *User) (InvitationToken, OutboxMessage, error) {
 	token := uuid.New().String()
 	response := InvitationToken{
 		Token:         token,
 		InvitationURL: fmt.Sprintf("%s/confirm/%s", auth.config.FrontendURL, token),
 	}
 
-	hashToken := hashToken(response.Token)
+	invitation, err := NewOutboxMessage(OutboxKindUserInvitation, invitationMessage{
+		Username:      user.Username,
+		Email:         user.Email,
+		ActivationURL: response.InvitationURL,
+	})
+	if err != nil {
+		return InvitationToken{}, OutboxMessage{}, err
+	}
+	return response, invitation, nil
+}
+
+// invitationMessage is the payload of OutboxKindUserInvitation messages.
+type invitationMessage struct {
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	ActivationURL string `json:"activation_url"`
+}
 
-	if err := auth.users.CreateAndInvite(ctx, user, hashToken, auth.config.InvitationExp); err != nil {
-		return nil, err
+// InvitationHandler delivers the invitation emails queued by RegisterUser.
+// When an invitation cannot be delivered the user could never activate the
+// account, so the registration is reverted to let them register again.
+func (auth *AuthUseCase) InvitationHandler() OutboxHandler {
+	decode := func(payload json.RawMessage) (invitationMessage, error) {
+		var msg invitationMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			return msg, fmt.Errorf("decode invitation: %w", err)
+		}
+		return msg, nil
 	}
 
-	return &response, nil
+	return OutboxHandler{
+		Deliver: func(ctx context.Context, payload json.RawMessage) error {
+			msg, err := decode(payload)
+			if err != nil {
+				return err
+			}
+			return auth.mailer.Send(ctx, UserInvitationTemplate, msg.Username, msg.Email, UserInvitation{
+				Username:      msg.Username,
+				ActivationURL: msg.ActivationURL,
+			})
+		},
+		Dead: func(ctx context.Context, payload json.RawMessage) error {
+			msg, err := decode(payload)
+			if err != nil {
+				return err
+			}
+			user, err := auth.users.GetByEmail(ctx, msg.Email)
+			if err != nil {
+				return err
+			}
+			if user.IsActive || user.ActivatedAt != nil {
+				return nil
+			}
+			return auth.users.RevertCreateAndInvite(ctx, user.ID)
+		},
+	}
+}
+
+// ResendInvitation replaces the invitation of a user who has not activated
+// their account yet and queues a new invitation email. Like
+// RequestPasswordReset it succeeds whatever the email, so callers cannot use
+// it to find out which emails have an account.
+func (auth *AuthUseCase) ResendInvitation(ctx context.Context, payload ResendInvitationPayload) error {
+	user, err := auth.users.GetByEmail(ctx, payload.Email)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.IsActive || user.ActivatedAt != nil || user.BannedAt != nil {
+		return nil
+	}
+
+	invitation, email, err := auth.newInvitation(user)
+	if err != nil {
+		return err
+	}
+
+	return auth.users.Reinvite(ctx, user.ID, hashToken(invitation.Token), auth.config.InvitationExp, email)
 }
 
-func (auth *AuthUseCase) ActivateToken(ctx context.Context, token string) error {
-	return auth.users.Activate(ctx, hashToken(token))
+// RequestPasswordReset queues the email with a password reset link. It
+// succeeds whether or not the email is registered, so callers cannot use it to
+// find out which emails have an account.
+func (auth *AuthUseCase) RequestPasswordReset(ctx context.Context, payload RequestPasswordResetPayload) error {
+	user, err := auth.users.GetByEmail(ctx, payload.Email)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token := uuid.New().String()
+	email, err := NewOutboxMessage(OutboxKindPasswordReset, passwordResetMessage{
+		Username: user.Username,
+		Email:    user.Email,
+		ResetURL: fmt.Sprintf("%s/reset-password/%s", auth.config.FrontendURL, token),
+	})
+	if err != nil {
+		return err
+	}
+
+	return auth.users.CreatePasswordReset(ctx, user.ID, hashToken(token), auth.config.PasswordResetExp, email)
 }
 
-func (auth *AuthUseCase) CreateToken(ctx context.Context, payload CreateUserTokenPayload) (string, error) {
+// ResetPassword sets the password of the user the reset token was issued to.
+// The user is logged out everywhere: their sessions, refresh tokens and
+// personal access tokens are revoked.
+func (auth *AuthUseCase) ResetPassword(ctx context.Context, payload ResetPasswordPayload) error {
+	var password Password
+	if err := password.Set(payload.Password); err != nil {
+		return err
+	}
+
+	userID, err := auth.users.ResetPassword(ctx, hashToken(payload.Token), password.Hash)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrInvalidPasswordResetToken
+		}
+		return err
+	}
+
+	auth.audit.Record(ctx, AuditEvent{
+		ActorID:    userID,
+		Action:     AuditPasswordReset,
+		TargetType: AuditTargetUser,
+		TargetID:   userID,
+	})
+	return nil
+}
+
+// passwordResetMessage is the payload of OutboxKindPasswordReset messages.
+type passwordResetMessage struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	ResetURL string `json:"reset_url"`
+}
+
+// PasswordResetHandler delivers the password reset emails queued by
+// RequestPasswordReset.
+func (auth *AuthUseCase) PasswordResetHandler() OutboxHandler {
+	return OutboxHandler{
+		Deliver: func(ctx context.Context, payload json.RawMessage) error {
+			var msg passwordResetMessage
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				return fmt.Errorf("decode password reset: %w", err)
+			}
+			return auth.mailer.Send(ctx, PasswordResetTemplate, msg.Username, msg.Email, PasswordReset{
+				Username: msg.Username,
+				ResetURL: msg.ResetURL,
+			})
+		},
+	}
+}
+
+// CreateToken logs the user in with their credentials. Users with two-factor
+// authentication get a challenge to complete with VerifyMFA instead of tokens,
+// banned users get ErrUserBanned and users who are not active ErrUserInactive.
+// Logins of accounts or IPs that failed too often get a *LoginLockedError.
+func (auth *AuthUseCase) CreateToken(ctx context.Context, payload CreateUserTokenPayload) (LoginResult, error) {
+	client := GetClientInfo(ctx)
+	if err := auth.throttle.Check(ctx, payload.Email, client.IP); err != nil {
+		return LoginResult{}, err
+	}
+
 	user, err := auth.users.GetByEmail(ctx, payload.Email)
 	if err != nil {
-		return "", err
+		if errors.Is(err, ErrNotFound) {
+			auth.loginFailed(ctx, 0, payload.Email, "unknown_email")
+			if err := auth.throttleFailure(ctx, payload.Email, client.IP, nil); err != nil {
+				return LoginResult{}, err
+			}
+		}
+		return LoginResult{}, err
 	}
 	if err := user.Password.Verify(payload.Password); err != nil {
-		return "", err
+		auth.loginFailed(ctx, user.ID, payload.Email, "invalid_password")
+		if err := auth.throttleFailure(ctx, payload.Email, client.IP, user); err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{}, err
+	}
+	if user.BannedAt != nil {
+		auth.loginFailed(ctx, user.ID, payload.Email, "banned")
+		return LoginResult{}, ErrUserBanned
+	}
+	if !user.IsActive {
+		auth.loginFailed(ctx, user.ID, payload.Email, "inactive")
+		return LoginResult{}, ErrUserInactive
+	}
+	if err := auth.throttle.Succeeded(ctx, payload.Email); err != nil {
+		return LoginResult{}, err
+	}
+
+	challenge, err := auth.challenge(ctx, user.ID)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if challenge != nil {
+		return LoginResult{Challenge: challenge}, nil
+	}
+
+	tokens, err := auth.issueTokens(ctx, user.ID)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	auth.loggedIn(ctx, user.ID, "password")
+	return LoginResult{Tokens: tokens}, nil
+}
+
+func (auth *AuthUseCase) loggedIn(ctx context.Context, userID int64, method string) {
+	auth.audit.Record(ctx, AuditEvent{
+		ActorID:    userID,
+		Action:     AuditLoginSucceeded,
+		TargetType: AuditTargetUser,
+		TargetID:   userID,
+		Metadata:   map[string]any{"method": method},
+	})
+}
+
+// throttleFailure counts a failed login and records the lockout it causes.
+func (auth *AuthUseCase) throttleFailure(ctx context.Context, email string, ip string, user *User) error {
+	lockout, err := auth.throttle.Failed(ctx, email, ip, user)
+	if err != nil || lockout == 0 {
+		return err
+	}
+
+	var userID int64
+	if user != nil {
+		userID = user.ID
+	}
+	auth.audit.Record(ctx, AuditEvent{
+		Action:     AuditAccountLocked,
+		TargetType: AuditTargetUser,
+		TargetID:   userID,
+		Metadata:   map[string]any{"email": email, "locked_for": lockout.String()},
+	})
+	return nil
+}
+
+// loginFailed records a failed login, the user is 0 when the email is not
+// registered.
+func (auth *AuthUseCase) loginFailed(ctx context.Context, userID int64, email string, reason string) {
+	metadata := map[string]any{"reason": reason}
+	if email != "" {
+		metadata["email"] = email
+	}
+	auth.audit.Record(ctx, AuditEvent{
+		Action:     AuditLoginFailed,
+		TargetType: AuditTargetUser,
+		TargetID:   userID,
+		Metadata:   metadata,
+	})
+}
+
+// issueTokens starts a session and issues its access token and the first
+// refresh token of its family.
+func (auth *AuthUseCase) issueTokens(ctx context.Context, userID int64) (AuthTokens, error) {
+	expiry := auth.now().Add(auth.config.RefreshTokenExp)
+	sessionID, err := auth.startSession(ctx, userID, expiry)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+
+	accessToken, err := auth.token.GenerateToken(ctx, userID, sessionID)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+
+	refreshToken := uuid.New().String()
+	if err := auth.refreshTokens.Create(ctx, hashToken(refreshToken), userID, sessionID, expiry); err != nil {
+		return AuthTokens{}, err
+	}
+
+	return AuthTokens{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new pair of tokens. Every
+// refresh token can be used once: presenting a rotated token again means it
+// leaked, so the whole family is revoked and its holder has to log in again.
+// Banned users get ErrUserBanned and users who are not active ErrUserInactive.
+func (auth *AuthUseCase) RefreshToken(ctx context.Context, refreshToken string) (AuthTokens, error) {
+	token, err := auth.refreshTokens.GetByToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return AuthTokens{}, ErrInvalidRefreshToken
+		}
+		return AuthTokens{}, err
+	}
+
+	if token.RevokedAt != nil {
+		return AuthTokens{}, auth.revokeReusedFamily(ctx, token)
+	}
+	if !token.Expiry.After(auth.now()) {
+		return AuthTokens{}, ErrInvalidRefreshToken
+	}
+
+	user, err := auth.users.GetByID(ctx, token.UserID)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+	if user.BannedAt != nil {
+		return AuthTokens{}, ErrUserBanned
 	}
-	return auth.token.GenerateToken(ctx, user.ID)
+	if !user.IsActive {
+		return AuthTokens{}, ErrUserInactive
+	}
+
+	accessToken, err := auth.token.GenerateToken(ctx, token.UserID, token.FamilyID)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+
+	next := uuid.New().String()
+	expiry := auth.now().Add(auth.config.RefreshTokenExp)
+	if err := auth.refreshTokens.Rotate(ctx, token, hashToken(next), expiry); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			// Rotated concurrently by someone else holding the same token.
+			return AuthTokens{}, auth.revokeReusedFamily(ctx, token)
+		}
+		return AuthTokens{}, err
+	}
+
+	auth.audit.Record(ctx, AuditEvent{
+		ActorID:    token.UserID,
+		Action:     AuditTokenRefreshed,
+		TargetType: AuditTargetUser,
+		TargetID:   token.UserID,
+		Metadata:   map[string]any{"family_id": token.FamilyID.String()},
+	})
+	return AuthTokens{AccessToken: accessToken, RefreshToken: next}, nil
 }
 
+func (auth *AuthUseCase) revokeReusedFamily(ctx context.Context, token *RefreshToken) error {
+	if err := auth.refreshTokens.RevokeFamily(ctx, token.FamilyID); err != nil {
+		return err
+	}
+	auth.audit.Record(ctx, AuditEvent{
+		Action:     AuditRefreshTokenReused,
+		TargetType: AuditTargetUser,
+		TargetID:   token.UserID,
+		Metadata:   map[string]any{"family_id": token.FamilyID.String()},
+	})
+	return ErrRefreshTokenReused
+}
+
+// Logout revokes the access token until it expires, together with its
+// session and the refresh token family given.
+func (auth *AuthUseCase) Logout(ctx context.Context, claims Claims, refreshToken string) error {
+	if err := auth.revokedTokens.Revoke(ctx, claims.ID, claims.ExpiresAt); err != nil {
+		return err
+	}
+
+	if claims.SessionID != uuid.Nil {
+		if err := auth.sessions.Revoke(ctx, claims.UserID, claims.SessionID); err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	token, err := auth.refreshTokens.GetByToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if token.UserID != claims.UserID {
+		return nil
+	}
+
+	return auth.refreshTokens.RevokeFamily(ctx, token.FamilyID)
+}
+
+// ValidateToken returns the claims of a JWT or of a personal access token.
 func (auth *AuthUseCase) ValidateToken(ctx context.Context, token string) (Claims, error) {
-	return auth.tokenValid.ValidateToken(ctx, token)
+	if strings.HasPrefix(token, PersonalTokenPrefix) {
+		return auth.validatePersonalToken(ctx, token)
+	}
+
+	claims, err := auth.tokenValid.ValidateToken(ctx, token)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	revoked, err := auth.revokedTokens.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return Claims{}, err
+	}
+	if revoked {
+		return Claims{}, ErrTokenRevoked
+	}
+
+	// Access tokens from before sessions have none, they expire soon.
+	if claims.SessionID != uuid.Nil {
+		if err := auth.validateSession(ctx, claims.SessionID); err != nil {
+			return Claims{}, err
+		}
+	}
+
+	return claims, nil
 }
 
 func hashToken(plainToken string) string {
@@ -0,0 +1,503 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthUseCase_RegisterUser(t *testing.T) {
+	payload := RegisterUserPayload{
+		UserName: "arya",
+		Email:    "arya@winterfell.com",
+		Password: "needle",
+	}
+	config := AuthConfig{
+		InvitationExp: time.Hour,
+		FrontendURL:   "http://localhost:5173",
+	}
+
+	t.Run("it should queue the invitation with the user", func(t *testing.T) {
+		roles := NewMockRolesRepository(t)
+		users := NewMockUsersRepository(t)
+		useCase := NewAuthUseCase(config, roles, users, nil, nil, nil, nil, nil, NewMockMailer(t), auditLogger(t), nil, nil, nil)
+
+		roles.On("GetByRoleType", mock.Anything, RoleTypeUser).Return(&Role{ID: 1}, nil)
+		users.On("CreateAndInvite", mock.Anything, mock.Anything, mock.Anything, config.InvitationExp, mock.Anything).Return(nil)
+
+		token, err := useCase.RegisterUser(context.Background(), payload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, config.FrontendURL+"/confirm/"+token.Token, token.InvitationURL)
+
+		invitation := users.Calls[0].Arguments.Get(4).(OutboxMessage)
+		assert.Equal(t, OutboxKindUserInvitation, invitation.Kind)
+		assert.JSONEq(t, `{
+			"username": "arya",
+			"email": "arya@winterfell.com",
+			"activation_url": "`+token.InvitationURL+`"
+		}`, string(invitation.Payload))
+	})
+}
+
+func TestAuthUseCase_InvitationHandler(t *testing.T) {
+	payload := json.RawMessage(`{
+		"username": "arya",
+		"email": "arya@winterfell.com",
+		"activation_url": "http://localhost:5173/confirm/token"
+	}`)
+
+	t.Run("it should email the invitation with the activation url", func(t *testing.T) {
+		mailer := NewMockMailer(t)
+		useCase := NewAuthUseCase(AuthConfig{}, nil, nil, nil, nil, nil, nil, nil, mailer, auditLogger(t), nil, nil, nil)
+		mailer.On("Send", mock.Anything, UserInvitationTemplate, "arya", "arya@winterfell.com", UserInvitation{
+			Username:      "arya",
+			ActivationURL: "http://localhost:5173/confirm/token",
+		}).Return(errors.New("mailbox unavailable"))
+
+		err := useCase.InvitationHandler().Deliver(context.Background(), payload)
+
+		assert.EqualError(t, err, "mailbox unavailable")
+	})
+
+	t.Run("it should revert the user once the invitation is dead", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		useCase := NewAuthUseCase(AuthConfig{}, nil, users, nil, nil, nil, nil, nil, nil, auditLogger(t), nil, nil, nil)
+		users.On("GetByEmail", mock.Anything, "arya@winterfell.com").Return(&User{ID: 42}, nil)
+		users.On("RevertCreateAndInvite", mock.Anything, int64(42)).Return(nil)
+
+		err := useCase.InvitationHandler().Dead(context.Background(), payload)
+
+		assert.NoError(t, err)
+		users.AssertCalled(t, "RevertCreateAndInvite", mock.Anything, int64(42))
+	})
+
+	t.Run("it should keep a user activated in the meantime", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		useCase := NewAuthUseCase(AuthConfig{}, nil, users, nil, nil, nil, nil, nil, nil, auditLogger(t), nil, nil, nil)
+		users.On("GetByEmail", mock.Anything, "arya@winterfell.com").Return(&User{ID: 42, IsActive: true}, nil)
+
+		err := useCase.InvitationHandler().Dead(context.Background(), payload)
+
+		assert.NoError(t, err)
+		users.AssertNotCalled(t, "RevertCreateAndInvite", mock.Anything, mock.Anything)
+	})
+
+	t.Run("it should keep a user deactivated after the activation", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		useCase := NewAuthUseCase(AuthConfig{}, nil, users, nil, nil, nil, nil, nil, nil, auditLogger(t), nil, nil, nil)
+		activatedAt := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+		users.On("GetByEmail", mock.Anything, "arya@winterfell.com").Return(&User{ID: 42, ActivatedAt: &activatedAt}, nil)
+
+		err := useCase.InvitationHandler().Dead(context.Background(), payload)
+
+		assert.NoError(t, err)
+		users.AssertNotCalled(t, "RevertCreateAndInvite", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthUseCase_ResendInvitation(t *testing.T) {
+	config := AuthConfig{
+		InvitationExp: time.Hour,
+		FrontendURL:   "http://localhost:5173",
+	}
+	payload := ResendInvitationPayload{Email: "arya@winterfell.com"}
+	activatedAt := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+
+	t.Run("it should replace the invitation and queue a new email", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		useCase := NewAuthUseCase(config, nil, users, nil, nil, nil, nil, nil, nil, auditLogger(t), nil, nil, nil)
+		users.On("GetByEmail", mock.Anything, payload.Email).Return(&User{ID: 42, Username: "arya", Email: payload.Email}, nil)
+		users.On("Reinvite", mock.Anything, int64(42), mock.Anything, time.Hour, mock.Anything).Return(nil)
+
+		err := useCase.ResendInvitation(context.Background(), payload)
+
+		assert.NoError(t, err)
+		email := users.Calls[1].Arguments.Get(4).(OutboxMessage)
+		assert.Equal(t, OutboxKindUserInvitation, email.Kind)
+
+		var msg invitationMessage
+		assert.NoError(t, json.Unmarshal(email.Payload, &msg))
+		token := strings.TrimPrefix(msg.ActivationURL, config.FrontendURL+"/confirm/")
+		assert.NotEqual(t, msg.ActivationURL, token)
+		users.AssertCalled(t, "Reinvite", mock.Anything, int64(42), hashToken(token), time.Hour, email)
+	})
+
+	tests := []struct {
+		name string
+		user *User
+		err  error
+	}{
+		{name: "it should not tell whether the email is registered", err: ErrNotFound},
+		{name: "it should not invite an active user", user: &User{ID: 42, IsActive: true}},
+		{name: "it should not invite a deactivated user", user: &User{ID: 42, ActivatedAt: &activatedAt}},
+		{name: "it should not invite a banned user", user: &User{ID: 42, BannedAt: &activatedAt}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := NewMockUsersRepository(t)
+			useCase := NewAuthUseCase(config, nil, users, nil, nil, nil, nil, nil, nil, auditLogger(t), nil, nil, nil)
+			users.On("GetByEmail", mock.Anything, payload.Email).Return(tt.user, tt.err)
+
+			err := useCase.ResendInvitation(context.Background(), payload)
+
+			assert.NoError(t, err)
+			users.AssertNotCalled(t, "Reinvite", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthUseCase_RefreshToken(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	config := AuthConfig{RefreshTokenExp: time.Hour}
+	stored := &RefreshToken{
+		ID:       7,
+		UserID:   42,
+		FamilyID: uuid.New(),
+		Expiry:   now.Add(time.Minute),
+	}
+
+	newUseCase := func(refreshTokens RefreshTokensRepository, token TokenGenerator, users UsersRepository) *AuthUseCase {
+		useCase := NewAuthUseCase(config, nil, users, refreshTokens, nil, nil, token, nil, nil, auditLogger(t), nil, nil, nil)
+		useCase.now = func() time.Time { return now }
+		return useCase
+	}
+	activeUsers := func(t *testing.T) *MockUsersRepository {
+		users := NewMockUsersRepository(t)
+		users.On("GetByID", mock.Anything, int64(42)).Return(&User{ID: 42, IsActive: true}, nil)
+		return users
+	}
+
+	t.Run("it should rotate the refresh token", func(t *testing.T) {
+		refreshTokens := NewMockRefreshTokensRepository(t)
+		token := NewMockTokenGenerator(t)
+		refreshTokens.On("GetByToken", mock.Anything, hashToken("refresh")).Return(stored, nil)
+		token.On("GenerateToken", mock.Anything, int64(42), stored.FamilyID).Return("access", nil)
+		refreshTokens.On("Rotate", mock.Anything, stored, mock.Anything, now.Add(time.Hour)).Return(nil)
+
+		tokens, err := newUseCase(refreshTokens, token, activeUsers(t)).RefreshToken(context.Background(), "refresh")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "access", tokens.AccessToken)
+		assert.NotEqual(t, "refresh", tokens.RefreshToken)
+		refreshTokens.AssertCalled(t, "Rotate", mock.Anything, stored, hashToken(tokens.RefreshToken), now.Add(time.Hour))
+	})
+
+	t.Run("it should record the refresh", func(t *testing.T) {
+		refreshTokens := NewMockRefreshTokensRepository(t)
+		token := NewMockTokenGenerator(t)
+		audit := NewMockAuditLogger(t)
+		useCase := NewAuthUseCase(config, nil, activeUsers(t), refreshTokens, nil, nil, token, nil, nil, audit, nil, nil, nil)
+		useCase.now = func() time.Time { return now }
+		refreshTokens.On("GetByToken", mock.Anything, hashToken("refresh")).Return(stored, nil)
+		token.On("GenerateToken", mock.Anything, int64(42), stored.FamilyID).Return("access", nil)
+		refreshTokens.On("Rotate", mock.Anything, stored, mock.Anything, now.Add(time.Hour)).Return(nil)
+		audit.On("Record", mock.Anything, AuditEvent{
+			ActorID:    42,
+			Action:     AuditTokenRefreshed,
+			TargetType: AuditTargetUser,
+			TargetID:   42,
+			Metadata:   map[string]any{"family_id": stored.FamilyID.String()},
+		})
+
+		_, err := useCase.RefreshToken(context.Background(), "refresh")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("it should revoke the family when a rotated token is reused", func(t *testing.T) {
+		revokedAt := now.Add(-time.Minute)
+		reused := *stored
+		reused.RevokedAt = &revokedAt
+		refreshTokens := NewMockRefreshTokensRepository(t)
+		refreshTokens.On("GetByToken", mock.Anything, hashToken("refresh")).Return(&reused, nil)
+		refreshTokens.On("RevokeFamily", mock.Anything, stored.FamilyID).Return(nil)
+
+		_, err := newUseCase(refreshTokens, nil, nil).RefreshToken(context.Background(), "refresh")
+
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	})
+
+	t.Run("it should revoke the family when the token was rotated concurrently", func(t *testing.T) {
+		refreshTokens := NewMockRefreshTokensRepository(t)
+		token := NewMockTokenGenerator(t)
+		refreshTokens.On("GetByToken", mock.Anything, hashToken("refresh")).Return(stored, nil)
+		token.On("GenerateToken", mock.Anything, int64(42), stored.FamilyID).Return("access", nil)
+		refreshTokens.On("Rotate", mock.Anything, stored, mock.Anything, mock.Anything).Return(ErrNotFound)
+		refreshTokens.On("RevokeFamily", mock.Anything, stored.FamilyID).Return(nil)
+
+		_, err := newUseCase(refreshTokens, token, activeUsers(t)).RefreshToken(context.Background(), "refresh")
+
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	})
+
+	t.Run("it should reject expired and unknown tokens", func(t *testing.T) {
+		expired := *stored
+		expired.Expiry = now
+		refreshTokens := NewMockRefreshTokensRepository(t)
+		refreshTokens.On("GetByToken", mock.Anything, hashToken("expired")).Return(&expired, nil)
+		refreshTokens.On("GetByToken", mock.Anything, hashToken("unknown")).Return(nil, ErrNotFound)
+		useCase := newUseCase(refreshTokens, nil, nil)
+
+		_, err := useCase.RefreshToken(context.Background(), "expired")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+		_, err = useCase.RefreshToken(context.Background(), "unknown")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	bannedAt := now.Add(-time.Hour)
+	tests := []struct {
+		name string
+		user *User
+		err  error
+	}{
+		{name: "it should not refresh the tokens of a deactivated user", user: &User{ID: 42}, err: ErrUserInactive},
+		{name: "it should not refresh the tokens of a banned user", user: &User{ID: 42, IsActive: true, BannedAt: &bannedAt}, err: ErrUserBanned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refreshTokens := NewMockRefreshTokensRepository(t)
+			token := NewMockTokenGenerator(t)
+			users := NewMockUsersRepository(t)
+			refreshTokens.On("GetByToken", mock.Anything, hashToken("refresh")).Return(stored, nil)
+			users.On("GetByID", mock.Anything, int64(42)).Return(tt.user, nil)
+
+			_, err := newUseCase(refreshTokens, token, users).RefreshToken(context.Background(), "refresh")
+
+			assert.ErrorIs(t, err, tt.err)
+			token.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything, mock.Anything)
+			refreshTokens.AssertNotCalled(t, "Rotate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthUseCase_Logout(t *testing.T) {
+	claims := Claims{UserID: 42, ID: "jti", ExpiresAt: time.Now().Add(time.Minute)}
+	familyID := uuid.New()
+
+	t.Run("it should revoke the access token and the refresh token family", func(t *testing.T) {
+		refreshTokens := NewMockRefreshTokensRepository(t)
+		revokedTokens := NewMockRevokedTokensRepository(t)
+		useCase := NewAuthUseCase(AuthConfig{}, nil, nil, refreshTokens, revokedTokens, nil, nil, nil, nil, auditLogger(t), nil, nil, nil)
+		revokedTokens.On("Revoke", mock.Anything, "jti", claims.ExpiresAt).Return(nil)
+		refreshTokens.On("GetByToken", mock.Anything, hashToken("refresh")).Return(&RefreshToken{UserID: 42, FamilyID: familyID}, nil)
+		refreshTokens.On("RevokeFamily", mock.Anything, familyID).Return(nil)
+
+		err := useCase.Logout(context.Background(), claims, "refresh")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("it should not revoke refresh tokens of other users", func(t *testing.T) {
+		refreshTokens := NewMockRefreshTokensRepository(t)
+		revokedTokens := NewMockRevokedTokensRepository(t)
+		useCase := NewAuthUseCase(AuthConfig{}, nil, nil, refreshTokens, revokedTokens, nil, nil, nil, nil, auditLogger(t), nil, nil, nil)
+		revokedTokens.On("Revoke", mock.Anything, "jti", claims.ExpiresAt).Return(nil)
+		refreshTokens.On("GetByToken", mock.Anything, hashToken("refresh")).Return(&RefreshToken{UserID: 43, FamilyID: familyID}, nil)
+
+		err := useCase.Logout(context.Background(), claims, "refresh")
+
+		assert.NoError(t, err)
+		refreshTokens.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthUseCase_ValidateToken(t *testing.T) {
+	t.Run("it should reject revoked tokens", func(t *testing.T) {
+		revokedTokens := NewMockRevokedTokensRepository(t)
+		tokenValid := NewMockTokenValidator(t)
+		useCase := NewAuthUseCase(AuthConfig{}, nil, nil, nil, revokedTokens, nil, nil, tokenValid, nil, auditLogger(t), nil, nil, nil)
+		tokenValid.On("ValidateToken", mock.Anything, "token").Return(Claims{UserID: 42, ID: "jti"}, nil)
+		revokedTokens.On("IsRevoked", mock.Anything, "jti").Return(true, nil)
+
+		_, err := useCase.ValidateToken(context.Background(), "token")
+
+		assert.ErrorIs(t, err, ErrTokenRevoked)
+	})
+}
+
+func TestAuthUseCase_RequestPasswordReset(t *testing.T) {
+	config := AuthConfig{
+		PasswordResetExp: time.Hour,
+		FrontendURL:      "http://localhost:5173",
+	}
+	payload := RequestPasswordResetPayload{Email: "arya@winterfell.com"}
+
+	t.Run("it should queue the reset email with the user", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		useCase := NewAuthUseCase(config, nil, users, nil, nil, nil, nil, nil, nil, auditLogger(t), nil, nil, nil)
+		users.On("GetByEmail", mock.Anything, payload.Email).Return(&User{ID: 42, Username: "arya", Email: payload.Email}, nil)
+		users.On("CreatePasswordReset", mock.Anything, int64(42), mock.Anything, time.Hour, mock.Anything).Return(nil)
+
+		err := useCase.RequestPasswordReset(context.Background(), payload)
+
+		assert.NoError(t, err)
+		email := users.Calls[1].Arguments.Get(4).(OutboxMessage)
+		assert.Equal(t, OutboxKindPasswordReset, email.Kind)
+
+		var msg passwordResetMessage
+		assert.NoError(t, json.Unmarshal(email.Payload, &msg))
+		token := strings.TrimPrefix(msg.ResetURL, config.FrontendURL+"/reset-password/")
+		assert.NotEqual(t, msg.ResetURL, token)
+		// Only the hash of the token is stored.
+		users.AssertCalled(t, "CreatePasswordReset", mock.Anything, int64(42), hashToken(token), time.Hour, email)
+	})
+
+	t.Run("it should not tell whether the email is registered", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		useCase := NewAuthUseCase(config, nil, users, nil, nil, nil, nil, nil, nil, auditLogger(t), nil, nil, nil)
+		users.On("GetByEmail", mock.Anything, payload.Email).Return(nil, ErrNotFound)
+
+		err := useCase.RequestPasswordReset(context.Background(), payload)
+
+		assert.NoError(t, err)
+		users.AssertNotCalled(t, "CreatePasswordReset", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthUseCase_ResetPassword(t *testing.T) {
+	payload := ResetPasswordPayload{Token: "token", Password: "valar morghulis"}
+
+	t.Run("it should set the hash of the new password", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		useCase := NewAuthUseCase(AuthConfig{}, nil, users, nil, nil, nil, nil, nil, nil, auditLogger(t), nil, nil, nil)
+		users.On("ResetPassword", mock.Anything, hashToken("token"), mock.Anything).Return(int64(42), nil)
+
+		err := useCase.ResetPassword(context.Background(), payload)
+
+		assert.NoError(t, err)
+		password := Password{Hash: users.Calls[0].Arguments.Get(2).([]byte)}
+		assert.NoError(t, password.Verify(payload.Password))
+	})
+
+	t.Run("it should reject unknown or expired tokens", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		useCase := NewAuthUseCase(AuthConfig{}, nil, users, nil, nil, nil, nil, nil, nil, auditLogger(t), nil, nil, nil)
+		users.On("ResetPassword", mock.Anything, hashToken("token"), mock.Anything).Return(int64(0), ErrNotFound)
+
+		err := useCase.ResetPassword(context.Background(), payload)
+
+		assert.ErrorIs(t, err, ErrInvalidPasswordResetToken)
+	})
+}
+
+func TestAuthUseCase_CreateToken(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	config := AuthConfig{RefreshTokenExp: time.Hour}
+	payload := CreateUserTokenPayload{Email: "arya@winterfell.com", Password: "needle"}
+	newUser := func(t *testing.T) *User {
+		user := &User{ID: 42, IsActive: true}
+		assert.NoError(t, user.Password.Set("needle"))
+		return user
+	}
+
+	t.Run("it should record the login", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		mfa := NewMockMFARepository(t)
+		refreshTokens := NewMockRefreshTokensRepository(t)
+		token := NewMockTokenGenerator(t)
+		audit := NewMockAuditLogger(t)
+		sessions := NewMockSessionsRepository(t)
+		useCase := NewAuthUseCase(config, nil, users, refreshTokens, nil, mfa, token, nil, nil, audit, nil, nil, sessions)
+		useCase.now = func() time.Time { return now }
+		users.On("GetByEmail", mock.Anything, payload.Email).Return(newUser(t), nil)
+		mfa.On("GetTOTP", mock.Anything, int64(42)).Return(nil, ErrNotFound)
+		sessions.On("Create", mock.Anything, mock.Anything).Return(nil)
+		token.On("GenerateToken", mock.Anything, int64(42), mock.Anything).Return("access", nil)
+		refreshTokens.On("Create", mock.Anything, mock.Anything, int64(42), mock.Anything, now.Add(time.Hour)).Return(nil)
+		audit.On("Record", mock.Anything, AuditEvent{
+			ActorID:    42,
+			Action:     AuditLoginSucceeded,
+			TargetType: AuditTargetUser,
+			TargetID:   42,
+			Metadata:   map[string]any{"method": "password"},
+		})
+
+		_, err := useCase.CreateToken(context.Background(), payload)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("it should record a wrong password", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		audit := NewMockAuditLogger(t)
+		useCase := NewAuthUseCase(config, nil, users, nil, nil, nil, nil, nil, nil, audit, nil, nil, nil)
+		users.On("GetByEmail", mock.Anything, payload.Email).Return(newUser(t), nil)
+		audit.On("Record", mock.Anything, AuditEvent{
+			Action:     AuditLoginFailed,
+			TargetType: AuditTargetUser,
+			TargetID:   42,
+			Metadata:   map[string]any{"email": payload.Email, "reason": "invalid_password"},
+		})
+
+		_, err := useCase.CreateToken(context.Background(), CreateUserTokenPayload{Email: payload.Email, Password: "sword"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("it should record an unknown email", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		audit := NewMockAuditLogger(t)
+		useCase := NewAuthUseCase(config, nil, users, nil, nil, nil, nil, nil, nil, audit, nil, nil, nil)
+		users.On("GetByEmail", mock.Anything, payload.Email).Return(nil, ErrNotFound)
+		audit.On("Record", mock.Anything, AuditEvent{
+			Action:     AuditLoginFailed,
+			TargetType: AuditTargetUser,
+			Metadata:   map[string]any{"email": payload.Email, "reason": "unknown_email"},
+		})
+
+		_, err := useCase.CreateToken(context.Background(), payload)
+
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("it should reject banned users and keep their failures", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		attempts := NewMockLoginAttemptsRepository(t)
+		throttle := NewLoginThrottle(LoginThrottleConfig{}, attempts, nil)
+		useCase := NewAuthUseCase(config, nil, users, nil, nil, nil, nil, nil, nil, auditLogger(t), throttle, nil, nil)
+		user := newUser(t)
+		user.BannedAt = &now
+		users.On("GetByEmail", mock.Anything, payload.Email).Return(user, nil)
+		attempts.On("LockedFor", mock.Anything, mock.Anything).Return(time.Duration(0), nil)
+
+		_, err := useCase.CreateToken(context.Background(), payload)
+
+		assert.ErrorIs(t, err, ErrUserBanned)
+	})
+
+	t.Run("it should reject users who are not active", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		audit := NewMockAuditLogger(t)
+		useCase := NewAuthUseCase(config, nil, users, nil, nil, nil, nil, nil, nil, audit, nil, nil, nil)
+		user := newUser(t)
+		user.IsActive = false
+		users.On("GetByEmail", mock.Anything, payload.Email).Return(user, nil)
+		audit.On("Record", mock.Anything, AuditEvent{
+			Action:     AuditLoginFailed,
+			TargetType: AuditTargetUser,
+			TargetID:   42,
+			Metadata:   map[string]any{"email": payload.Email, "reason": "inactive"},
+		})
+
+		_, err := useCase.CreateToken(context.Background(), payload)
+
+		assert.ErrorIs(t, err, ErrUserInactive)
+	})
+}
+
+// auditLogger returns an AuditLogger for tests that do not check the audit
+// events.
+func auditLogger(t *testing.T) *MockAuditLogger {
+	audit := NewMockAuditLogger(t)
+	audit.On("Record", mock.Anything, mock.Anything).Maybe()
+	return audit
+}
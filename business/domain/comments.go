@@ -1,18 +1,84 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// MaxCommentDepth is the depth of the deepest reply allowed in a thread.
+// Top level comments have depth 0.
+const MaxCommentDepth = 5
+
+var ErrMaxCommentDepth = errors.New("maximum reply depth reached")
+
+// MaxThreadReplies is the most replies a comment thread returns at once.
+const MaxThreadReplies = 200
 
 type Comment struct {
-	ID        int64  `json:"id"`
-	PostID    int64  `json:"post_id"`
-	UserID    int64  `json:"user_id"`
-	Content   string `json:"content"`
-	CreatedAt string `json:"created_at"`
+	ID           int64  `json:"id"`
+	PostID       int64  `json:"post_id"`
+	UserID       int64  `json:"user_id"`
+	ParentID     *int64 `json:"parent_id,omitempty"`
+	Depth        int    `json:"depth"`
+	Content      string `json:"content"`
+	RepliesCount int64  `json:"replies_count"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+
+	User      User      `json:"user"`
+	Reactions Reactions `json:"reactions"`
+}
+
+// Reply returns a reply to the comment written by the given user, or
+// ErrMaxCommentDepth when the thread is already as deep as allowed.
+func (c *Comment) Reply(userID int64, content string) (*Comment, error) {
+	if c.Depth >= MaxCommentDepth {
+		return nil, ErrMaxCommentDepth
+	}
+	parentID := c.ID
+	return &Comment{
+		PostID:   c.PostID,
+		UserID:   userID,
+		ParentID: &parentID,
+		Depth:    c.Depth + 1,
+		Content:  content,
+	}, nil
+}
+
+type CommentsQuery struct {
+	Limit  int     `json:"limit" validate:"gte=1,lte=50"`
+	Cursor *Cursor `json:"-"`
+	// ViewerID is the user whose own reactions are reported in the page.
+	ViewerID int64 `json:"-"`
+}
+
+// CommentsPage is a page of comments. Top level comments of a post are
+// ordered newest first, replies oldest first. NextCursor is nil when there
+// are no more comments to fetch.
+type CommentsPage struct {
+	Comments   []Comment
+	NextCursor *Cursor
+}
 
-	User User `json:"user"`
+// CommentThread is the replies to a comment at every depth, each reply after
+// its parent and the siblings oldest first. Truncated tells the thread has
+// more than MaxThreadReplies replies, the others are loaded with the replies
+// of their parent.
+type CommentThread struct {
+	Replies   []Comment `json:"replies"`
+	Truncated bool      `json:"truncated"`
 }
 
 type CommentsRepository interface {
 	Create(ctx context.Context, comment *Comment) error
-	GetAllByPostID(ctx context.Context, postID int64) ([]Comment, error)
+	GetByID(ctx context.Context, id int64) (*Comment, error)
+	// GetPageByPostID returns the top level comments of a post.
+	GetPageByPostID(ctx context.Context, postID int64, query CommentsQuery) (CommentsPage, error)
+	// GetRepliesPage returns the direct replies to a comment.
+	GetRepliesPage(ctx context.Context, commentID int64, query CommentsQuery) (CommentsPage, error)
+	// GetThread returns the replies to a comment at every depth, with the
+	// own reactions of the viewer.
+	GetThread(ctx context.Context, commentID int64, viewerID int64) (CommentThread, error)
+	Update(ctx context.Context, comment *Comment) error
+	Delete(ctx context.Context, id int64) error
 }
@@ -7,3 +7,4 @@ import (
 var ErrNotFound = errors.New("record not found")
 var ErrDuplicateEmail = errors.New("email already exists")
 var ErrDuplicateUsername = errors.New("username already exists")
+var ErrEditConflict = errors.New("edit conflict")
@@ -1,17 +1,68 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FeedTimeLayout is the layout of the Since and Until feed filters. Times
+// are interpreted as UTC.
+const FeedTimeLayout = time.DateTime
 
 type PaginatedFeedQuery struct {
 	Limit  int      `json:"limit" validate:"gte=1,lte=50"`
-	Offset int      `json:"offset" validate:"gte=0"`
+	Offset int      `json:"offset" validate:"gte=0,excluded_with=Cursor"`
+	Cursor *Cursor  `json:"-"`
 	SortBy string   `form:"sort_by" validate:"oneof=asc desc"`
 	Tags   []string `json:"tags" validate:"max=5"`
 	Search string   `form:"search" validate:"max=100"`
-	Since  string   `json:"since"`
-	Until  string   `json:"until"`
+	Since  string   `json:"since" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+	Until  string   `json:"until" validate:"omitempty,datetime=2006-01-02 15:04:05"`
+}
+
+// IsAscending reports whether the oldest posts should come first.
+func (q PaginatedFeedQuery) IsAscending() bool {
+	return q.SortBy == "asc"
+}
+
+// TimeWindow returns the parsed Since and Until filters, nil when a filter
+// is not set or is malformed.
+func (q PaginatedFeedQuery) TimeWindow() (since *time.Time, until *time.Time) {
+	return parseFeedTime(q.Since), parseFeedTime(q.Until)
+}
+
+func parseFeedTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(FeedTimeLayout, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// validatePaginatedFeedQuery checks that the time window is not inverted.
+func validatePaginatedFeedQuery(sl validator.StructLevel) {
+	q := sl.Current().Interface().(PaginatedFeedQuery)
+
+	since, until := q.TimeWindow()
+	if since != nil && until != nil && until.Before(*since) {
+		sl.ReportError(q.Until, "Until", "until", "gtefield", "Since")
+	}
+}
+
+// FeedPage is a page of the feed. NextCursor is nil when there are no more
+// posts to fetch.
+type FeedPage struct {
+	Posts      []PostWithMetadata
+	NextCursor *Cursor
 }
 
 type FeedRepository interface {
-	GetUserFeed(ctx context.Context, userId int64, query PaginatedFeedQuery) ([]PostWithMetadata, error)
+	// GetUserFeed returns the home timeline of the user: their own posts
+	// merged with the posts of everyone they follow.
+	GetUserFeed(ctx context.Context, userId int64, query PaginatedFeedQuery) (FeedPage, error)
 }
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type InvitationCleanupConfig struct {
+	// GracePeriod is how long a registration is kept without being activated,
+	// giving the user time to have the invitation resent.
+	GracePeriod time.Duration
+}
+
+type InvitationCleanupResult struct {
+	ExpiredInvitations int64
+	UnactivatedUsers   int64
+}
+
+type InvitationCleaner struct {
+	config InvitationCleanupConfig
+	users  UsersRepository
+	now    func() time.Time
+}
+
+func NewInvitationCleaner(config InvitationCleanupConfig, users UsersRepository) *InvitationCleaner {
+	return &InvitationCleaner{
+		config: config,
+		users:  users,
+		now:    time.Now,
+	}
+}
+
+// Cleanup deletes the expired invitations, then the users who registered
+// more than the grace period ago and never activated their account. Users
+// with a pending invitation are kept until it expires.
+func (c *InvitationCleaner) Cleanup(ctx context.Context) (InvitationCleanupResult, error) {
+	var result InvitationCleanupResult
+
+	expired, err := c.users.DeleteExpiredInvitations(ctx)
+	if err != nil {
+		return result, fmt.Errorf("delete expired invitations: %w", err)
+	}
+	result.ExpiredInvitations = expired
+
+	purged, err := c.users.DeleteUnactivated(ctx, c.now().Add(-c.config.GracePeriod))
+	if err != nil {
+		return result, fmt.Errorf("delete unactivated users: %w", err)
+	}
+	result.UnactivatedUsers = purged
+
+	return result, nil
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInvitationCleaner_Cleanup(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	config := InvitationCleanupConfig{GracePeriod: 7 * 24 * time.Hour}
+
+	newCleaner := func(t *testing.T) (*InvitationCleaner, *MockUsersRepository) {
+		users := NewMockUsersRepository(t)
+		cleaner := NewInvitationCleaner(config, users)
+		cleaner.now = func() time.Time { return now }
+		return cleaner, users
+	}
+
+	t.Run("it should purge the users unactivated after the grace period", func(t *testing.T) {
+		cleaner, users := newCleaner(t)
+		users.On("DeleteExpiredInvitations", mock.Anything).Return(int64(3), nil)
+		users.On("DeleteUnactivated", mock.Anything, now.Add(-7*24*time.Hour)).Return(int64(2), nil)
+
+		result, err := cleaner.Cleanup(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, InvitationCleanupResult{ExpiredInvitations: 3, UnactivatedUsers: 2}, result)
+	})
+
+	t.Run("it should not purge users when the invitations are not deleted", func(t *testing.T) {
+		cleaner, users := newCleaner(t)
+		users.On("DeleteExpiredInvitations", mock.Anything).Return(int64(0), errors.New("connection reset"))
+
+		_, err := cleaner.Cleanup(context.Background())
+
+		assert.EqualError(t, err, "delete expired invitations: connection reset")
+		users.AssertNotCalled(t, "DeleteUnactivated", mock.Anything, mock.Anything)
+	})
+}
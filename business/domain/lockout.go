@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OutboxKindAccountLocked is the kind of the messages telling users their
+// account was locked after too many failed logins.
+const OutboxKindAccountLocked = "account_locked"
+
+var ErrLoginLocked = errors.New("too many failed logins")
+
+// LoginLockedError is returned for logins of a locked out account or IP.
+// It matches ErrLoginLocked.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("too many failed logins, retry in %s", e.RetryAfter)
+}
+
+func (e *LoginLockedError) Is(target error) bool {
+	return target == ErrLoginLocked
+}
+
+type LoginThrottleConfig struct {
+	// MaxAccountFailures failed logins of an account within Window lock the
+	// account out, MaxIPFailures failed logins from an IP lock the IP out.
+	MaxAccountFailures int
+	MaxIPFailures      int
+	// Window restarts with every failure, counters expire once no login
+	// failed for that long.
+	Window time.Duration
+	// Lockout is the first lockout, doubled for every failure past the
+	// maximum up to MaxLockout.
+	Lockout    time.Duration
+	MaxLockout time.Duration
+	// Delay slows down the answer to a failed login, once per failure of the
+	// account up to MaxDelay.
+	Delay    time.Duration
+	MaxDelay time.Duration
+	// FrontendURL is where the lockout email sends users to reset their
+	// password.
+	FrontendURL string
+}
+
+// LoginAttemptsRepository counts failed logins and the lockouts they cause,
+// by key.
+type LoginAttemptsRepository interface {
+	// Fail counts a failed login and returns the failures counted within
+	// the window.
+	Fail(ctx context.Context, key string, window time.Duration) (int, error)
+	Lock(ctx context.Context, key string, d time.Duration) error
+	// LockedFor returns how long the key stays locked out, 0 when it is not.
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// Reset forgets the failures of the key.
+	Reset(ctx context.Context, key string) error
+}
+
+// LoginThrottle protects logins from brute-force attacks. Failed logins are
+// counted per account and per IP, slowed down and eventually locked out
+// before the password is verified, so they are also cheap to reject. A nil
+// LoginThrottle does not throttle.
+type LoginThrottle struct {
+	config   LoginThrottleConfig
+	attempts LoginAttemptsRepository
+	outbox   OutboxRepository
+	sleep    func(ctx context.Context, d time.Duration)
+}
+
+func NewLoginThrottle(config LoginThrottleConfig, attempts LoginAttemptsRepository, outbox OutboxRepository) *LoginThrottle {
+	return &LoginThrottle{
+		config:   config,
+		attempts: attempts,
+		outbox:   outbox,
+		sleep:    sleep,
+	}
+}
+
+// Check returns a *LoginLockedError when the account or the IP is locked
+// out.
+func (t *LoginThrottle) Check(ctx context.Context, email string, ip string) error {
+	if t == nil {
+		return nil
+	}
+
+	lockedFor, err := t.attempts.LockedFor(ctx, accountKey(email))
+	if err != nil {
+		return err
+	}
+	if ip != "" {
+		ipLockedFor, err := t.attempts.LockedFor(ctx, ipKey(ip))
+		if err != nil {
+			return err
+		}
+		lockedFor = max(lockedFor, ipLockedFor)
+	}
+
+	if lockedFor > 0 {
+		return &LoginLockedError{RetryAfter: lockedFor}
+	}
+	return nil
+}
+
+// Failed counts a failed login, locks the account or the IP out once they
+// failed too often and waits before returning, longer with every failure.
+// The user is nil for unregistered emails, registered ones are emailed when
+// their account gets locked. It returns the lockout of the account, 0 when
+// it is not locked.
+func (t *LoginThrottle) Failed(ctx context.Context, email string, ip string, user *User) (time.Duration, error) {
+	if t == nil {
+		return 0, nil
+	}
+
+	failures, err := t.attempts.Fail(ctx, accountKey(email), t.config.Window)
+	if err != nil {
+		return 0, err
+	}
+
+	var lockout time.Duration
+	if failures >= t.config.MaxAccountFailures {
+		lockout = t.lockout(failures - t.config.MaxAccountFailures)
+		if err := t.attempts.Lock(ctx, accountKey(email), lockout); err != nil {
+			return 0, err
+		}
+		// Only the first lockout is emailed, not every one that follows.
+		if failures == t.config.MaxAccountFailures && user != nil {
+			if err := t.notify(ctx, user, lockout); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if ip != "" {
+		ipFailures, err := t.attempts.Fail(ctx, ipKey(ip), t.config.Window)
+		if err != nil {
+			return 0, err
+		}
+		if ipFailures >= t.config.MaxIPFailures {
+			if err := t.attempts.Lock(ctx, ipKey(ip), t.lockout(ipFailures-t.config.MaxIPFailures)); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	t.sleep(ctx, min(time.Duration(failures)*t.config.Delay, t.config.MaxDelay))
+	return lockout, nil
+}
+
+// Succeeded forgets the failures of the account. The failures of the IP are
+// kept, or logging into an own account would reset them.
+func (t *LoginThrottle) Succeeded(ctx context.Context, email string) error {
+	if t == nil {
+		return nil
+	}
+	return t.attempts.Reset(ctx, accountKey(email))
+}
+
+func (t *LoginThrottle) lockout(extraFailures int) time.Duration {
+	lockout := t.config.Lockout
+	for range extraFailures {
+		if lockout >= t.config.MaxLockout {
+			break
+		}
+		lockout *= 2
+	}
+	return min(lockout, t.config.MaxLockout)
+}
+
+func (t *LoginThrottle) notify(ctx context.Context, user *User, lockout time.Duration) error {
+	msg, err := NewOutboxMessage(OutboxKindAccountLocked, accountLockedMessage{
+		Username:  user.Username,
+		Email:     user.Email,
+		LockedFor: lockout.String(),
+		ResetURL:  fmt.Sprintf("%s/forgot-password", t.config.FrontendURL),
+	})
+	if err != nil {
+		return err
+	}
+	return t.outbox.Enqueue(ctx, msg)
+}
+
+// accountLockedMessage is the payload of OutboxKindAccountLocked messages.
+type accountLockedMessage struct {
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	LockedFor string `json:"locked_for"`
+	ResetURL  string `json:"reset_url"`
+}
+
+// AccountLockedHandler delivers the lockout emails queued by LoginThrottle.
+func (auth *AuthUseCase) AccountLockedHandler() OutboxHandler {
+	return OutboxHandler{
+		Deliver: func(ctx context.Context, payload json.RawMessage) error {
+			var msg accountLockedMessage
+			if err := json.Unmarshal(payload, &msg); err != nil {
+				return fmt.Errorf("decode account locked: %w", err)
+			}
+			return auth.mailer.Send(ctx, AccountLockedTemplate, msg.Username, msg.Email, AccountLocked{
+				Username:  msg.Username,
+				LockedFor: msg.LockedFor,
+				ResetURL:  msg.ResetURL,
+			})
+		},
+	}
+}
+
+func accountKey(email string) string {
+	return "login:account:" + strings.ToLower(email)
+}
+
+func ipKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
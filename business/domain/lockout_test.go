@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoginThrottle(t *testing.T) {
+	config := LoginThrottleConfig{
+		MaxAccountFailures: 3,
+		MaxIPFailures:      10,
+		Window:             time.Hour,
+		Lockout:            time.Minute,
+		MaxLockout:         5 * time.Minute,
+		Delay:              100 * time.Millisecond,
+		MaxDelay:           250 * time.Millisecond,
+		FrontendURL:        "http://localhost:5173",
+	}
+	email := "arya@winterfell.com"
+	newThrottle := func(attempts LoginAttemptsRepository, outbox OutboxRepository) (*LoginThrottle, *[]time.Duration) {
+		var delays []time.Duration
+		throttle := NewLoginThrottle(config, attempts, outbox)
+		throttle.sleep = func(ctx context.Context, d time.Duration) {
+			delays = append(delays, d)
+		}
+		return throttle, &delays
+	}
+
+	t.Run("it should slow down failures before the lockout", func(t *testing.T) {
+		attempts := NewMockLoginAttemptsRepository(t)
+		throttle, delays := newThrottle(attempts, nil)
+		attempts.On("Fail", mock.Anything, accountKey(email), time.Hour).Return(2, nil)
+		attempts.On("Fail", mock.Anything, ipKey("10.0.0.1"), time.Hour).Return(2, nil)
+
+		lockout, err := throttle.Failed(context.Background(), email, "10.0.0.1", nil)
+
+		assert.NoError(t, err)
+		assert.Zero(t, lockout)
+		assert.Equal(t, []time.Duration{200 * time.Millisecond}, *delays)
+	})
+
+	t.Run("it should lock the account and email the user once", func(t *testing.T) {
+		attempts := NewMockLoginAttemptsRepository(t)
+		outbox := NewMockOutboxRepository(t)
+		throttle, delays := newThrottle(attempts, outbox)
+		attempts.On("Fail", mock.Anything, accountKey(email), time.Hour).Return(3, nil)
+		attempts.On("Lock", mock.Anything, accountKey(email), time.Minute).Return(nil)
+		outbox.On("Enqueue", mock.Anything, mock.MatchedBy(func(msg OutboxMessage) bool {
+			return msg.Kind == OutboxKindAccountLocked
+		})).Return(nil).Once()
+
+		lockout, err := throttle.Failed(context.Background(), email, "", &User{Username: "arya", Email: email})
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Minute, lockout)
+		assert.Equal(t, []time.Duration{250 * time.Millisecond}, *delays)
+	})
+
+	t.Run("it should double the lockout up to the maximum", func(t *testing.T) {
+		for failures, want := range map[int]time.Duration{
+			4: 2 * time.Minute,
+			5: 4 * time.Minute,
+			6: 5 * time.Minute,
+			9: 5 * time.Minute,
+		} {
+			attempts := NewMockLoginAttemptsRepository(t)
+			throttle, _ := newThrottle(attempts, nil)
+			attempts.On("Fail", mock.Anything, accountKey(email), time.Hour).Return(failures, nil)
+			attempts.On("Lock", mock.Anything, accountKey(email), want).Return(nil)
+
+			lockout, err := throttle.Failed(context.Background(), email, "", &User{})
+
+			assert.NoError(t, err)
+			assert.Equal(t, want, lockout)
+		}
+	})
+
+	t.Run("it should lock the IP out", func(t *testing.T) {
+		attempts := NewMockLoginAttemptsRepository(t)
+		throttle, _ := newThrottle(attempts, nil)
+		attempts.On("Fail", mock.Anything, accountKey(email), time.Hour).Return(1, nil)
+		attempts.On("Fail", mock.Anything, ipKey("10.0.0.1"), time.Hour).Return(10, nil)
+		attempts.On("Lock", mock.Anything, ipKey("10.0.0.1"), time.Minute).Return(nil)
+
+		lockout, err := throttle.Failed(context.Background(), email, "10.0.0.1", nil)
+
+		assert.NoError(t, err)
+		assert.Zero(t, lockout)
+	})
+
+	t.Run("it should reject locked out logins", func(t *testing.T) {
+		attempts := NewMockLoginAttemptsRepository(t)
+		throttle, _ := newThrottle(attempts, nil)
+		attempts.On("LockedFor", mock.Anything, accountKey(email)).Return(time.Duration(0), nil)
+		attempts.On("LockedFor", mock.Anything, ipKey("10.0.0.1")).Return(30*time.Second, nil)
+
+		err := throttle.Check(context.Background(), email, "10.0.0.1")
+
+		assert.ErrorIs(t, err, ErrLoginLocked)
+		assert.Equal(t, &LoginLockedError{RetryAfter: 30 * time.Second}, err)
+	})
+
+	t.Run("it should not throttle when nil", func(t *testing.T) {
+		var throttle *LoginThrottle
+
+		assert.NoError(t, throttle.Check(context.Background(), email, "10.0.0.1"))
+		assert.NoError(t, throttle.Succeeded(context.Background(), email))
+	})
+}
+
+func TestAuthUseCase_CreateToken_Throttled(t *testing.T) {
+	payload := CreateUserTokenPayload{Email: "arya@winterfell.com", Password: "needle"}
+
+	t.Run("it should reject locked out accounts before checking the password", func(t *testing.T) {
+		attempts := NewMockLoginAttemptsRepository(t)
+		throttle := NewLoginThrottle(LoginThrottleConfig{}, attempts, nil)
+		useCase := NewAuthUseCase(AuthConfig{}, nil, nil, nil, nil, nil, nil, nil, nil, auditLogger(t), throttle, nil, nil)
+		attempts.On("LockedFor", mock.Anything, accountKey(payload.Email)).Return(time.Minute, nil)
+
+		_, err := useCase.CreateToken(context.Background(), payload)
+
+		assert.ErrorIs(t, err, ErrLoginLocked)
+	})
+
+	t.Run("it should record the lockout", func(t *testing.T) {
+		users := NewMockUsersRepository(t)
+		attempts := NewMockLoginAttemptsRepository(t)
+		audit := NewMockAuditLogger(t)
+		throttle := NewLoginThrottle(LoginThrottleConfig{MaxAccountFailures: 5, Lockout: time.Minute, MaxLockout: time.Hour}, attempts, nil)
+		throttle.sleep = func(ctx context.Context, d time.Duration) {}
+		useCase := NewAuthUseCase(AuthConfig{}, nil, users, nil, nil, nil, nil, nil, nil, audit, throttle, nil, nil)
+		attempts.On("LockedFor", mock.Anything, accountKey(payload.Email)).Return(time.Duration(0), nil)
+		attempts.On("Fail", mock.Anything, accountKey(payload.Email), time.Duration(0)).Return(6, nil)
+		attempts.On("Lock", mock.Anything, accountKey(payload.Email), 2*time.Minute).Return(nil)
+		users.On("GetByEmail", mock.Anything, payload.Email).Return(nil, ErrNotFound)
+		audit.On("Record", mock.Anything, mock.MatchedBy(func(event AuditEvent) bool {
+			return event.Action == AuditLoginFailed
+		}))
+		audit.On("Record", mock.Anything, mock.MatchedBy(func(event AuditEvent) bool {
+			return event.Action == AuditAccountLocked
+		})).Once()
+
+		_, err := useCase.CreateToken(context.Background(), payload)
+
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "context"
+
+// UserInvitationTemplate is the template of the email inviting a newly
+// registered user to activate their account.
+const UserInvitationTemplate = "user_invitation.tmpl"
+
+// PasswordResetTemplate is the template of the email with the link to reset
+// a forgotten password.
+const PasswordResetTemplate = "password_reset.tmpl"
+
+// AccountLockedTemplate is the template of the email telling a user their
+// account was locked after too many failed logins.
+const AccountLockedTemplate = "account_locked.tmpl"
+
+// Mailer sends emails rendered from a template. Send makes a single attempt
+// within the context, an error means the email could not be delivered and
+// the outbox retries it.
+type Mailer interface {
+	Send(ctx context.Context, templateFile, username, email string, data any) error
+}
+
+// UserInvitation is the data of UserInvitationTemplate.
+type UserInvitation struct {
+	Username      string
+	ActivationURL string
+}
+
+// PasswordReset is the data of PasswordResetTemplate.
+type PasswordReset struct {
+	Username string
+	ResetURL string
+}
+
+// AccountLocked is the data of AccountLockedTemplate.
+type AccountLocked struct {
+	Username  string
+	LockedFor string
+	ResetURL  string
+}
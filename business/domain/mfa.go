@@ -0,0 +1,334 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sergdort/Social/foundation/totp"
+)
+
+var ErrMFAAlreadyEnabled = errors.New("two-factor authentication already enabled")
+var ErrMFANotEnrolled = errors.New("two-factor authentication not enrolled")
+var ErrInvalidMFACode = errors.New("invalid two-factor authentication code")
+var ErrInvalidMFAChallenge = errors.New("invalid two-factor authentication challenge")
+
+const (
+	// RecoveryCodesCount is the number of recovery codes issued when
+	// two-factor authentication is enabled.
+	RecoveryCodesCount = 10
+
+	// MaxMFAChallengeAttempts is the number of codes that can be tried for
+	// one challenge, so codes cannot be guessed by brute force.
+	MaxMFAChallengeAttempts = 5
+
+	// totpSkew is the number of time steps a code is accepted for before
+	// and after its own, to allow for clock drift.
+	totpSkew = 1
+)
+
+// TOTPSecret is the TOTP secret of a user. It only protects the login once
+// confirmed with a code from the authenticator app.
+type TOTPSecret struct {
+	UserID       int64
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+}
+
+func (s *TOTPSecret) Enabled() bool {
+	return s.ConfirmedAt != nil
+}
+
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// MFAChallenge is returned instead of tokens by CreateToken for users with
+// two-factor authentication. It is completed with VerifyMFA.
+type MFAChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// MFAChallengeAttempt is a challenge with the number of codes tried for it,
+// including the current one.
+type MFAChallengeAttempt struct {
+	UserID   int64
+	Expiry   time.Time
+	Attempts int
+}
+
+// LoginResult holds the tokens of a login, or the challenge to complete
+// first when the user has two-factor authentication.
+type LoginResult struct {
+	Tokens    AuthTokens
+	Challenge *MFAChallenge
+}
+
+type MFACodePayload struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+type VerifyMFAPayload struct {
+	Challenge string `json:"mfa_challenge" validate:"required"`
+	// Code is either a code from the authenticator app or a recovery code.
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+type MFARepository interface {
+	GetTOTP(ctx context.Context, userID int64) (*TOTPSecret, error)
+	// SaveTOTP stores a new unconfirmed secret, replacing a pending one.
+	SaveTOTP(ctx context.Context, userID int64, secret string) error
+	// ConfirmTOTP enables the secret and replaces the recovery codes.
+	ConfirmTOTP(ctx context.Context, userID int64, recoveryCodes []string) error
+	DeleteTOTP(ctx context.Context, userID int64) error
+	// UseTOTPStep records the time step of a used code. It returns
+	// ErrNotFound when a code of that step or a later one was already used.
+	UseTOTPStep(ctx context.Context, userID int64, step int64) error
+	// UseRecoveryCode returns ErrNotFound when the code is unknown or used.
+	UseRecoveryCode(ctx context.Context, userID int64, code string) error
+	CreateChallenge(ctx context.Context, token string, userID int64, expiry time.Time) error
+	// AttemptChallenge counts an attempt at the challenge and returns it.
+	AttemptChallenge(ctx context.Context, token string) (*MFAChallengeAttempt, error)
+	DeleteChallenge(ctx context.Context, token string) error
+}
+
+// EnrollTOTP generates a new TOTP secret for the user. Two-factor
+// authentication is only enabled once the secret is confirmed by ConfirmTOTP.
+func (auth *AuthUseCase) EnrollTOTP(ctx context.Context, userID int64) (TOTPEnrollment, error) {
+	current, err := auth.mfa.GetTOTP(ctx, userID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return TOTPEnrollment{}, err
+	}
+	if current != nil && current.Enabled() {
+		return TOTPEnrollment{}, ErrMFAAlreadyEnabled
+	}
+
+	user, err := auth.users.GetByID(ctx, userID)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+	if err := auth.mfa.SaveTOTP(ctx, userID, secret); err != nil {
+		return TOTPEnrollment{}, err
+	}
+
+	return TOTPEnrollment{
+		Secret: secret,
+		URI:    totp.URI(auth.config.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication once the user proves their
+// authenticator app is set up, and returns the recovery codes. They are only
+// stored hashed, so this is the one time they can be shown.
+func (auth *AuthUseCase) ConfirmTOTP(ctx context.Context, userID int64, code string) ([]string, error) {
+	secret, err := auth.mfa.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrMFANotEnrolled
+		}
+		return nil, err
+	}
+	if secret.Enabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	if err := auth.verifyTOTPCode(ctx, secret, code); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, RecoveryCodesCount)
+	hashes := make([]string, RecoveryCodesCount)
+	for i := range codes {
+		if codes[i], err = newRecoveryCode(); err != nil {
+			return nil, err
+		}
+		hashes[i] = hashToken(normalizeRecoveryCode(codes[i]))
+	}
+
+	if err := auth.mfa.ConfirmTOTP(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	auth.mfaChanged(ctx, userID, AuditMFAEnabled)
+	return codes, nil
+}
+
+// DisableTOTP turns two-factor authentication off, after checking a code so
+// a stolen access token is not enough.
+func (auth *AuthUseCase) DisableTOTP(ctx context.Context, userID int64, code string) error {
+	secret, err := auth.enabledTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := auth.verifyMFACode(ctx, secret, code); err != nil {
+		return err
+	}
+	if err := auth.mfa.DeleteTOTP(ctx, userID); err != nil {
+		return err
+	}
+	auth.mfaChanged(ctx, userID, AuditMFADisabled)
+	return nil
+}
+
+func (auth *AuthUseCase) mfaChanged(ctx context.Context, userID int64, action string) {
+	auth.audit.Record(ctx, AuditEvent{
+		ActorID:    userID,
+		Action:     action,
+		TargetType: AuditTargetUser,
+		TargetID:   userID,
+		Metadata:   map[string]any{"method": "totp"},
+	})
+}
+
+// VerifyMFA completes the challenge returned by CreateToken with a code and
+// issues the tokens. The user is checked again, so a user banned or
+// deactivated since the challenge was issued gets ErrUserBanned or
+// ErrUserInactive.
+func (auth *AuthUseCase) VerifyMFA(ctx context.Context, payload VerifyMFAPayload) (AuthTokens, error) {
+	challenge := hashToken(payload.Challenge)
+
+	attempt, err := auth.mfa.AttemptChallenge(ctx, challenge)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return AuthTokens{}, ErrInvalidMFAChallenge
+		}
+		return AuthTokens{}, err
+	}
+
+	if attempt.Attempts > MaxMFAChallengeAttempts || !attempt.Expiry.After(auth.now()) {
+		if err := auth.mfa.DeleteChallenge(ctx, challenge); err != nil {
+			return AuthTokens{}, err
+		}
+		return AuthTokens{}, ErrInvalidMFAChallenge
+	}
+
+	secret, err := auth.enabledTOTP(ctx, attempt.UserID)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+	if err := auth.verifyMFACode(ctx, secret, payload.Code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			auth.loginFailed(ctx, attempt.UserID, "", "invalid_mfa_code")
+		}
+		return AuthTokens{}, err
+	}
+
+	if err := auth.mfa.DeleteChallenge(ctx, challenge); err != nil {
+		return AuthTokens{}, err
+	}
+
+	user, err := auth.users.GetByID(ctx, attempt.UserID)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+	if user.BannedAt != nil {
+		auth.loginFailed(ctx, user.ID, "", "banned")
+		return AuthTokens{}, ErrUserBanned
+	}
+	if !user.IsActive {
+		auth.loginFailed(ctx, user.ID, "", "inactive")
+		return AuthTokens{}, ErrUserInactive
+	}
+
+	tokens, err := auth.issueTokens(ctx, attempt.UserID)
+	if err != nil {
+		return AuthTokens{}, err
+	}
+	auth.loggedIn(ctx, attempt.UserID, "mfa")
+	return tokens, nil
+}
+
+// challenge returns the challenge to complete the login of the user, or nil
+// when the user has no two-factor authentication.
+func (auth *AuthUseCase) challenge(ctx context.Context, userID int64) (*MFAChallenge, error) {
+	secret, err := auth.mfa.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !secret.Enabled() {
+		return nil, nil
+	}
+
+	challenge := MFAChallenge{
+		Token:     uuid.New().String(),
+		ExpiresAt: auth.now().Add(auth.config.MFAChallengeExp),
+	}
+	if err := auth.mfa.CreateChallenge(ctx, hashToken(challenge.Token), userID, challenge.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (auth *AuthUseCase) enabledTOTP(ctx context.Context, userID int64) (*TOTPSecret, error) {
+	secret, err := auth.mfa.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrMFANotEnrolled
+		}
+		return nil, err
+	}
+	if !secret.Enabled() {
+		return nil, ErrMFANotEnrolled
+	}
+	return secret, nil
+}
+
+// verifyMFACode accepts a code from the authenticator app or an unused
+// recovery code.
+func (auth *AuthUseCase) verifyMFACode(ctx context.Context, secret *TOTPSecret, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return auth.verifyTOTPCode(ctx, secret, code)
+	}
+
+	err := auth.mfa.UseRecoveryCode(ctx, secret.UserID, hashToken(normalizeRecoveryCode(code)))
+	if errors.Is(err, ErrNotFound) {
+		return ErrInvalidMFACode
+	}
+	return err
+}
+
+// verifyTOTPCode checks the code against the secret. A code is only accepted
+// once, so an intercepted code cannot be replayed.
+func (auth *AuthUseCase) verifyTOTPCode(ctx context.Context, secret *TOTPSecret, code string) error {
+	step, ok := totp.Verify(secret.Secret, strings.TrimSpace(code), auth.now(), totpSkew)
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	err := auth.mfa.UseTOTPStep(ctx, secret.UserID, step)
+	if errors.Is(err, ErrNotFound) {
+		return ErrInvalidMFACode
+	}
+	return err
+}
+
+// newRecoveryCode returns a random code formatted as two groups of five
+// characters, like "k3j8d-9xq2m".
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
@@ -0,0 +1,268 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sergdort/Social/foundation/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthUseCase_MFA(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	confirmedAt := now.Add(-time.Hour)
+	const secret = "JBSWY3DPEHPK3PXP"
+	config := AuthConfig{
+		RefreshTokenExp: time.Hour,
+		MFAChallengeExp: 5 * time.Minute,
+		MFAIssuer:       "social",
+	}
+	enabled := &TOTPSecret{UserID: 42, Secret: secret, ConfirmedAt: &confirmedAt}
+
+	type mocks struct {
+		users         *MockUsersRepository
+		mfa           *MockMFARepository
+		refreshTokens *MockRefreshTokensRepository
+		token         *MockTokenGenerator
+		sessions      *MockSessionsRepository
+	}
+	newUseCase := func(t *testing.T) (*AuthUseCase, mocks) {
+		m := mocks{
+			users:         NewMockUsersRepository(t),
+			mfa:           NewMockMFARepository(t),
+			refreshTokens: NewMockRefreshTokensRepository(t),
+			token:         NewMockTokenGenerator(t),
+			sessions:      NewMockSessionsRepository(t),
+		}
+		useCase := NewAuthUseCase(config, nil, m.users, m.refreshTokens, nil, m.mfa, m.token, nil, nil, auditLogger(t), nil, nil, m.sessions)
+		useCase.now = func() time.Time { return now }
+		return useCase, m
+	}
+	code := func(t *testing.T, at time.Time) string {
+		code, err := totp.Code(secret, at)
+		assert.NoError(t, err)
+		return code
+	}
+
+	t.Run("it should return a challenge instead of tokens when mfa is enabled", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		user := &User{ID: 42, IsActive: true}
+		assert.NoError(t, user.Password.Set("needle"))
+		m.users.On("GetByEmail", mock.Anything, "arya@winterfell.com").Return(user, nil)
+		m.mfa.On("GetTOTP", mock.Anything, int64(42)).Return(enabled, nil)
+		m.mfa.On("CreateChallenge", mock.Anything, mock.Anything, int64(42), now.Add(5*time.Minute)).Return(nil)
+
+		login, err := useCase.CreateToken(context.Background(), CreateUserTokenPayload{
+			Email:    "arya@winterfell.com",
+			Password: "needle",
+		})
+
+		assert.NoError(t, err)
+		assert.Empty(t, login.Tokens.AccessToken)
+		assert.NotNil(t, login.Challenge)
+		assert.Equal(t, now.Add(5*time.Minute), login.Challenge.ExpiresAt)
+		m.mfa.AssertCalled(t, "CreateChallenge", mock.Anything, hashToken(login.Challenge.Token), int64(42), now.Add(5*time.Minute))
+		m.token.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("it should issue tokens when mfa is not confirmed", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		user := &User{ID: 42, IsActive: true}
+		assert.NoError(t, user.Password.Set("needle"))
+		m.users.On("GetByEmail", mock.Anything, "arya@winterfell.com").Return(user, nil)
+		m.mfa.On("GetTOTP", mock.Anything, int64(42)).Return(&TOTPSecret{UserID: 42, Secret: secret}, nil)
+		m.sessions.On("Create", mock.Anything, mock.Anything).Return(nil)
+		m.token.On("GenerateToken", mock.Anything, int64(42), mock.Anything).Return("access", nil)
+		m.refreshTokens.On("Create", mock.Anything, mock.Anything, int64(42), mock.Anything, now.Add(time.Hour)).Return(nil)
+
+		login, err := useCase.CreateToken(context.Background(), CreateUserTokenPayload{
+			Email:    "arya@winterfell.com",
+			Password: "needle",
+		})
+
+		assert.NoError(t, err)
+		assert.Nil(t, login.Challenge)
+		assert.Equal(t, "access", login.Tokens.AccessToken)
+	})
+
+	t.Run("it should enroll with an otpauth uri", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.mfa.On("GetTOTP", mock.Anything, int64(42)).Return(nil, ErrNotFound)
+		m.users.On("GetByID", mock.Anything, int64(42)).Return(&User{ID: 42, Email: "arya@winterfell.com"}, nil)
+		m.mfa.On("SaveTOTP", mock.Anything, int64(42), mock.Anything).Return(nil)
+
+		enrollment, err := useCase.EnrollTOTP(context.Background(), 42)
+
+		assert.NoError(t, err)
+		assert.Equal(t, totp.URI("social", "arya@winterfell.com", enrollment.Secret), enrollment.URI)
+		m.mfa.AssertCalled(t, "SaveTOTP", mock.Anything, int64(42), enrollment.Secret)
+	})
+
+	t.Run("it should not enroll twice", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.mfa.On("GetTOTP", mock.Anything, int64(42)).Return(enabled, nil)
+
+		_, err := useCase.EnrollTOTP(context.Background(), 42)
+
+		assert.ErrorIs(t, err, ErrMFAAlreadyEnabled)
+	})
+
+	t.Run("it should confirm the enrollment with a code and store hashed recovery codes", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.mfa.On("GetTOTP", mock.Anything, int64(42)).Return(&TOTPSecret{UserID: 42, Secret: secret}, nil)
+		m.mfa.On("UseTOTPStep", mock.Anything, int64(42), totp.Step(now)).Return(nil)
+		m.mfa.On("ConfirmTOTP", mock.Anything, int64(42), mock.Anything).Return(nil)
+
+		codes, err := useCase.ConfirmTOTP(context.Background(), 42, code(t, now))
+
+		assert.NoError(t, err)
+		assert.Len(t, codes, RecoveryCodesCount)
+		hashes := m.mfa.Calls[2].Arguments.Get(2).([]string)
+		assert.Equal(t, hashToken(normalizeRecoveryCode(codes[0])), hashes[0])
+	})
+
+	t.Run("it should not confirm the enrollment with a wrong code", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.mfa.On("GetTOTP", mock.Anything, int64(42)).Return(&TOTPSecret{UserID: 42, Secret: secret}, nil)
+
+		_, err := useCase.ConfirmTOTP(context.Background(), 42, code(t, now.Add(-time.Hour)))
+
+		assert.ErrorIs(t, err, ErrInvalidMFACode)
+	})
+
+	t.Run("it should issue tokens once the challenge is completed", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.mfa.On("AttemptChallenge", mock.Anything, hashToken("challenge")).Return(&MFAChallengeAttempt{
+			UserID:   42,
+			Expiry:   now.Add(time.Minute),
+			Attempts: 1,
+		}, nil)
+		m.mfa.On("GetTOTP", mock.Anything, int64(42)).Return(enabled, nil)
+		// A code of the previous step is still accepted.
+		m.mfa.On("UseTOTPStep", mock.Anything, int64(42), totp.Step(now)-1).Return(nil)
+		m.mfa.On("DeleteChallenge", mock.Anything, hashToken("challenge")).Return(nil)
+		m.users.On("GetByID", mock.Anything, int64(42)).Return(&User{ID: 42, IsActive: true}, nil)
+		m.sessions.On("Create", mock.Anything, mock.Anything).Return(nil)
+		m.token.On("GenerateToken", mock.Anything, int64(42), mock.Anything).Return("access", nil)
+		m.refreshTokens.On("Create", mock.Anything, mock.Anything, int64(42), mock.Anything, now.Add(time.Hour)).Return(nil)
+
+		tokens, err := useCase.VerifyMFA(context.Background(), VerifyMFAPayload{
+			Challenge: "challenge",
+			Code:      code(t, now.Add(-totp.Period)),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "access", tokens.AccessToken)
+	})
+
+	t.Run("it should reject a replayed code", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.mfa.On("AttemptChallenge", mock.Anything, hashToken("challenge")).Return(&MFAChallengeAttempt{
+			UserID:   42,
+			Expiry:   now.Add(time.Minute),
+			Attempts: 2,
+		}, nil)
+		m.mfa.On("GetTOTP", mock.Anything, int64(42)).Return(enabled, nil)
+		m.mfa.On("UseTOTPStep", mock.Anything, int64(42), totp.Step(now)).Return(ErrNotFound)
+
+		_, err := useCase.VerifyMFA(context.Background(), VerifyMFAPayload{
+			Challenge: "challenge",
+			Code:      code(t, now),
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidMFACode)
+	})
+
+	t.Run("it should accept a recovery code", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.mfa.On("AttemptChallenge", mock.Anything, hashToken("challenge")).Return(&MFAChallengeAttempt{
+			UserID:   42,
+			Expiry:   now.Add(time.Minute),
+			Attempts: 1,
+		}, nil)
+		m.mfa.On("GetTOTP", mock.Anything, int64(42)).Return(enabled, nil)
+		m.mfa.On("UseRecoveryCode", mock.Anything, int64(42), hashToken("k3j8d9xq2m")).Return(nil)
+		m.mfa.On("DeleteChallenge", mock.Anything, hashToken("challenge")).Return(nil)
+		m.users.On("GetByID", mock.Anything, int64(42)).Return(&User{ID: 42, IsActive: true}, nil)
+		m.sessions.On("Create", mock.Anything, mock.Anything).Return(nil)
+		m.token.On("GenerateToken", mock.Anything, int64(42), mock.Anything).Return("access", nil)
+		m.refreshTokens.On("Create", mock.Anything, mock.Anything, int64(42), mock.Anything, mock.Anything).Return(nil)
+
+		_, err := useCase.VerifyMFA(context.Background(), VerifyMFAPayload{
+			Challenge: "challenge",
+			Code:      "K3J8D-9XQ2M",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("it should not issue tokens to a user banned or deactivated since the challenge", func(t *testing.T) {
+		bannedAt := now.Add(-time.Minute)
+		tests := []struct {
+			name string
+			user *User
+			err  error
+		}{
+			{name: "banned", user: &User{ID: 42, IsActive: true, BannedAt: &bannedAt}, err: ErrUserBanned},
+			{name: "inactive", user: &User{ID: 42, IsActive: false}, err: ErrUserInactive},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				useCase, m := newUseCase(t)
+				m.mfa.On("AttemptChallenge", mock.Anything, hashToken("challenge")).Return(&MFAChallengeAttempt{
+					UserID:   42,
+					Expiry:   now.Add(time.Minute),
+					Attempts: 1,
+				}, nil)
+				m.mfa.On("GetTOTP", mock.Anything, int64(42)).Return(enabled, nil)
+				m.mfa.On("UseTOTPStep", mock.Anything, int64(42), totp.Step(now)).Return(nil)
+				m.mfa.On("DeleteChallenge", mock.Anything, hashToken("challenge")).Return(nil)
+				m.users.On("GetByID", mock.Anything, int64(42)).Return(tt.user, nil)
+
+				_, err := useCase.VerifyMFA(context.Background(), VerifyMFAPayload{
+					Challenge: "challenge",
+					Code:      code(t, now),
+				})
+
+				assert.ErrorIs(t, err, tt.err)
+				m.token.AssertNotCalled(t, "GenerateToken", mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("it should drop the challenge after too many attempts", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.mfa.On("AttemptChallenge", mock.Anything, hashToken("challenge")).Return(&MFAChallengeAttempt{
+			UserID:   42,
+			Expiry:   now.Add(time.Minute),
+			Attempts: MaxMFAChallengeAttempts + 1,
+		}, nil)
+		m.mfa.On("DeleteChallenge", mock.Anything, hashToken("challenge")).Return(nil)
+
+		_, err := useCase.VerifyMFA(context.Background(), VerifyMFAPayload{
+			Challenge: "challenge",
+			Code:      code(t, now),
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidMFAChallenge)
+	})
+
+	t.Run("it should reject an expired challenge", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.mfa.On("AttemptChallenge", mock.Anything, hashToken("challenge")).Return(&MFAChallengeAttempt{
+			UserID:   42,
+			Expiry:   now,
+			Attempts: 1,
+		}, nil)
+		m.mfa.On("DeleteChallenge", mock.Anything, hashToken("challenge")).Return(nil)
+
+		_, err := useCase.VerifyMFA(context.Background(), VerifyMFAPayload{
+			Challenge: "challenge",
+			Code:      code(t, now),
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidMFAChallenge)
+	})
+}
@@ -0,0 +1,70 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAuditLogger is an autogenerated mock type for the AuditLogger type
+type MockAuditLogger struct {
+	mock.Mock
+}
+
+type MockAuditLogger_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuditLogger) EXPECT() *MockAuditLogger_Expecter {
+	return &MockAuditLogger_Expecter{mock: &_m.Mock}
+}
+
+// Record provides a mock function with given fields: ctx, event
+func (_m *MockAuditLogger) Record(ctx context.Context, event AuditEvent) {
+	_m.Called(ctx, event)
+}
+
+// MockAuditLogger_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type MockAuditLogger_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event AuditEvent
+func (_e *MockAuditLogger_Expecter) Record(ctx interface{}, event interface{}) *MockAuditLogger_Record_Call {
+	return &MockAuditLogger_Record_Call{Call: _e.mock.On("Record", ctx, event)}
+}
+
+func (_c *MockAuditLogger_Record_Call) Run(run func(ctx context.Context, event AuditEvent)) *MockAuditLogger_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(AuditEvent))
+	})
+	return _c
+}
+
+func (_c *MockAuditLogger_Record_Call) Return() *MockAuditLogger_Record_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockAuditLogger_Record_Call) RunAndReturn(run func(context.Context, AuditEvent)) *MockAuditLogger_Record_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewMockAuditLogger creates a new instance of MockAuditLogger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuditLogger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuditLogger {
+	mock := &MockAuditLogger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,140 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAuditRepository is an autogenerated mock type for the AuditRepository type
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+type MockAuditRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuditRepository) EXPECT() *MockAuditRepository_Expecter {
+	return &MockAuditRepository_Expecter{mock: &_m.Mock}
+}
+
+// Append provides a mock function with given fields: ctx, event
+func (_m *MockAuditRepository) Append(ctx context.Context, event AuditEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Append")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, AuditEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockAuditRepository_Append_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Append'
+type MockAuditRepository_Append_Call struct {
+	*mock.Call
+}
+
+// Append is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event AuditEvent
+func (_e *MockAuditRepository_Expecter) Append(ctx interface{}, event interface{}) *MockAuditRepository_Append_Call {
+	return &MockAuditRepository_Append_Call{Call: _e.mock.On("Append", ctx, event)}
+}
+
+func (_c *MockAuditRepository_Append_Call) Run(run func(ctx context.Context, event AuditEvent)) *MockAuditRepository_Append_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(AuditEvent))
+	})
+	return _c
+}
+
+func (_c *MockAuditRepository_Append_Call) Return(_a0 error) *MockAuditRepository_Append_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockAuditRepository_Append_Call) RunAndReturn(run func(context.Context, AuditEvent) error) *MockAuditRepository_Append_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Query provides a mock function with given fields: ctx, query
+func (_m *MockAuditRepository) Query(ctx context.Context, query AuditQuery) (AuditPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Query")
+	}
+
+	var r0 AuditPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, AuditQuery) (AuditPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, AuditQuery) AuditPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Get(0).(AuditPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, AuditQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAuditRepository_Query_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Query'
+type MockAuditRepository_Query_Call struct {
+	*mock.Call
+}
+
+// Query is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query AuditQuery
+func (_e *MockAuditRepository_Expecter) Query(ctx interface{}, query interface{}) *MockAuditRepository_Query_Call {
+	return &MockAuditRepository_Query_Call{Call: _e.mock.On("Query", ctx, query)}
+}
+
+func (_c *MockAuditRepository_Query_Call) Run(run func(ctx context.Context, query AuditQuery)) *MockAuditRepository_Query_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(AuditQuery))
+	})
+	return _c
+}
+
+func (_c *MockAuditRepository_Query_Call) Return(_a0 AuditPage, _a1 error) *MockAuditRepository_Query_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAuditRepository_Query_Call) RunAndReturn(run func(context.Context, AuditQuery) (AuditPage, error)) *MockAuditRepository_Query_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAuditRepository creates a new instance of MockAuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuditRepository {
+	mock := &MockAuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -68,29 +68,76 @@ func (_c *MockCommentsRepository_Create_Call) RunAndReturn(run func(context.Cont
 	return _c
 }
 
-// GetAllByPostID provides a mock function with given fields: ctx, postID
-func (_m *MockCommentsRepository) GetAllByPostID(ctx context.Context, postID int64) ([]Comment, error) {
-	ret := _m.Called(ctx, postID)
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockCommentsRepository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllByPostID")
+		panic("no return value specified for Delete")
 	}
 
-	var r0 []Comment
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCommentsRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockCommentsRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockCommentsRepository_Expecter) Delete(ctx interface{}, id interface{}) *MockCommentsRepository_Delete_Call {
+	return &MockCommentsRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockCommentsRepository_Delete_Call) Run(run func(ctx context.Context, id int64)) *MockCommentsRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_Delete_Call) Return(_a0 error) *MockCommentsRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCommentsRepository_Delete_Call) RunAndReturn(run func(context.Context, int64) error) *MockCommentsRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *MockCommentsRepository) GetByID(ctx context.Context, id int64) (*Comment, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *Comment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]Comment, error)); ok {
-		return rf(ctx, postID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*Comment, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64) []Comment); ok {
-		r0 = rf(ctx, postID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *Comment); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]Comment)
+			r0 = ret.Get(0).(*Comment)
 		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, postID)
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -98,31 +145,252 @@ func (_m *MockCommentsRepository) GetAllByPostID(ctx context.Context, postID int
 	return r0, r1
 }
 
-// MockCommentsRepository_GetAllByPostID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllByPostID'
-type MockCommentsRepository_GetAllByPostID_Call struct {
+// MockCommentsRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockCommentsRepository_GetByID_Call struct {
 	*mock.Call
 }
 
-// GetAllByPostID is a helper method to define mock.On call
+// GetByID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - postID int64
-func (_e *MockCommentsRepository_Expecter) GetAllByPostID(ctx interface{}, postID interface{}) *MockCommentsRepository_GetAllByPostID_Call {
-	return &MockCommentsRepository_GetAllByPostID_Call{Call: _e.mock.On("GetAllByPostID", ctx, postID)}
+//   - id int64
+func (_e *MockCommentsRepository_Expecter) GetByID(ctx interface{}, id interface{}) *MockCommentsRepository_GetByID_Call {
+	return &MockCommentsRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
 }
 
-func (_c *MockCommentsRepository_GetAllByPostID_Call) Run(run func(ctx context.Context, postID int64)) *MockCommentsRepository_GetAllByPostID_Call {
+func (_c *MockCommentsRepository_GetByID_Call) Run(run func(ctx context.Context, id int64)) *MockCommentsRepository_GetByID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(int64))
 	})
 	return _c
 }
 
-func (_c *MockCommentsRepository_GetAllByPostID_Call) Return(_a0 []Comment, _a1 error) *MockCommentsRepository_GetAllByPostID_Call {
+func (_c *MockCommentsRepository_GetByID_Call) Return(_a0 *Comment, _a1 error) *MockCommentsRepository_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCommentsRepository_GetByID_Call) RunAndReturn(run func(context.Context, int64) (*Comment, error)) *MockCommentsRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPageByPostID provides a mock function with given fields: ctx, postID, query
+func (_m *MockCommentsRepository) GetPageByPostID(ctx context.Context, postID int64, query CommentsQuery) (CommentsPage, error) {
+	ret := _m.Called(ctx, postID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPageByPostID")
+	}
+
+	var r0 CommentsPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, CommentsQuery) (CommentsPage, error)); ok {
+		return rf(ctx, postID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, CommentsQuery) CommentsPage); ok {
+		r0 = rf(ctx, postID, query)
+	} else {
+		r0 = ret.Get(0).(CommentsPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, CommentsQuery) error); ok {
+		r1 = rf(ctx, postID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCommentsRepository_GetPageByPostID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPageByPostID'
+type MockCommentsRepository_GetPageByPostID_Call struct {
+	*mock.Call
+}
+
+// GetPageByPostID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - postID int64
+//   - query CommentsQuery
+func (_e *MockCommentsRepository_Expecter) GetPageByPostID(ctx interface{}, postID interface{}, query interface{}) *MockCommentsRepository_GetPageByPostID_Call {
+	return &MockCommentsRepository_GetPageByPostID_Call{Call: _e.mock.On("GetPageByPostID", ctx, postID, query)}
+}
+
+func (_c *MockCommentsRepository_GetPageByPostID_Call) Run(run func(ctx context.Context, postID int64, query CommentsQuery)) *MockCommentsRepository_GetPageByPostID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(CommentsQuery))
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_GetPageByPostID_Call) Return(_a0 CommentsPage, _a1 error) *MockCommentsRepository_GetPageByPostID_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockCommentsRepository_GetAllByPostID_Call) RunAndReturn(run func(context.Context, int64) ([]Comment, error)) *MockCommentsRepository_GetAllByPostID_Call {
+func (_c *MockCommentsRepository_GetPageByPostID_Call) RunAndReturn(run func(context.Context, int64, CommentsQuery) (CommentsPage, error)) *MockCommentsRepository_GetPageByPostID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRepliesPage provides a mock function with given fields: ctx, commentID, query
+func (_m *MockCommentsRepository) GetRepliesPage(ctx context.Context, commentID int64, query CommentsQuery) (CommentsPage, error) {
+	ret := _m.Called(ctx, commentID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRepliesPage")
+	}
+
+	var r0 CommentsPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, CommentsQuery) (CommentsPage, error)); ok {
+		return rf(ctx, commentID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, CommentsQuery) CommentsPage); ok {
+		r0 = rf(ctx, commentID, query)
+	} else {
+		r0 = ret.Get(0).(CommentsPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, CommentsQuery) error); ok {
+		r1 = rf(ctx, commentID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCommentsRepository_GetRepliesPage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRepliesPage'
+type MockCommentsRepository_GetRepliesPage_Call struct {
+	*mock.Call
+}
+
+// GetRepliesPage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - commentID int64
+//   - query CommentsQuery
+func (_e *MockCommentsRepository_Expecter) GetRepliesPage(ctx interface{}, commentID interface{}, query interface{}) *MockCommentsRepository_GetRepliesPage_Call {
+	return &MockCommentsRepository_GetRepliesPage_Call{Call: _e.mock.On("GetRepliesPage", ctx, commentID, query)}
+}
+
+func (_c *MockCommentsRepository_GetRepliesPage_Call) Run(run func(ctx context.Context, commentID int64, query CommentsQuery)) *MockCommentsRepository_GetRepliesPage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(CommentsQuery))
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_GetRepliesPage_Call) Return(_a0 CommentsPage, _a1 error) *MockCommentsRepository_GetRepliesPage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCommentsRepository_GetRepliesPage_Call) RunAndReturn(run func(context.Context, int64, CommentsQuery) (CommentsPage, error)) *MockCommentsRepository_GetRepliesPage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetThread provides a mock function with given fields: ctx, commentID, viewerID
+func (_m *MockCommentsRepository) GetThread(ctx context.Context, commentID int64, viewerID int64) (CommentThread, error) {
+	ret := _m.Called(ctx, commentID, viewerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetThread")
+	}
+
+	var r0 CommentThread
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (CommentThread, error)); ok {
+		return rf(ctx, commentID, viewerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) CommentThread); ok {
+		r0 = rf(ctx, commentID, viewerID)
+	} else {
+		r0 = ret.Get(0).(CommentThread)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, commentID, viewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCommentsRepository_GetThread_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetThread'
+type MockCommentsRepository_GetThread_Call struct {
+	*mock.Call
+}
+
+// GetThread is a helper method to define mock.On call
+//   - ctx context.Context
+//   - commentID int64
+//   - viewerID int64
+func (_e *MockCommentsRepository_Expecter) GetThread(ctx interface{}, commentID interface{}, viewerID interface{}) *MockCommentsRepository_GetThread_Call {
+	return &MockCommentsRepository_GetThread_Call{Call: _e.mock.On("GetThread", ctx, commentID, viewerID)}
+}
+
+func (_c *MockCommentsRepository_GetThread_Call) Run(run func(ctx context.Context, commentID int64, viewerID int64)) *MockCommentsRepository_GetThread_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_GetThread_Call) Return(_a0 CommentThread, _a1 error) *MockCommentsRepository_GetThread_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCommentsRepository_GetThread_Call) RunAndReturn(run func(context.Context, int64, int64) (CommentThread, error)) *MockCommentsRepository_GetThread_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, comment
+func (_m *MockCommentsRepository) Update(ctx context.Context, comment *Comment) error {
+	ret := _m.Called(ctx, comment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *Comment) error); ok {
+		r0 = rf(ctx, comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCommentsRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockCommentsRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - comment *Comment
+func (_e *MockCommentsRepository_Expecter) Update(ctx interface{}, comment interface{}) *MockCommentsRepository_Update_Call {
+	return &MockCommentsRepository_Update_Call{Call: _e.mock.On("Update", ctx, comment)}
+}
+
+func (_c *MockCommentsRepository_Update_Call) Run(run func(ctx context.Context, comment *Comment)) *MockCommentsRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*Comment))
+	})
+	return _c
+}
+
+func (_c *MockCommentsRepository_Update_Call) Return(_a0 error) *MockCommentsRepository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCommentsRepository_Update_Call) RunAndReturn(run func(context.Context, *Comment) error) *MockCommentsRepository_Update_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,247 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockLoginAttemptsRepository is an autogenerated mock type for the LoginAttemptsRepository type
+type MockLoginAttemptsRepository struct {
+	mock.Mock
+}
+
+type MockLoginAttemptsRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLoginAttemptsRepository) EXPECT() *MockLoginAttemptsRepository_Expecter {
+	return &MockLoginAttemptsRepository_Expecter{mock: &_m.Mock}
+}
+
+// Fail provides a mock function with given fields: ctx, key, window
+func (_m *MockLoginAttemptsRepository) Fail(ctx context.Context, key string, window time.Duration) (int, error) {
+	ret := _m.Called(ctx, key, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fail")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (int, error)); ok {
+		return rf(ctx, key, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) int); ok {
+		r0 = rf(ctx, key, window)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, key, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLoginAttemptsRepository_Fail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fail'
+type MockLoginAttemptsRepository_Fail_Call struct {
+	*mock.Call
+}
+
+// Fail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - window time.Duration
+func (_e *MockLoginAttemptsRepository_Expecter) Fail(ctx interface{}, key interface{}, window interface{}) *MockLoginAttemptsRepository_Fail_Call {
+	return &MockLoginAttemptsRepository_Fail_Call{Call: _e.mock.On("Fail", ctx, key, window)}
+}
+
+func (_c *MockLoginAttemptsRepository_Fail_Call) Run(run func(ctx context.Context, key string, window time.Duration)) *MockLoginAttemptsRepository_Fail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptsRepository_Fail_Call) Return(_a0 int, _a1 error) *MockLoginAttemptsRepository_Fail_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLoginAttemptsRepository_Fail_Call) RunAndReturn(run func(context.Context, string, time.Duration) (int, error)) *MockLoginAttemptsRepository_Fail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Lock provides a mock function with given fields: ctx, key, d
+func (_m *MockLoginAttemptsRepository) Lock(ctx context.Context, key string, d time.Duration) error {
+	ret := _m.Called(ctx, key, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) error); ok {
+		r0 = rf(ctx, key, d)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLoginAttemptsRepository_Lock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Lock'
+type MockLoginAttemptsRepository_Lock_Call struct {
+	*mock.Call
+}
+
+// Lock is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - d time.Duration
+func (_e *MockLoginAttemptsRepository_Expecter) Lock(ctx interface{}, key interface{}, d interface{}) *MockLoginAttemptsRepository_Lock_Call {
+	return &MockLoginAttemptsRepository_Lock_Call{Call: _e.mock.On("Lock", ctx, key, d)}
+}
+
+func (_c *MockLoginAttemptsRepository_Lock_Call) Run(run func(ctx context.Context, key string, d time.Duration)) *MockLoginAttemptsRepository_Lock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptsRepository_Lock_Call) Return(_a0 error) *MockLoginAttemptsRepository_Lock_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLoginAttemptsRepository_Lock_Call) RunAndReturn(run func(context.Context, string, time.Duration) error) *MockLoginAttemptsRepository_Lock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LockedFor provides a mock function with given fields: ctx, key
+func (_m *MockLoginAttemptsRepository) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockedFor")
+	}
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (time.Duration, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) time.Duration); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLoginAttemptsRepository_LockedFor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LockedFor'
+type MockLoginAttemptsRepository_LockedFor_Call struct {
+	*mock.Call
+}
+
+// LockedFor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockLoginAttemptsRepository_Expecter) LockedFor(ctx interface{}, key interface{}) *MockLoginAttemptsRepository_LockedFor_Call {
+	return &MockLoginAttemptsRepository_LockedFor_Call{Call: _e.mock.On("LockedFor", ctx, key)}
+}
+
+func (_c *MockLoginAttemptsRepository_LockedFor_Call) Run(run func(ctx context.Context, key string)) *MockLoginAttemptsRepository_LockedFor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptsRepository_LockedFor_Call) Return(_a0 time.Duration, _a1 error) *MockLoginAttemptsRepository_LockedFor_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLoginAttemptsRepository_LockedFor_Call) RunAndReturn(run func(context.Context, string) (time.Duration, error)) *MockLoginAttemptsRepository_LockedFor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reset provides a mock function with given fields: ctx, key
+func (_m *MockLoginAttemptsRepository) Reset(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLoginAttemptsRepository_Reset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reset'
+type MockLoginAttemptsRepository_Reset_Call struct {
+	*mock.Call
+}
+
+// Reset is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockLoginAttemptsRepository_Expecter) Reset(ctx interface{}, key interface{}) *MockLoginAttemptsRepository_Reset_Call {
+	return &MockLoginAttemptsRepository_Reset_Call{Call: _e.mock.On("Reset", ctx, key)}
+}
+
+func (_c *MockLoginAttemptsRepository_Reset_Call) Run(run func(ctx context.Context, key string)) *MockLoginAttemptsRepository_Reset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptsRepository_Reset_Call) Return(_a0 error) *MockLoginAttemptsRepository_Reset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLoginAttemptsRepository_Reset_Call) RunAndReturn(run func(context.Context, string) error) *MockLoginAttemptsRepository_Reset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockLoginAttemptsRepository creates a new instance of MockLoginAttemptsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLoginAttemptsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLoginAttemptsRepository {
+	mock := &MockLoginAttemptsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
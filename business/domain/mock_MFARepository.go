@@ -0,0 +1,490 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockMFARepository is an autogenerated mock type for the MFARepository type
+type MockMFARepository struct {
+	mock.Mock
+}
+
+type MockMFARepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMFARepository) EXPECT() *MockMFARepository_Expecter {
+	return &MockMFARepository_Expecter{mock: &_m.Mock}
+}
+
+// AttemptChallenge provides a mock function with given fields: ctx, token
+func (_m *MockMFARepository) AttemptChallenge(ctx context.Context, token string) (*MFAChallengeAttempt, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttemptChallenge")
+	}
+
+	var r0 *MFAChallengeAttempt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*MFAChallengeAttempt, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *MFAChallengeAttempt); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*MFAChallengeAttempt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMFARepository_AttemptChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AttemptChallenge'
+type MockMFARepository_AttemptChallenge_Call struct {
+	*mock.Call
+}
+
+// AttemptChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *MockMFARepository_Expecter) AttemptChallenge(ctx interface{}, token interface{}) *MockMFARepository_AttemptChallenge_Call {
+	return &MockMFARepository_AttemptChallenge_Call{Call: _e.mock.On("AttemptChallenge", ctx, token)}
+}
+
+func (_c *MockMFARepository_AttemptChallenge_Call) Run(run func(ctx context.Context, token string)) *MockMFARepository_AttemptChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMFARepository_AttemptChallenge_Call) Return(_a0 *MFAChallengeAttempt, _a1 error) *MockMFARepository_AttemptChallenge_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMFARepository_AttemptChallenge_Call) RunAndReturn(run func(context.Context, string) (*MFAChallengeAttempt, error)) *MockMFARepository_AttemptChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConfirmTOTP provides a mock function with given fields: ctx, userID, recoveryCodes
+func (_m *MockMFARepository) ConfirmTOTP(ctx context.Context, userID int64, recoveryCodes []string) error {
+	ret := _m.Called(ctx, userID, recoveryCodes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string) error); ok {
+		r0 = rf(ctx, userID, recoveryCodes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMFARepository_ConfirmTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmTOTP'
+type MockMFARepository_ConfirmTOTP_Call struct {
+	*mock.Call
+}
+
+// ConfirmTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - recoveryCodes []string
+func (_e *MockMFARepository_Expecter) ConfirmTOTP(ctx interface{}, userID interface{}, recoveryCodes interface{}) *MockMFARepository_ConfirmTOTP_Call {
+	return &MockMFARepository_ConfirmTOTP_Call{Call: _e.mock.On("ConfirmTOTP", ctx, userID, recoveryCodes)}
+}
+
+func (_c *MockMFARepository_ConfirmTOTP_Call) Run(run func(ctx context.Context, userID int64, recoveryCodes []string)) *MockMFARepository_ConfirmTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *MockMFARepository_ConfirmTOTP_Call) Return(_a0 error) *MockMFARepository_ConfirmTOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMFARepository_ConfirmTOTP_Call) RunAndReturn(run func(context.Context, int64, []string) error) *MockMFARepository_ConfirmTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateChallenge provides a mock function with given fields: ctx, token, userID, expiry
+func (_m *MockMFARepository) CreateChallenge(ctx context.Context, token string, userID int64, expiry time.Time) error {
+	ret := _m.Called(ctx, token, userID, expiry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateChallenge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, time.Time) error); ok {
+		r0 = rf(ctx, token, userID, expiry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMFARepository_CreateChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateChallenge'
+type MockMFARepository_CreateChallenge_Call struct {
+	*mock.Call
+}
+
+// CreateChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+//   - userID int64
+//   - expiry time.Time
+func (_e *MockMFARepository_Expecter) CreateChallenge(ctx interface{}, token interface{}, userID interface{}, expiry interface{}) *MockMFARepository_CreateChallenge_Call {
+	return &MockMFARepository_CreateChallenge_Call{Call: _e.mock.On("CreateChallenge", ctx, token, userID, expiry)}
+}
+
+func (_c *MockMFARepository_CreateChallenge_Call) Run(run func(ctx context.Context, token string, userID int64, expiry time.Time)) *MockMFARepository_CreateChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockMFARepository_CreateChallenge_Call) Return(_a0 error) *MockMFARepository_CreateChallenge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMFARepository_CreateChallenge_Call) RunAndReturn(run func(context.Context, string, int64, time.Time) error) *MockMFARepository_CreateChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteChallenge provides a mock function with given fields: ctx, token
+func (_m *MockMFARepository) DeleteChallenge(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteChallenge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMFARepository_DeleteChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteChallenge'
+type MockMFARepository_DeleteChallenge_Call struct {
+	*mock.Call
+}
+
+// DeleteChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *MockMFARepository_Expecter) DeleteChallenge(ctx interface{}, token interface{}) *MockMFARepository_DeleteChallenge_Call {
+	return &MockMFARepository_DeleteChallenge_Call{Call: _e.mock.On("DeleteChallenge", ctx, token)}
+}
+
+func (_c *MockMFARepository_DeleteChallenge_Call) Run(run func(ctx context.Context, token string)) *MockMFARepository_DeleteChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMFARepository_DeleteChallenge_Call) Return(_a0 error) *MockMFARepository_DeleteChallenge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMFARepository_DeleteChallenge_Call) RunAndReturn(run func(context.Context, string) error) *MockMFARepository_DeleteChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteTOTP provides a mock function with given fields: ctx, userID
+func (_m *MockMFARepository) DeleteTOTP(ctx context.Context, userID int64) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMFARepository_DeleteTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteTOTP'
+type MockMFARepository_DeleteTOTP_Call struct {
+	*mock.Call
+}
+
+// DeleteTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *MockMFARepository_Expecter) DeleteTOTP(ctx interface{}, userID interface{}) *MockMFARepository_DeleteTOTP_Call {
+	return &MockMFARepository_DeleteTOTP_Call{Call: _e.mock.On("DeleteTOTP", ctx, userID)}
+}
+
+func (_c *MockMFARepository_DeleteTOTP_Call) Run(run func(ctx context.Context, userID int64)) *MockMFARepository_DeleteTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockMFARepository_DeleteTOTP_Call) Return(_a0 error) *MockMFARepository_DeleteTOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMFARepository_DeleteTOTP_Call) RunAndReturn(run func(context.Context, int64) error) *MockMFARepository_DeleteTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTOTP provides a mock function with given fields: ctx, userID
+func (_m *MockMFARepository) GetTOTP(ctx context.Context, userID int64) (*TOTPSecret, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTOTP")
+	}
+
+	var r0 *TOTPSecret
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*TOTPSecret, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *TOTPSecret); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*TOTPSecret)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMFARepository_GetTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTOTP'
+type MockMFARepository_GetTOTP_Call struct {
+	*mock.Call
+}
+
+// GetTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *MockMFARepository_Expecter) GetTOTP(ctx interface{}, userID interface{}) *MockMFARepository_GetTOTP_Call {
+	return &MockMFARepository_GetTOTP_Call{Call: _e.mock.On("GetTOTP", ctx, userID)}
+}
+
+func (_c *MockMFARepository_GetTOTP_Call) Run(run func(ctx context.Context, userID int64)) *MockMFARepository_GetTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockMFARepository_GetTOTP_Call) Return(_a0 *TOTPSecret, _a1 error) *MockMFARepository_GetTOTP_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMFARepository_GetTOTP_Call) RunAndReturn(run func(context.Context, int64) (*TOTPSecret, error)) *MockMFARepository_GetTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveTOTP provides a mock function with given fields: ctx, userID, secret
+func (_m *MockMFARepository) SaveTOTP(ctx context.Context, userID int64, secret string) error {
+	ret := _m.Called(ctx, userID, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMFARepository_SaveTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTOTP'
+type MockMFARepository_SaveTOTP_Call struct {
+	*mock.Call
+}
+
+// SaveTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - secret string
+func (_e *MockMFARepository_Expecter) SaveTOTP(ctx interface{}, userID interface{}, secret interface{}) *MockMFARepository_SaveTOTP_Call {
+	return &MockMFARepository_SaveTOTP_Call{Call: _e.mock.On("SaveTOTP", ctx, userID, secret)}
+}
+
+func (_c *MockMFARepository_SaveTOTP_Call) Run(run func(ctx context.Context, userID int64, secret string)) *MockMFARepository_SaveTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockMFARepository_SaveTOTP_Call) Return(_a0 error) *MockMFARepository_SaveTOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMFARepository_SaveTOTP_Call) RunAndReturn(run func(context.Context, int64, string) error) *MockMFARepository_SaveTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, userID, code
+func (_m *MockMFARepository) UseRecoveryCode(ctx context.Context, userID int64, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMFARepository_UseRecoveryCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseRecoveryCode'
+type MockMFARepository_UseRecoveryCode_Call struct {
+	*mock.Call
+}
+
+// UseRecoveryCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - code string
+func (_e *MockMFARepository_Expecter) UseRecoveryCode(ctx interface{}, userID interface{}, code interface{}) *MockMFARepository_UseRecoveryCode_Call {
+	return &MockMFARepository_UseRecoveryCode_Call{Call: _e.mock.On("UseRecoveryCode", ctx, userID, code)}
+}
+
+func (_c *MockMFARepository_UseRecoveryCode_Call) Run(run func(ctx context.Context, userID int64, code string)) *MockMFARepository_UseRecoveryCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockMFARepository_UseRecoveryCode_Call) Return(_a0 error) *MockMFARepository_UseRecoveryCode_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMFARepository_UseRecoveryCode_Call) RunAndReturn(run func(context.Context, int64, string) error) *MockMFARepository_UseRecoveryCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseTOTPStep provides a mock function with given fields: ctx, userID, step
+func (_m *MockMFARepository) UseTOTPStep(ctx context.Context, userID int64, step int64) error {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPStep")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMFARepository_UseTOTPStep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseTOTPStep'
+type MockMFARepository_UseTOTPStep_Call struct {
+	*mock.Call
+}
+
+// UseTOTPStep is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - step int64
+func (_e *MockMFARepository_Expecter) UseTOTPStep(ctx interface{}, userID interface{}, step interface{}) *MockMFARepository_UseTOTPStep_Call {
+	return &MockMFARepository_UseTOTPStep_Call{Call: _e.mock.On("UseTOTPStep", ctx, userID, step)}
+}
+
+func (_c *MockMFARepository_UseTOTPStep_Call) Run(run func(ctx context.Context, userID int64, step int64)) *MockMFARepository_UseTOTPStep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockMFARepository_UseTOTPStep_Call) Return(_a0 error) *MockMFARepository_UseTOTPStep_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMFARepository_UseTOTPStep_Call) RunAndReturn(run func(context.Context, int64, int64) error) *MockMFARepository_UseTOTPStep_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockMFARepository creates a new instance of MockMFARepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMFARepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMFARepository {
+	mock := &MockMFARepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
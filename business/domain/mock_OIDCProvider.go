@@ -0,0 +1,199 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockOIDCProvider is an autogenerated mock type for the OIDCProvider type
+type MockOIDCProvider struct {
+	mock.Mock
+}
+
+type MockOIDCProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockOIDCProvider) EXPECT() *MockOIDCProvider_Expecter {
+	return &MockOIDCProvider_Expecter{mock: &_m.Mock}
+}
+
+// AuthCodeURL provides a mock function with given fields: ctx, state, nonce, codeChallenge
+func (_m *MockOIDCProvider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	ret := _m.Called(ctx, state, nonce, codeChallenge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthCodeURL")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return rf(ctx, state, nonce, codeChallenge)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, state, nonce, codeChallenge)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, state, nonce, codeChallenge)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOIDCProvider_AuthCodeURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuthCodeURL'
+type MockOIDCProvider_AuthCodeURL_Call struct {
+	*mock.Call
+}
+
+// AuthCodeURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - state string
+//   - nonce string
+//   - codeChallenge string
+func (_e *MockOIDCProvider_Expecter) AuthCodeURL(ctx interface{}, state interface{}, nonce interface{}, codeChallenge interface{}) *MockOIDCProvider_AuthCodeURL_Call {
+	return &MockOIDCProvider_AuthCodeURL_Call{Call: _e.mock.On("AuthCodeURL", ctx, state, nonce, codeChallenge)}
+}
+
+func (_c *MockOIDCProvider_AuthCodeURL_Call) Run(run func(ctx context.Context, state string, nonce string, codeChallenge string)) *MockOIDCProvider_AuthCodeURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockOIDCProvider_AuthCodeURL_Call) Return(_a0 string, _a1 error) *MockOIDCProvider_AuthCodeURL_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOIDCProvider_AuthCodeURL_Call) RunAndReturn(run func(context.Context, string, string, string) (string, error)) *MockOIDCProvider_AuthCodeURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Exchange provides a mock function with given fields: ctx, code, codeVerifier, nonce
+func (_m *MockOIDCProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (OIDCIdentity, error) {
+	ret := _m.Called(ctx, code, codeVerifier, nonce)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exchange")
+	}
+
+	var r0 OIDCIdentity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (OIDCIdentity, error)); ok {
+		return rf(ctx, code, codeVerifier, nonce)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) OIDCIdentity); ok {
+		r0 = rf(ctx, code, codeVerifier, nonce)
+	} else {
+		r0 = ret.Get(0).(OIDCIdentity)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, code, codeVerifier, nonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOIDCProvider_Exchange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Exchange'
+type MockOIDCProvider_Exchange_Call struct {
+	*mock.Call
+}
+
+// Exchange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - code string
+//   - codeVerifier string
+//   - nonce string
+func (_e *MockOIDCProvider_Expecter) Exchange(ctx interface{}, code interface{}, codeVerifier interface{}, nonce interface{}) *MockOIDCProvider_Exchange_Call {
+	return &MockOIDCProvider_Exchange_Call{Call: _e.mock.On("Exchange", ctx, code, codeVerifier, nonce)}
+}
+
+func (_c *MockOIDCProvider_Exchange_Call) Run(run func(ctx context.Context, code string, codeVerifier string, nonce string)) *MockOIDCProvider_Exchange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockOIDCProvider_Exchange_Call) Return(_a0 OIDCIdentity, _a1 error) *MockOIDCProvider_Exchange_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOIDCProvider_Exchange_Call) RunAndReturn(run func(context.Context, string, string, string) (OIDCIdentity, error)) *MockOIDCProvider_Exchange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Name provides a mock function with no fields
+func (_m *MockOIDCProvider) Name() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// MockOIDCProvider_Name_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Name'
+type MockOIDCProvider_Name_Call struct {
+	*mock.Call
+}
+
+// Name is a helper method to define mock.On call
+func (_e *MockOIDCProvider_Expecter) Name() *MockOIDCProvider_Name_Call {
+	return &MockOIDCProvider_Name_Call{Call: _e.mock.On("Name")}
+}
+
+func (_c *MockOIDCProvider_Name_Call) Run(run func()) *MockOIDCProvider_Name_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockOIDCProvider_Name_Call) Return(_a0 string) *MockOIDCProvider_Name_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOIDCProvider_Name_Call) RunAndReturn(run func() string) *MockOIDCProvider_Name_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockOIDCProvider creates a new instance of MockOIDCProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOIDCProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOIDCProvider {
+	mock := &MockOIDCProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,298 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockOIDCRepository is an autogenerated mock type for the OIDCRepository type
+type MockOIDCRepository struct {
+	mock.Mock
+}
+
+type MockOIDCRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockOIDCRepository) EXPECT() *MockOIDCRepository_Expecter {
+	return &MockOIDCRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateUser provides a mock function with given fields: ctx, user, identity
+func (_m *MockOIDCRepository) CreateUser(ctx context.Context, user *User, identity *UserIdentity) error {
+	ret := _m.Called(ctx, user, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *User, *UserIdentity) error); ok {
+		r0 = rf(ctx, user, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOIDCRepository_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
+type MockOIDCRepository_CreateUser_Call struct {
+	*mock.Call
+}
+
+// CreateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *User
+//   - identity *UserIdentity
+func (_e *MockOIDCRepository_Expecter) CreateUser(ctx interface{}, user interface{}, identity interface{}) *MockOIDCRepository_CreateUser_Call {
+	return &MockOIDCRepository_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, user, identity)}
+}
+
+func (_c *MockOIDCRepository_CreateUser_Call) Run(run func(ctx context.Context, user *User, identity *UserIdentity)) *MockOIDCRepository_CreateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*User), args[2].(*UserIdentity))
+	})
+	return _c
+}
+
+func (_c *MockOIDCRepository_CreateUser_Call) Return(_a0 error) *MockOIDCRepository_CreateUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOIDCRepository_CreateUser_Call) RunAndReturn(run func(context.Context, *User, *UserIdentity) error) *MockOIDCRepository_CreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetIdentity provides a mock function with given fields: ctx, provider, subject
+func (_m *MockOIDCRepository) GetIdentity(ctx context.Context, provider string, subject string) (*UserIdentity, error) {
+	ret := _m.Called(ctx, provider, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIdentity")
+	}
+
+	var r0 *UserIdentity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*UserIdentity, error)); ok {
+		return rf(ctx, provider, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *UserIdentity); ok {
+		r0 = rf(ctx, provider, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*UserIdentity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOIDCRepository_GetIdentity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIdentity'
+type MockOIDCRepository_GetIdentity_Call struct {
+	*mock.Call
+}
+
+// GetIdentity is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - subject string
+func (_e *MockOIDCRepository_Expecter) GetIdentity(ctx interface{}, provider interface{}, subject interface{}) *MockOIDCRepository_GetIdentity_Call {
+	return &MockOIDCRepository_GetIdentity_Call{Call: _e.mock.On("GetIdentity", ctx, provider, subject)}
+}
+
+func (_c *MockOIDCRepository_GetIdentity_Call) Run(run func(ctx context.Context, provider string, subject string)) *MockOIDCRepository_GetIdentity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockOIDCRepository_GetIdentity_Call) Return(_a0 *UserIdentity, _a1 error) *MockOIDCRepository_GetIdentity_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOIDCRepository_GetIdentity_Call) RunAndReturn(run func(context.Context, string, string) (*UserIdentity, error)) *MockOIDCRepository_GetIdentity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LinkIdentity provides a mock function with given fields: ctx, identity
+func (_m *MockOIDCRepository) LinkIdentity(ctx context.Context, identity UserIdentity) error {
+	ret := _m.Called(ctx, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LinkIdentity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, UserIdentity) error); ok {
+		r0 = rf(ctx, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOIDCRepository_LinkIdentity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LinkIdentity'
+type MockOIDCRepository_LinkIdentity_Call struct {
+	*mock.Call
+}
+
+// LinkIdentity is a helper method to define mock.On call
+//   - ctx context.Context
+//   - identity UserIdentity
+func (_e *MockOIDCRepository_Expecter) LinkIdentity(ctx interface{}, identity interface{}) *MockOIDCRepository_LinkIdentity_Call {
+	return &MockOIDCRepository_LinkIdentity_Call{Call: _e.mock.On("LinkIdentity", ctx, identity)}
+}
+
+func (_c *MockOIDCRepository_LinkIdentity_Call) Run(run func(ctx context.Context, identity UserIdentity)) *MockOIDCRepository_LinkIdentity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(UserIdentity))
+	})
+	return _c
+}
+
+func (_c *MockOIDCRepository_LinkIdentity_Call) Return(_a0 error) *MockOIDCRepository_LinkIdentity_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOIDCRepository_LinkIdentity_Call) RunAndReturn(run func(context.Context, UserIdentity) error) *MockOIDCRepository_LinkIdentity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveState provides a mock function with given fields: ctx, hash, state
+func (_m *MockOIDCRepository) SaveState(ctx context.Context, hash string, state OIDCLoginState) error {
+	ret := _m.Called(ctx, hash, state)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveState")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, OIDCLoginState) error); ok {
+		r0 = rf(ctx, hash, state)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOIDCRepository_SaveState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveState'
+type MockOIDCRepository_SaveState_Call struct {
+	*mock.Call
+}
+
+// SaveState is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hash string
+//   - state OIDCLoginState
+func (_e *MockOIDCRepository_Expecter) SaveState(ctx interface{}, hash interface{}, state interface{}) *MockOIDCRepository_SaveState_Call {
+	return &MockOIDCRepository_SaveState_Call{Call: _e.mock.On("SaveState", ctx, hash, state)}
+}
+
+func (_c *MockOIDCRepository_SaveState_Call) Run(run func(ctx context.Context, hash string, state OIDCLoginState)) *MockOIDCRepository_SaveState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(OIDCLoginState))
+	})
+	return _c
+}
+
+func (_c *MockOIDCRepository_SaveState_Call) Return(_a0 error) *MockOIDCRepository_SaveState_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOIDCRepository_SaveState_Call) RunAndReturn(run func(context.Context, string, OIDCLoginState) error) *MockOIDCRepository_SaveState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TakeState provides a mock function with given fields: ctx, hash
+func (_m *MockOIDCRepository) TakeState(ctx context.Context, hash string) (*OIDCLoginState, error) {
+	ret := _m.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TakeState")
+	}
+
+	var r0 *OIDCLoginState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*OIDCLoginState, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *OIDCLoginState); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*OIDCLoginState)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOIDCRepository_TakeState_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TakeState'
+type MockOIDCRepository_TakeState_Call struct {
+	*mock.Call
+}
+
+// TakeState is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hash string
+func (_e *MockOIDCRepository_Expecter) TakeState(ctx interface{}, hash interface{}) *MockOIDCRepository_TakeState_Call {
+	return &MockOIDCRepository_TakeState_Call{Call: _e.mock.On("TakeState", ctx, hash)}
+}
+
+func (_c *MockOIDCRepository_TakeState_Call) Run(run func(ctx context.Context, hash string)) *MockOIDCRepository_TakeState_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockOIDCRepository_TakeState_Call) Return(_a0 *OIDCLoginState, _a1 error) *MockOIDCRepository_TakeState_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOIDCRepository_TakeState_Call) RunAndReturn(run func(context.Context, string) (*OIDCLoginState, error)) *MockOIDCRepository_TakeState_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockOIDCRepository creates a new instance of MockOIDCRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOIDCRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOIDCRepository {
+	mock := &MockOIDCRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
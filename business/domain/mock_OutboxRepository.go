@@ -0,0 +1,393 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockOutboxRepository is an autogenerated mock type for the OutboxRepository type
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+type MockOutboxRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockOutboxRepository) EXPECT() *MockOutboxRepository_Expecter {
+	return &MockOutboxRepository_Expecter{mock: &_m.Mock}
+}
+
+// Claim provides a mock function with given fields: ctx, limit, now, leasedUntil
+func (_m *MockOutboxRepository) Claim(ctx context.Context, limit int, now time.Time, leasedUntil time.Time) ([]OutboxMessage, error) {
+	ret := _m.Called(ctx, limit, now, leasedUntil)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Claim")
+	}
+
+	var r0 []OutboxMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time) ([]OutboxMessage, error)); ok {
+		return rf(ctx, limit, now, leasedUntil)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time, time.Time) []OutboxMessage); ok {
+		r0 = rf(ctx, limit, now, leasedUntil)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]OutboxMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, limit, now, leasedUntil)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOutboxRepository_Claim_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Claim'
+type MockOutboxRepository_Claim_Call struct {
+	*mock.Call
+}
+
+// Claim is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - now time.Time
+//   - leasedUntil time.Time
+func (_e *MockOutboxRepository_Expecter) Claim(ctx interface{}, limit interface{}, now interface{}, leasedUntil interface{}) *MockOutboxRepository_Claim_Call {
+	return &MockOutboxRepository_Claim_Call{Call: _e.mock.On("Claim", ctx, limit, now, leasedUntil)}
+}
+
+func (_c *MockOutboxRepository_Claim_Call) Run(run func(ctx context.Context, limit int, now time.Time, leasedUntil time.Time)) *MockOutboxRepository_Claim_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Time), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_Claim_Call) Return(_a0 []OutboxMessage, _a1 error) *MockOutboxRepository_Claim_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOutboxRepository_Claim_Call) RunAndReturn(run func(context.Context, int, time.Time, time.Time) ([]OutboxMessage, error)) *MockOutboxRepository_Claim_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountPending provides a mock function with given fields: ctx
+func (_m *MockOutboxRepository) CountPending(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountPending")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockOutboxRepository_CountPending_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountPending'
+type MockOutboxRepository_CountPending_Call struct {
+	*mock.Call
+}
+
+// CountPending is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockOutboxRepository_Expecter) CountPending(ctx interface{}) *MockOutboxRepository_CountPending_Call {
+	return &MockOutboxRepository_CountPending_Call{Call: _e.mock.On("CountPending", ctx)}
+}
+
+func (_c *MockOutboxRepository_CountPending_Call) Run(run func(ctx context.Context)) *MockOutboxRepository_CountPending_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_CountPending_Call) Return(_a0 int64, _a1 error) *MockOutboxRepository_CountPending_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockOutboxRepository_CountPending_Call) RunAndReturn(run func(context.Context) (int64, error)) *MockOutboxRepository_CountPending_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Enqueue provides a mock function with given fields: ctx, msg
+func (_m *MockOutboxRepository) Enqueue(ctx context.Context, msg OutboxMessage) error {
+	ret := _m.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enqueue")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, OutboxMessage) error); ok {
+		r0 = rf(ctx, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOutboxRepository_Enqueue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Enqueue'
+type MockOutboxRepository_Enqueue_Call struct {
+	*mock.Call
+}
+
+// Enqueue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msg OutboxMessage
+func (_e *MockOutboxRepository_Expecter) Enqueue(ctx interface{}, msg interface{}) *MockOutboxRepository_Enqueue_Call {
+	return &MockOutboxRepository_Enqueue_Call{Call: _e.mock.On("Enqueue", ctx, msg)}
+}
+
+func (_c *MockOutboxRepository_Enqueue_Call) Run(run func(ctx context.Context, msg OutboxMessage)) *MockOutboxRepository_Enqueue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(OutboxMessage))
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_Enqueue_Call) Return(_a0 error) *MockOutboxRepository_Enqueue_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOutboxRepository_Enqueue_Call) RunAndReturn(run func(context.Context, OutboxMessage) error) *MockOutboxRepository_Enqueue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkDead provides a mock function with given fields: ctx, id, lastErr
+func (_m *MockOutboxRepository) MarkDead(ctx context.Context, id int64, lastErr string) error {
+	ret := _m.Called(ctx, id, lastErr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkDead")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, lastErr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOutboxRepository_MarkDead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkDead'
+type MockOutboxRepository_MarkDead_Call struct {
+	*mock.Call
+}
+
+// MarkDead is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - lastErr string
+func (_e *MockOutboxRepository_Expecter) MarkDead(ctx interface{}, id interface{}, lastErr interface{}) *MockOutboxRepository_MarkDead_Call {
+	return &MockOutboxRepository_MarkDead_Call{Call: _e.mock.On("MarkDead", ctx, id, lastErr)}
+}
+
+func (_c *MockOutboxRepository_MarkDead_Call) Run(run func(ctx context.Context, id int64, lastErr string)) *MockOutboxRepository_MarkDead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_MarkDead_Call) Return(_a0 error) *MockOutboxRepository_MarkDead_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOutboxRepository_MarkDead_Call) RunAndReturn(run func(context.Context, int64, string) error) *MockOutboxRepository_MarkDead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkSent provides a mock function with given fields: ctx, id
+func (_m *MockOutboxRepository) MarkSent(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkSent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOutboxRepository_MarkSent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkSent'
+type MockOutboxRepository_MarkSent_Call struct {
+	*mock.Call
+}
+
+// MarkSent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockOutboxRepository_Expecter) MarkSent(ctx interface{}, id interface{}) *MockOutboxRepository_MarkSent_Call {
+	return &MockOutboxRepository_MarkSent_Call{Call: _e.mock.On("MarkSent", ctx, id)}
+}
+
+func (_c *MockOutboxRepository_MarkSent_Call) Run(run func(ctx context.Context, id int64)) *MockOutboxRepository_MarkSent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_MarkSent_Call) Return(_a0 error) *MockOutboxRepository_MarkSent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOutboxRepository_MarkSent_Call) RunAndReturn(run func(context.Context, int64) error) *MockOutboxRepository_MarkSent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Release provides a mock function with given fields: ctx, id, at
+func (_m *MockOutboxRepository) Release(ctx context.Context, id int64, at time.Time) error {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) error); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOutboxRepository_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type MockOutboxRepository_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - at time.Time
+func (_e *MockOutboxRepository_Expecter) Release(ctx interface{}, id interface{}, at interface{}) *MockOutboxRepository_Release_Call {
+	return &MockOutboxRepository_Release_Call{Call: _e.mock.On("Release", ctx, id, at)}
+}
+
+func (_c *MockOutboxRepository_Release_Call) Run(run func(ctx context.Context, id int64, at time.Time)) *MockOutboxRepository_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_Release_Call) Return(_a0 error) *MockOutboxRepository_Release_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOutboxRepository_Release_Call) RunAndReturn(run func(context.Context, int64, time.Time) error) *MockOutboxRepository_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Retry provides a mock function with given fields: ctx, id, at, lastErr
+func (_m *MockOutboxRepository) Retry(ctx context.Context, id int64, at time.Time, lastErr string) error {
+	ret := _m.Called(ctx, id, at, lastErr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Retry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, string) error); ok {
+		r0 = rf(ctx, id, at, lastErr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockOutboxRepository_Retry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Retry'
+type MockOutboxRepository_Retry_Call struct {
+	*mock.Call
+}
+
+// Retry is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - at time.Time
+//   - lastErr string
+func (_e *MockOutboxRepository_Expecter) Retry(ctx interface{}, id interface{}, at interface{}, lastErr interface{}) *MockOutboxRepository_Retry_Call {
+	return &MockOutboxRepository_Retry_Call{Call: _e.mock.On("Retry", ctx, id, at, lastErr)}
+}
+
+func (_c *MockOutboxRepository_Retry_Call) Run(run func(ctx context.Context, id int64, at time.Time, lastErr string)) *MockOutboxRepository_Retry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockOutboxRepository_Retry_Call) Return(_a0 error) *MockOutboxRepository_Retry_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockOutboxRepository_Retry_Call) RunAndReturn(run func(context.Context, int64, time.Time, string) error) *MockOutboxRepository_Retry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockOutboxRepository creates a new instance of MockOutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOutboxRepository {
+	mock := &MockOutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,299 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockPersonalTokensRepository is an autogenerated mock type for the PersonalTokensRepository type
+type MockPersonalTokensRepository struct {
+	mock.Mock
+}
+
+type MockPersonalTokensRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPersonalTokensRepository) EXPECT() *MockPersonalTokensRepository_Expecter {
+	return &MockPersonalTokensRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, token, hash
+func (_m *MockPersonalTokensRepository) Create(ctx context.Context, token *PersonalToken, hash string) error {
+	ret := _m.Called(ctx, token, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *PersonalToken, string) error); ok {
+		r0 = rf(ctx, token, hash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPersonalTokensRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockPersonalTokensRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *PersonalToken
+//   - hash string
+func (_e *MockPersonalTokensRepository_Expecter) Create(ctx interface{}, token interface{}, hash interface{}) *MockPersonalTokensRepository_Create_Call {
+	return &MockPersonalTokensRepository_Create_Call{Call: _e.mock.On("Create", ctx, token, hash)}
+}
+
+func (_c *MockPersonalTokensRepository_Create_Call) Run(run func(ctx context.Context, token *PersonalToken, hash string)) *MockPersonalTokensRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*PersonalToken), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockPersonalTokensRepository_Create_Call) Return(_a0 error) *MockPersonalTokensRepository_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPersonalTokensRepository_Create_Call) RunAndReturn(run func(context.Context, *PersonalToken, string) error) *MockPersonalTokensRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, userID, id
+func (_m *MockPersonalTokensRepository) Delete(ctx context.Context, userID int64, id int64) error {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPersonalTokensRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockPersonalTokensRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - id int64
+func (_e *MockPersonalTokensRepository_Expecter) Delete(ctx interface{}, userID interface{}, id interface{}) *MockPersonalTokensRepository_Delete_Call {
+	return &MockPersonalTokensRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, userID, id)}
+}
+
+func (_c *MockPersonalTokensRepository_Delete_Call) Run(run func(ctx context.Context, userID int64, id int64)) *MockPersonalTokensRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockPersonalTokensRepository_Delete_Call) Return(_a0 error) *MockPersonalTokensRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPersonalTokensRepository_Delete_Call) RunAndReturn(run func(context.Context, int64, int64) error) *MockPersonalTokensRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByToken provides a mock function with given fields: ctx, hash
+func (_m *MockPersonalTokensRepository) GetByToken(ctx context.Context, hash string) (*PersonalToken, error) {
+	ret := _m.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByToken")
+	}
+
+	var r0 *PersonalToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*PersonalToken, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *PersonalToken); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*PersonalToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPersonalTokensRepository_GetByToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByToken'
+type MockPersonalTokensRepository_GetByToken_Call struct {
+	*mock.Call
+}
+
+// GetByToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hash string
+func (_e *MockPersonalTokensRepository_Expecter) GetByToken(ctx interface{}, hash interface{}) *MockPersonalTokensRepository_GetByToken_Call {
+	return &MockPersonalTokensRepository_GetByToken_Call{Call: _e.mock.On("GetByToken", ctx, hash)}
+}
+
+func (_c *MockPersonalTokensRepository_GetByToken_Call) Run(run func(ctx context.Context, hash string)) *MockPersonalTokensRepository_GetByToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPersonalTokensRepository_GetByToken_Call) Return(_a0 *PersonalToken, _a1 error) *MockPersonalTokensRepository_GetByToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPersonalTokensRepository_GetByToken_Call) RunAndReturn(run func(context.Context, string) (*PersonalToken, error)) *MockPersonalTokensRepository_GetByToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByUser provides a mock function with given fields: ctx, userID
+func (_m *MockPersonalTokensRepository) ListByUser(ctx context.Context, userID int64) ([]PersonalToken, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUser")
+	}
+
+	var r0 []PersonalToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]PersonalToken, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []PersonalToken); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]PersonalToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPersonalTokensRepository_ListByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByUser'
+type MockPersonalTokensRepository_ListByUser_Call struct {
+	*mock.Call
+}
+
+// ListByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *MockPersonalTokensRepository_Expecter) ListByUser(ctx interface{}, userID interface{}) *MockPersonalTokensRepository_ListByUser_Call {
+	return &MockPersonalTokensRepository_ListByUser_Call{Call: _e.mock.On("ListByUser", ctx, userID)}
+}
+
+func (_c *MockPersonalTokensRepository_ListByUser_Call) Run(run func(ctx context.Context, userID int64)) *MockPersonalTokensRepository_ListByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockPersonalTokensRepository_ListByUser_Call) Return(_a0 []PersonalToken, _a1 error) *MockPersonalTokensRepository_ListByUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPersonalTokensRepository_ListByUser_Call) RunAndReturn(run func(context.Context, int64) ([]PersonalToken, error)) *MockPersonalTokensRepository_ListByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Touch provides a mock function with given fields: ctx, id, at
+func (_m *MockPersonalTokensRepository) Touch(ctx context.Context, id int64, at time.Time) error {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Touch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) error); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPersonalTokensRepository_Touch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Touch'
+type MockPersonalTokensRepository_Touch_Call struct {
+	*mock.Call
+}
+
+// Touch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - at time.Time
+func (_e *MockPersonalTokensRepository_Expecter) Touch(ctx interface{}, id interface{}, at interface{}) *MockPersonalTokensRepository_Touch_Call {
+	return &MockPersonalTokensRepository_Touch_Call{Call: _e.mock.On("Touch", ctx, id, at)}
+}
+
+func (_c *MockPersonalTokensRepository_Touch_Call) Run(run func(ctx context.Context, id int64, at time.Time)) *MockPersonalTokensRepository_Touch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockPersonalTokensRepository_Touch_Call) Return(_a0 error) *MockPersonalTokensRepository_Touch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPersonalTokensRepository_Touch_Call) RunAndReturn(run func(context.Context, int64, time.Time) error) *MockPersonalTokensRepository_Touch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockPersonalTokensRepository creates a new instance of MockPersonalTokensRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPersonalTokensRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPersonalTokensRepository {
+	mock := &MockPersonalTokensRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,232 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockReactionsRepository is an autogenerated mock type for the ReactionsRepository type
+type MockReactionsRepository struct {
+	mock.Mock
+}
+
+type MockReactionsRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockReactionsRepository) EXPECT() *MockReactionsRepository_Expecter {
+	return &MockReactionsRepository_Expecter{mock: &_m.Mock}
+}
+
+// AddToComment provides a mock function with given fields: ctx, commentID, userID, reaction
+func (_m *MockReactionsRepository) AddToComment(ctx context.Context, commentID int64, userID int64, reaction ReactionType) error {
+	ret := _m.Called(ctx, commentID, userID, reaction)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddToComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, ReactionType) error); ok {
+		r0 = rf(ctx, commentID, userID, reaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockReactionsRepository_AddToComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddToComment'
+type MockReactionsRepository_AddToComment_Call struct {
+	*mock.Call
+}
+
+// AddToComment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - commentID int64
+//   - userID int64
+//   - reaction ReactionType
+func (_e *MockReactionsRepository_Expecter) AddToComment(ctx interface{}, commentID interface{}, userID interface{}, reaction interface{}) *MockReactionsRepository_AddToComment_Call {
+	return &MockReactionsRepository_AddToComment_Call{Call: _e.mock.On("AddToComment", ctx, commentID, userID, reaction)}
+}
+
+func (_c *MockReactionsRepository_AddToComment_Call) Run(run func(ctx context.Context, commentID int64, userID int64, reaction ReactionType)) *MockReactionsRepository_AddToComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(ReactionType))
+	})
+	return _c
+}
+
+func (_c *MockReactionsRepository_AddToComment_Call) Return(_a0 error) *MockReactionsRepository_AddToComment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockReactionsRepository_AddToComment_Call) RunAndReturn(run func(context.Context, int64, int64, ReactionType) error) *MockReactionsRepository_AddToComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddToPost provides a mock function with given fields: ctx, postID, userID, reaction
+func (_m *MockReactionsRepository) AddToPost(ctx context.Context, postID int64, userID int64, reaction ReactionType) error {
+	ret := _m.Called(ctx, postID, userID, reaction)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddToPost")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, ReactionType) error); ok {
+		r0 = rf(ctx, postID, userID, reaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockReactionsRepository_AddToPost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddToPost'
+type MockReactionsRepository_AddToPost_Call struct {
+	*mock.Call
+}
+
+// AddToPost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - postID int64
+//   - userID int64
+//   - reaction ReactionType
+func (_e *MockReactionsRepository_Expecter) AddToPost(ctx interface{}, postID interface{}, userID interface{}, reaction interface{}) *MockReactionsRepository_AddToPost_Call {
+	return &MockReactionsRepository_AddToPost_Call{Call: _e.mock.On("AddToPost", ctx, postID, userID, reaction)}
+}
+
+func (_c *MockReactionsRepository_AddToPost_Call) Run(run func(ctx context.Context, postID int64, userID int64, reaction ReactionType)) *MockReactionsRepository_AddToPost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(ReactionType))
+	})
+	return _c
+}
+
+func (_c *MockReactionsRepository_AddToPost_Call) Return(_a0 error) *MockReactionsRepository_AddToPost_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockReactionsRepository_AddToPost_Call) RunAndReturn(run func(context.Context, int64, int64, ReactionType) error) *MockReactionsRepository_AddToPost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveFromComment provides a mock function with given fields: ctx, commentID, userID, reaction
+func (_m *MockReactionsRepository) RemoveFromComment(ctx context.Context, commentID int64, userID int64, reaction ReactionType) error {
+	ret := _m.Called(ctx, commentID, userID, reaction)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveFromComment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, ReactionType) error); ok {
+		r0 = rf(ctx, commentID, userID, reaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockReactionsRepository_RemoveFromComment_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveFromComment'
+type MockReactionsRepository_RemoveFromComment_Call struct {
+	*mock.Call
+}
+
+// RemoveFromComment is a helper method to define mock.On call
+//   - ctx context.Context
+//   - commentID int64
+//   - userID int64
+//   - reaction ReactionType
+func (_e *MockReactionsRepository_Expecter) RemoveFromComment(ctx interface{}, commentID interface{}, userID interface{}, reaction interface{}) *MockReactionsRepository_RemoveFromComment_Call {
+	return &MockReactionsRepository_RemoveFromComment_Call{Call: _e.mock.On("RemoveFromComment", ctx, commentID, userID, reaction)}
+}
+
+func (_c *MockReactionsRepository_RemoveFromComment_Call) Run(run func(ctx context.Context, commentID int64, userID int64, reaction ReactionType)) *MockReactionsRepository_RemoveFromComment_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(ReactionType))
+	})
+	return _c
+}
+
+func (_c *MockReactionsRepository_RemoveFromComment_Call) Return(_a0 error) *MockReactionsRepository_RemoveFromComment_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockReactionsRepository_RemoveFromComment_Call) RunAndReturn(run func(context.Context, int64, int64, ReactionType) error) *MockReactionsRepository_RemoveFromComment_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveFromPost provides a mock function with given fields: ctx, postID, userID, reaction
+func (_m *MockReactionsRepository) RemoveFromPost(ctx context.Context, postID int64, userID int64, reaction ReactionType) error {
+	ret := _m.Called(ctx, postID, userID, reaction)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveFromPost")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, ReactionType) error); ok {
+		r0 = rf(ctx, postID, userID, reaction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockReactionsRepository_RemoveFromPost_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveFromPost'
+type MockReactionsRepository_RemoveFromPost_Call struct {
+	*mock.Call
+}
+
+// RemoveFromPost is a helper method to define mock.On call
+//   - ctx context.Context
+//   - postID int64
+//   - userID int64
+//   - reaction ReactionType
+func (_e *MockReactionsRepository_Expecter) RemoveFromPost(ctx interface{}, postID interface{}, userID interface{}, reaction interface{}) *MockReactionsRepository_RemoveFromPost_Call {
+	return &MockReactionsRepository_RemoveFromPost_Call{Call: _e.mock.On("RemoveFromPost", ctx, postID, userID, reaction)}
+}
+
+func (_c *MockReactionsRepository_RemoveFromPost_Call) Run(run func(ctx context.Context, postID int64, userID int64, reaction ReactionType)) *MockReactionsRepository_RemoveFromPost_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(ReactionType))
+	})
+	return _c
+}
+
+func (_c *MockReactionsRepository_RemoveFromPost_Call) Return(_a0 error) *MockReactionsRepository_RemoveFromPost_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockReactionsRepository_RemoveFromPost_Call) RunAndReturn(run func(context.Context, int64, int64, ReactionType) error) *MockReactionsRepository_RemoveFromPost_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockReactionsRepository creates a new instance of MockReactionsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockReactionsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockReactionsRepository {
+	mock := &MockReactionsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
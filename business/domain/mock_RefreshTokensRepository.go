@@ -0,0 +1,244 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockRefreshTokensRepository is an autogenerated mock type for the RefreshTokensRepository type
+type MockRefreshTokensRepository struct {
+	mock.Mock
+}
+
+type MockRefreshTokensRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRefreshTokensRepository) EXPECT() *MockRefreshTokensRepository_Expecter {
+	return &MockRefreshTokensRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, token, userID, familyID, expiry
+func (_m *MockRefreshTokensRepository) Create(ctx context.Context, token string, userID int64, familyID uuid.UUID, expiry time.Time) error {
+	ret := _m.Called(ctx, token, userID, familyID, expiry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, uuid.UUID, time.Time) error); ok {
+		r0 = rf(ctx, token, userID, familyID, expiry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRefreshTokensRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockRefreshTokensRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+//   - userID int64
+//   - familyID uuid.UUID
+//   - expiry time.Time
+func (_e *MockRefreshTokensRepository_Expecter) Create(ctx interface{}, token interface{}, userID interface{}, familyID interface{}, expiry interface{}) *MockRefreshTokensRepository_Create_Call {
+	return &MockRefreshTokensRepository_Create_Call{Call: _e.mock.On("Create", ctx, token, userID, familyID, expiry)}
+}
+
+func (_c *MockRefreshTokensRepository_Create_Call) Run(run func(ctx context.Context, token string, userID int64, familyID uuid.UUID, expiry time.Time)) *MockRefreshTokensRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64), args[3].(uuid.UUID), args[4].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_Create_Call) Return(_a0 error) *MockRefreshTokensRepository_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_Create_Call) RunAndReturn(run func(context.Context, string, int64, uuid.UUID, time.Time) error) *MockRefreshTokensRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByToken provides a mock function with given fields: ctx, token
+func (_m *MockRefreshTokensRepository) GetByToken(ctx context.Context, token string) (*RefreshToken, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByToken")
+	}
+
+	var r0 *RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*RefreshToken, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *RefreshToken); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*RefreshToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRefreshTokensRepository_GetByToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByToken'
+type MockRefreshTokensRepository_GetByToken_Call struct {
+	*mock.Call
+}
+
+// GetByToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *MockRefreshTokensRepository_Expecter) GetByToken(ctx interface{}, token interface{}) *MockRefreshTokensRepository_GetByToken_Call {
+	return &MockRefreshTokensRepository_GetByToken_Call{Call: _e.mock.On("GetByToken", ctx, token)}
+}
+
+func (_c *MockRefreshTokensRepository_GetByToken_Call) Run(run func(ctx context.Context, token string)) *MockRefreshTokensRepository_GetByToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_GetByToken_Call) Return(_a0 *RefreshToken, _a1 error) *MockRefreshTokensRepository_GetByToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_GetByToken_Call) RunAndReturn(run func(context.Context, string) (*RefreshToken, error)) *MockRefreshTokensRepository_GetByToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeFamily provides a mock function with given fields: ctx, familyID
+func (_m *MockRefreshTokensRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	ret := _m.Called(ctx, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeFamily")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, familyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRefreshTokensRepository_RevokeFamily_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeFamily'
+type MockRefreshTokensRepository_RevokeFamily_Call struct {
+	*mock.Call
+}
+
+// RevokeFamily is a helper method to define mock.On call
+//   - ctx context.Context
+//   - familyID uuid.UUID
+func (_e *MockRefreshTokensRepository_Expecter) RevokeFamily(ctx interface{}, familyID interface{}) *MockRefreshTokensRepository_RevokeFamily_Call {
+	return &MockRefreshTokensRepository_RevokeFamily_Call{Call: _e.mock.On("RevokeFamily", ctx, familyID)}
+}
+
+func (_c *MockRefreshTokensRepository_RevokeFamily_Call) Run(run func(ctx context.Context, familyID uuid.UUID)) *MockRefreshTokensRepository_RevokeFamily_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_RevokeFamily_Call) Return(_a0 error) *MockRefreshTokensRepository_RevokeFamily_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_RevokeFamily_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockRefreshTokensRepository_RevokeFamily_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Rotate provides a mock function with given fields: ctx, old, token, expiry
+func (_m *MockRefreshTokensRepository) Rotate(ctx context.Context, old *RefreshToken, token string, expiry time.Time) error {
+	ret := _m.Called(ctx, old, token, expiry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rotate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *RefreshToken, string, time.Time) error); ok {
+		r0 = rf(ctx, old, token, expiry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRefreshTokensRepository_Rotate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rotate'
+type MockRefreshTokensRepository_Rotate_Call struct {
+	*mock.Call
+}
+
+// Rotate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - old *RefreshToken
+//   - token string
+//   - expiry time.Time
+func (_e *MockRefreshTokensRepository_Expecter) Rotate(ctx interface{}, old interface{}, token interface{}, expiry interface{}) *MockRefreshTokensRepository_Rotate_Call {
+	return &MockRefreshTokensRepository_Rotate_Call{Call: _e.mock.On("Rotate", ctx, old, token, expiry)}
+}
+
+func (_c *MockRefreshTokensRepository_Rotate_Call) Run(run func(ctx context.Context, old *RefreshToken, token string, expiry time.Time)) *MockRefreshTokensRepository_Rotate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*RefreshToken), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_Rotate_Call) Return(_a0 error) *MockRefreshTokensRepository_Rotate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRefreshTokensRepository_Rotate_Call) RunAndReturn(run func(context.Context, *RefreshToken, string, time.Time) error) *MockRefreshTokensRepository_Rotate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRefreshTokensRepository creates a new instance of MockRefreshTokensRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRefreshTokensRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRefreshTokensRepository {
+	mock := &MockRefreshTokensRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,142 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRevokedTokensRepository is an autogenerated mock type for the RevokedTokensRepository type
+type MockRevokedTokensRepository struct {
+	mock.Mock
+}
+
+type MockRevokedTokensRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRevokedTokensRepository) EXPECT() *MockRevokedTokensRepository_Expecter {
+	return &MockRevokedTokensRepository_Expecter{mock: &_m.Mock}
+}
+
+// IsRevoked provides a mock function with given fields: ctx, jti
+func (_m *MockRevokedTokensRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	ret := _m.Called(ctx, jti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, jti)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, jti)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, jti)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRevokedTokensRepository_IsRevoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsRevoked'
+type MockRevokedTokensRepository_IsRevoked_Call struct {
+	*mock.Call
+}
+
+// IsRevoked is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jti string
+func (_e *MockRevokedTokensRepository_Expecter) IsRevoked(ctx interface{}, jti interface{}) *MockRevokedTokensRepository_IsRevoked_Call {
+	return &MockRevokedTokensRepository_IsRevoked_Call{Call: _e.mock.On("IsRevoked", ctx, jti)}
+}
+
+func (_c *MockRevokedTokensRepository_IsRevoked_Call) Run(run func(ctx context.Context, jti string)) *MockRevokedTokensRepository_IsRevoked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockRevokedTokensRepository_IsRevoked_Call) Return(_a0 bool, _a1 error) *MockRevokedTokensRepository_IsRevoked_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRevokedTokensRepository_IsRevoked_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *MockRevokedTokensRepository_IsRevoked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revoke provides a mock function with given fields: ctx, jti, expiry
+func (_m *MockRevokedTokensRepository) Revoke(ctx context.Context, jti string, expiry time.Time) error {
+	ret := _m.Called(ctx, jti, expiry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, jti, expiry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRevokedTokensRepository_Revoke_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revoke'
+type MockRevokedTokensRepository_Revoke_Call struct {
+	*mock.Call
+}
+
+// Revoke is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jti string
+//   - expiry time.Time
+func (_e *MockRevokedTokensRepository_Expecter) Revoke(ctx interface{}, jti interface{}, expiry interface{}) *MockRevokedTokensRepository_Revoke_Call {
+	return &MockRevokedTokensRepository_Revoke_Call{Call: _e.mock.On("Revoke", ctx, jti, expiry)}
+}
+
+func (_c *MockRevokedTokensRepository_Revoke_Call) Run(run func(ctx context.Context, jti string, expiry time.Time)) *MockRevokedTokensRepository_Revoke_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockRevokedTokensRepository_Revoke_Call) Return(_a0 error) *MockRevokedTokensRepository_Revoke_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRevokedTokensRepository_Revoke_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *MockRevokedTokensRepository_Revoke_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRevokedTokensRepository creates a new instance of MockRevokedTokensRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRevokedTokensRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRevokedTokensRepository {
+	mock := &MockRevokedTokensRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
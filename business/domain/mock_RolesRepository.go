@@ -80,6 +80,123 @@ func (_c *MockRolesRepository_GetByRoleType_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// GetPermissions provides a mock function with given fields: ctx, roleID
+func (_m *MockRolesRepository) GetPermissions(ctx context.Context, roleID int64) ([]Permission, error) {
+	ret := _m.Called(ctx, roleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPermissions")
+	}
+
+	var r0 []Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]Permission, error)); ok {
+		return rf(ctx, roleID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []Permission); ok {
+		r0 = rf(ctx, roleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, roleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRolesRepository_GetPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPermissions'
+type MockRolesRepository_GetPermissions_Call struct {
+	*mock.Call
+}
+
+// GetPermissions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - roleID int64
+func (_e *MockRolesRepository_Expecter) GetPermissions(ctx interface{}, roleID interface{}) *MockRolesRepository_GetPermissions_Call {
+	return &MockRolesRepository_GetPermissions_Call{Call: _e.mock.On("GetPermissions", ctx, roleID)}
+}
+
+func (_c *MockRolesRepository_GetPermissions_Call) Run(run func(ctx context.Context, roleID int64)) *MockRolesRepository_GetPermissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockRolesRepository_GetPermissions_Call) Return(_a0 []Permission, _a1 error) *MockRolesRepository_GetPermissions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRolesRepository_GetPermissions_Call) RunAndReturn(run func(context.Context, int64) ([]Permission, error)) *MockRolesRepository_GetPermissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *MockRolesRepository) List(ctx context.Context) ([]Role, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]Role, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []Role); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRolesRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockRolesRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockRolesRepository_Expecter) List(ctx interface{}) *MockRolesRepository_List_Call {
+	return &MockRolesRepository_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *MockRolesRepository_List_Call) Run(run func(ctx context.Context)) *MockRolesRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockRolesRepository_List_Call) Return(_a0 []Role, _a1 error) *MockRolesRepository_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRolesRepository_List_Call) RunAndReturn(run func(context.Context) ([]Role, error)) *MockRolesRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockRolesRepository creates a new instance of MockRolesRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockRolesRepository(t interface {
@@ -0,0 +1,359 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockSessionsRepository is an autogenerated mock type for the SessionsRepository type
+type MockSessionsRepository struct {
+	mock.Mock
+}
+
+type MockSessionsRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSessionsRepository) EXPECT() *MockSessionsRepository_Expecter {
+	return &MockSessionsRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, session
+func (_m *MockSessionsRepository) Create(ctx context.Context, session *Session) error {
+	ret := _m.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *Session) error); ok {
+		r0 = rf(ctx, session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSessionsRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockSessionsRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - session *Session
+func (_e *MockSessionsRepository_Expecter) Create(ctx interface{}, session interface{}) *MockSessionsRepository_Create_Call {
+	return &MockSessionsRepository_Create_Call{Call: _e.mock.On("Create", ctx, session)}
+}
+
+func (_c *MockSessionsRepository_Create_Call) Run(run func(ctx context.Context, session *Session)) *MockSessionsRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*Session))
+	})
+	return _c
+}
+
+func (_c *MockSessionsRepository_Create_Call) Return(_a0 error) *MockSessionsRepository_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSessionsRepository_Create_Call) RunAndReturn(run func(context.Context, *Session) error) *MockSessionsRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *MockSessionsRepository) Get(ctx context.Context, id uuid.UUID) (*Session, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*Session, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *Session); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSessionsRepository_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockSessionsRepository_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockSessionsRepository_Expecter) Get(ctx interface{}, id interface{}) *MockSessionsRepository_Get_Call {
+	return &MockSessionsRepository_Get_Call{Call: _e.mock.On("Get", ctx, id)}
+}
+
+func (_c *MockSessionsRepository_Get_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockSessionsRepository_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockSessionsRepository_Get_Call) Return(_a0 *Session, _a1 error) *MockSessionsRepository_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSessionsRepository_Get_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*Session, error)) *MockSessionsRepository_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByUser provides a mock function with given fields: ctx, userID
+func (_m *MockSessionsRepository) ListByUser(ctx context.Context, userID int64) ([]Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUser")
+	}
+
+	var r0 []Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]Session, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSessionsRepository_ListByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByUser'
+type MockSessionsRepository_ListByUser_Call struct {
+	*mock.Call
+}
+
+// ListByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+func (_e *MockSessionsRepository_Expecter) ListByUser(ctx interface{}, userID interface{}) *MockSessionsRepository_ListByUser_Call {
+	return &MockSessionsRepository_ListByUser_Call{Call: _e.mock.On("ListByUser", ctx, userID)}
+}
+
+func (_c *MockSessionsRepository_ListByUser_Call) Run(run func(ctx context.Context, userID int64)) *MockSessionsRepository_ListByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockSessionsRepository_ListByUser_Call) Return(_a0 []Session, _a1 error) *MockSessionsRepository_ListByUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSessionsRepository_ListByUser_Call) RunAndReturn(run func(context.Context, int64) ([]Session, error)) *MockSessionsRepository_ListByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revoke provides a mock function with given fields: ctx, userID, id
+func (_m *MockSessionsRepository) Revoke(ctx context.Context, userID int64, id uuid.UUID) error {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSessionsRepository_Revoke_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revoke'
+type MockSessionsRepository_Revoke_Call struct {
+	*mock.Call
+}
+
+// Revoke is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - id uuid.UUID
+func (_e *MockSessionsRepository_Expecter) Revoke(ctx interface{}, userID interface{}, id interface{}) *MockSessionsRepository_Revoke_Call {
+	return &MockSessionsRepository_Revoke_Call{Call: _e.mock.On("Revoke", ctx, userID, id)}
+}
+
+func (_c *MockSessionsRepository_Revoke_Call) Run(run func(ctx context.Context, userID int64, id uuid.UUID)) *MockSessionsRepository_Revoke_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockSessionsRepository_Revoke_Call) Return(_a0 error) *MockSessionsRepository_Revoke_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSessionsRepository_Revoke_Call) RunAndReturn(run func(context.Context, int64, uuid.UUID) error) *MockSessionsRepository_Revoke_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeOthers provides a mock function with given fields: ctx, userID, keep
+func (_m *MockSessionsRepository) RevokeOthers(ctx context.Context, userID int64, keep uuid.UUID) (int64, error) {
+	ret := _m.Called(ctx, userID, keep)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeOthers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) (int64, error)); ok {
+		return rf(ctx, userID, keep)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) int64); ok {
+		r0 = rf(ctx, userID, keep)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, keep)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSessionsRepository_RevokeOthers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeOthers'
+type MockSessionsRepository_RevokeOthers_Call struct {
+	*mock.Call
+}
+
+// RevokeOthers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - keep uuid.UUID
+func (_e *MockSessionsRepository_Expecter) RevokeOthers(ctx interface{}, userID interface{}, keep interface{}) *MockSessionsRepository_RevokeOthers_Call {
+	return &MockSessionsRepository_RevokeOthers_Call{Call: _e.mock.On("RevokeOthers", ctx, userID, keep)}
+}
+
+func (_c *MockSessionsRepository_RevokeOthers_Call) Run(run func(ctx context.Context, userID int64, keep uuid.UUID)) *MockSessionsRepository_RevokeOthers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockSessionsRepository_RevokeOthers_Call) Return(_a0 int64, _a1 error) *MockSessionsRepository_RevokeOthers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSessionsRepository_RevokeOthers_Call) RunAndReturn(run func(context.Context, int64, uuid.UUID) (int64, error)) *MockSessionsRepository_RevokeOthers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Touch provides a mock function with given fields: ctx, id, at, ip
+func (_m *MockSessionsRepository) Touch(ctx context.Context, id uuid.UUID, at time.Time, ip string) error {
+	ret := _m.Called(ctx, id, at, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Touch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, string) error); ok {
+		r0 = rf(ctx, id, at, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSessionsRepository_Touch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Touch'
+type MockSessionsRepository_Touch_Call struct {
+	*mock.Call
+}
+
+// Touch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - at time.Time
+//   - ip string
+func (_e *MockSessionsRepository_Expecter) Touch(ctx interface{}, id interface{}, at interface{}, ip interface{}) *MockSessionsRepository_Touch_Call {
+	return &MockSessionsRepository_Touch_Call{Call: _e.mock.On("Touch", ctx, id, at, ip)}
+}
+
+func (_c *MockSessionsRepository_Touch_Call) Run(run func(ctx context.Context, id uuid.UUID, at time.Time, ip string)) *MockSessionsRepository_Touch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(time.Time), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockSessionsRepository_Touch_Call) Return(_a0 error) *MockSessionsRepository_Touch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSessionsRepository_Touch_Call) RunAndReturn(run func(context.Context, uuid.UUID, time.Time, string) error) *MockSessionsRepository_Touch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSessionsRepository creates a new instance of MockSessionsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSessionsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSessionsRepository {
+	mock := &MockSessionsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
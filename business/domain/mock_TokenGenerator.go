@@ -0,0 +1,95 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package domain
+
+import (
+	context "context"
+
+	uuid "github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockTokenGenerator is an autogenerated mock type for the TokenGenerator type
+type MockTokenGenerator struct {
+	mock.Mock
+}
+
+type MockTokenGenerator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTokenGenerator) EXPECT() *MockTokenGenerator_Expecter {
+	return &MockTokenGenerator_Expecter{mock: &_m.Mock}
+}
+
+// GenerateToken provides a mock function with given fields: ctx, userID, sessionID
+func (_m *MockTokenGenerator) GenerateToken(ctx context.Context, userID int64, sessionID uuid.UUID) (string, error) {
+	ret := _m.Called(ctx, userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) (string, error)); ok {
+		return rf(ctx, userID, sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, uuid.UUID) string); ok {
+		r0 = rf(ctx, userID, sessionID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTokenGenerator_GenerateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GenerateToken'
+type MockTokenGenerator_GenerateToken_Call struct {
+	*mock.Call
+}
+
+// GenerateToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - sessionID uuid.UUID
+func (_e *MockTokenGenerator_Expecter) GenerateToken(ctx interface{}, userID interface{}, sessionID interface{}) *MockTokenGenerator_GenerateToken_Call {
+	return &MockTokenGenerator_GenerateToken_Call{Call: _e.mock.On("GenerateToken", ctx, userID, sessionID)}
+}
+
+func (_c *MockTokenGenerator_GenerateToken_Call) Run(run func(ctx context.Context, userID int64, sessionID uuid.UUID)) *MockTokenGenerator_GenerateToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockTokenGenerator_GenerateToken_Call) Return(_a0 string, _a1 error) *MockTokenGenerator_GenerateToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTokenGenerator_GenerateToken_Call) RunAndReturn(run func(context.Context, int64, uuid.UUID) (string, error)) *MockTokenGenerator_GenerateToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTokenGenerator creates a new instance of MockTokenGenerator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTokenGenerator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTokenGenerator {
+	mock := &MockTokenGenerator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -21,6 +21,53 @@ func (_m *MockUsersCache) EXPECT() *MockUsersCache_Expecter {
 	return &MockUsersCache_Expecter{mock: &_m.Mock}
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockUsersCache) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUsersCache_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockUsersCache_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockUsersCache_Expecter) Delete(ctx interface{}, id interface{}) *MockUsersCache_Delete_Call {
+	return &MockUsersCache_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockUsersCache_Delete_Call) Run(run func(ctx context.Context, id int64)) *MockUsersCache_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockUsersCache_Delete_Call) Return(_a0 error) *MockUsersCache_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUsersCache_Delete_Call) RunAndReturn(run func(context.Context, int64) error) *MockUsersCache_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Get provides a mock function with given fields: ctx, id
 func (_m *MockUsersCache) Get(ctx context.Context, id int64) (*User, error) {
 	ret := _m.Called(ctx, id)
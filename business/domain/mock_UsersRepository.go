@@ -25,21 +25,31 @@ func (_m *MockUsersRepository) EXPECT() *MockUsersRepository_Expecter {
 }
 
 // Activate provides a mock function with given fields: ctx, token
-func (_m *MockUsersRepository) Activate(ctx context.Context, token string) error {
+func (_m *MockUsersRepository) Activate(ctx context.Context, token string) (int64, error) {
 	ret := _m.Called(ctx, token)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Activate")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
 		r0 = rf(ctx, token)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // MockUsersRepository_Activate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Activate'
@@ -61,12 +71,60 @@ func (_c *MockUsersRepository_Activate_Call) Run(run func(ctx context.Context, t
 	return _c
 }
 
-func (_c *MockUsersRepository_Activate_Call) Return(_a0 error) *MockUsersRepository_Activate_Call {
+func (_c *MockUsersRepository_Activate_Call) Return(_a0 int64, _a1 error) *MockUsersRepository_Activate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUsersRepository_Activate_Call) RunAndReturn(run func(context.Context, string) (int64, error)) *MockUsersRepository_Activate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ban provides a mock function with given fields: ctx, id, at
+func (_m *MockUsersRepository) Ban(ctx context.Context, id int64, at time.Time) error {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ban")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) error); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUsersRepository_Ban_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ban'
+type MockUsersRepository_Ban_Call struct {
+	*mock.Call
+}
+
+// Ban is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - at time.Time
+func (_e *MockUsersRepository_Expecter) Ban(ctx interface{}, id interface{}, at interface{}) *MockUsersRepository_Ban_Call {
+	return &MockUsersRepository_Ban_Call{Call: _e.mock.On("Ban", ctx, id, at)}
+}
+
+func (_c *MockUsersRepository_Ban_Call) Run(run func(ctx context.Context, id int64, at time.Time)) *MockUsersRepository_Ban_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_Ban_Call) Return(_a0 error) *MockUsersRepository_Ban_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockUsersRepository_Activate_Call) RunAndReturn(run func(context.Context, string) error) *MockUsersRepository_Activate_Call {
+func (_c *MockUsersRepository_Ban_Call) RunAndReturn(run func(context.Context, int64, time.Time) error) *MockUsersRepository_Ban_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -119,17 +177,17 @@ func (_c *MockUsersRepository_Create_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
-// CreateAndInvite provides a mock function with given fields: ctx, user, token, expiration
-func (_m *MockUsersRepository) CreateAndInvite(ctx context.Context, user *User, token string, expiration time.Duration) error {
-	ret := _m.Called(ctx, user, token, expiration)
+// CreateAndInvite provides a mock function with given fields: ctx, user, token, expiration, invitation
+func (_m *MockUsersRepository) CreateAndInvite(ctx context.Context, user *User, token string, expiration time.Duration, invitation OutboxMessage) error {
+	ret := _m.Called(ctx, user, token, expiration, invitation)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateAndInvite")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *User, string, time.Duration) error); ok {
-		r0 = rf(ctx, user, token, expiration)
+	if rf, ok := ret.Get(0).(func(context.Context, *User, string, time.Duration, OutboxMessage) error); ok {
+		r0 = rf(ctx, user, token, expiration, invitation)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -147,13 +205,14 @@ type MockUsersRepository_CreateAndInvite_Call struct {
 //   - user *User
 //   - token string
 //   - expiration time.Duration
-func (_e *MockUsersRepository_Expecter) CreateAndInvite(ctx interface{}, user interface{}, token interface{}, expiration interface{}) *MockUsersRepository_CreateAndInvite_Call {
-	return &MockUsersRepository_CreateAndInvite_Call{Call: _e.mock.On("CreateAndInvite", ctx, user, token, expiration)}
+//   - invitation OutboxMessage
+func (_e *MockUsersRepository_Expecter) CreateAndInvite(ctx interface{}, user interface{}, token interface{}, expiration interface{}, invitation interface{}) *MockUsersRepository_CreateAndInvite_Call {
+	return &MockUsersRepository_CreateAndInvite_Call{Call: _e.mock.On("CreateAndInvite", ctx, user, token, expiration, invitation)}
 }
 
-func (_c *MockUsersRepository_CreateAndInvite_Call) Run(run func(ctx context.Context, user *User, token string, expiration time.Duration)) *MockUsersRepository_CreateAndInvite_Call {
+func (_c *MockUsersRepository_CreateAndInvite_Call) Run(run func(ctx context.Context, user *User, token string, expiration time.Duration, invitation OutboxMessage)) *MockUsersRepository_CreateAndInvite_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*User), args[2].(string), args[3].(time.Duration))
+		run(args[0].(context.Context), args[1].(*User), args[2].(string), args[3].(time.Duration), args[4].(OutboxMessage))
 	})
 	return _c
 }
@@ -163,7 +222,217 @@ func (_c *MockUsersRepository_CreateAndInvite_Call) Return(_a0 error) *MockUsers
 	return _c
 }
 
-func (_c *MockUsersRepository_CreateAndInvite_Call) RunAndReturn(run func(context.Context, *User, string, time.Duration) error) *MockUsersRepository_CreateAndInvite_Call {
+func (_c *MockUsersRepository_CreateAndInvite_Call) RunAndReturn(run func(context.Context, *User, string, time.Duration, OutboxMessage) error) *MockUsersRepository_CreateAndInvite_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreatePasswordReset provides a mock function with given fields: ctx, userID, token, expiration, email
+func (_m *MockUsersRepository) CreatePasswordReset(ctx context.Context, userID int64, token string, expiration time.Duration, email OutboxMessage) error {
+	ret := _m.Called(ctx, userID, token, expiration, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePasswordReset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, time.Duration, OutboxMessage) error); ok {
+		r0 = rf(ctx, userID, token, expiration, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUsersRepository_CreatePasswordReset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePasswordReset'
+type MockUsersRepository_CreatePasswordReset_Call struct {
+	*mock.Call
+}
+
+// CreatePasswordReset is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - token string
+//   - expiration time.Duration
+//   - email OutboxMessage
+func (_e *MockUsersRepository_Expecter) CreatePasswordReset(ctx interface{}, userID interface{}, token interface{}, expiration interface{}, email interface{}) *MockUsersRepository_CreatePasswordReset_Call {
+	return &MockUsersRepository_CreatePasswordReset_Call{Call: _e.mock.On("CreatePasswordReset", ctx, userID, token, expiration, email)}
+}
+
+func (_c *MockUsersRepository_CreatePasswordReset_Call) Run(run func(ctx context.Context, userID int64, token string, expiration time.Duration, email OutboxMessage)) *MockUsersRepository_CreatePasswordReset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(time.Duration), args[4].(OutboxMessage))
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_CreatePasswordReset_Call) Return(_a0 error) *MockUsersRepository_CreatePasswordReset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUsersRepository_CreatePasswordReset_Call) RunAndReturn(run func(context.Context, int64, string, time.Duration, OutboxMessage) error) *MockUsersRepository_CreatePasswordReset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *MockUsersRepository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUsersRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockUsersRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockUsersRepository_Expecter) Delete(ctx interface{}, id interface{}) *MockUsersRepository_Delete_Call {
+	return &MockUsersRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *MockUsersRepository_Delete_Call) Run(run func(ctx context.Context, id int64)) *MockUsersRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_Delete_Call) Return(_a0 error) *MockUsersRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUsersRepository_Delete_Call) RunAndReturn(run func(context.Context, int64) error) *MockUsersRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteExpiredInvitations provides a mock function with given fields: ctx
+func (_m *MockUsersRepository) DeleteExpiredInvitations(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredInvitations")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUsersRepository_DeleteExpiredInvitations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredInvitations'
+type MockUsersRepository_DeleteExpiredInvitations_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredInvitations is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockUsersRepository_Expecter) DeleteExpiredInvitations(ctx interface{}) *MockUsersRepository_DeleteExpiredInvitations_Call {
+	return &MockUsersRepository_DeleteExpiredInvitations_Call{Call: _e.mock.On("DeleteExpiredInvitations", ctx)}
+}
+
+func (_c *MockUsersRepository_DeleteExpiredInvitations_Call) Run(run func(ctx context.Context)) *MockUsersRepository_DeleteExpiredInvitations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_DeleteExpiredInvitations_Call) Return(_a0 int64, _a1 error) *MockUsersRepository_DeleteExpiredInvitations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUsersRepository_DeleteExpiredInvitations_Call) RunAndReturn(run func(context.Context) (int64, error)) *MockUsersRepository_DeleteExpiredInvitations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUnactivated provides a mock function with given fields: ctx, createdBefore
+func (_m *MockUsersRepository) DeleteUnactivated(ctx context.Context, createdBefore time.Time) (int64, error) {
+	ret := _m.Called(ctx, createdBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUnactivated")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, createdBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, createdBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, createdBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUsersRepository_DeleteUnactivated_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUnactivated'
+type MockUsersRepository_DeleteUnactivated_Call struct {
+	*mock.Call
+}
+
+// DeleteUnactivated is a helper method to define mock.On call
+//   - ctx context.Context
+//   - createdBefore time.Time
+func (_e *MockUsersRepository_Expecter) DeleteUnactivated(ctx interface{}, createdBefore interface{}) *MockUsersRepository_DeleteUnactivated_Call {
+	return &MockUsersRepository_DeleteUnactivated_Call{Call: _e.mock.On("DeleteUnactivated", ctx, createdBefore)}
+}
+
+func (_c *MockUsersRepository_DeleteUnactivated_Call) Run(run func(ctx context.Context, createdBefore time.Time)) *MockUsersRepository_DeleteUnactivated_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_DeleteUnactivated_Call) Return(_a0 int64, _a1 error) *MockUsersRepository_DeleteUnactivated_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUsersRepository_DeleteUnactivated_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *MockUsersRepository_DeleteUnactivated_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -286,6 +555,171 @@ func (_c *MockUsersRepository_GetByID_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// List provides a mock function with given fields: ctx, query
+func (_m *MockUsersRepository) List(ctx context.Context, query UsersQuery) (UsersPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 UsersPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, UsersQuery) (UsersPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, UsersQuery) UsersPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		r0 = ret.Get(0).(UsersPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, UsersQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUsersRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockUsersRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query UsersQuery
+func (_e *MockUsersRepository_Expecter) List(ctx interface{}, query interface{}) *MockUsersRepository_List_Call {
+	return &MockUsersRepository_List_Call{Call: _e.mock.On("List", ctx, query)}
+}
+
+func (_c *MockUsersRepository_List_Call) Run(run func(ctx context.Context, query UsersQuery)) *MockUsersRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(UsersQuery))
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_List_Call) Return(_a0 UsersPage, _a1 error) *MockUsersRepository_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUsersRepository_List_Call) RunAndReturn(run func(context.Context, UsersQuery) (UsersPage, error)) *MockUsersRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reinvite provides a mock function with given fields: ctx, userID, token, expiration, invitation
+func (_m *MockUsersRepository) Reinvite(ctx context.Context, userID int64, token string, expiration time.Duration, invitation OutboxMessage) error {
+	ret := _m.Called(ctx, userID, token, expiration, invitation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reinvite")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, time.Duration, OutboxMessage) error); ok {
+		r0 = rf(ctx, userID, token, expiration, invitation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUsersRepository_Reinvite_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reinvite'
+type MockUsersRepository_Reinvite_Call struct {
+	*mock.Call
+}
+
+// Reinvite is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - token string
+//   - expiration time.Duration
+//   - invitation OutboxMessage
+func (_e *MockUsersRepository_Expecter) Reinvite(ctx interface{}, userID interface{}, token interface{}, expiration interface{}, invitation interface{}) *MockUsersRepository_Reinvite_Call {
+	return &MockUsersRepository_Reinvite_Call{Call: _e.mock.On("Reinvite", ctx, userID, token, expiration, invitation)}
+}
+
+func (_c *MockUsersRepository_Reinvite_Call) Run(run func(ctx context.Context, userID int64, token string, expiration time.Duration, invitation OutboxMessage)) *MockUsersRepository_Reinvite_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].(time.Duration), args[4].(OutboxMessage))
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_Reinvite_Call) Return(_a0 error) *MockUsersRepository_Reinvite_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUsersRepository_Reinvite_Call) RunAndReturn(run func(context.Context, int64, string, time.Duration, OutboxMessage) error) *MockUsersRepository_Reinvite_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function with given fields: ctx, token, password
+func (_m *MockUsersRepository) ResetPassword(ctx context.Context, token string, password []byte) (int64, error) {
+	ret := _m.Called(ctx, token, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte) (int64, error)); ok {
+		return rf(ctx, token, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte) int64); ok {
+		r0 = rf(ctx, token, password)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []byte) error); ok {
+		r1 = rf(ctx, token, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUsersRepository_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type MockUsersRepository_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+//   - password []byte
+func (_e *MockUsersRepository_Expecter) ResetPassword(ctx interface{}, token interface{}, password interface{}) *MockUsersRepository_ResetPassword_Call {
+	return &MockUsersRepository_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, token, password)}
+}
+
+func (_c *MockUsersRepository_ResetPassword_Call) Run(run func(ctx context.Context, token string, password []byte)) *MockUsersRepository_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]byte))
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_ResetPassword_Call) Return(_a0 int64, _a1 error) *MockUsersRepository_ResetPassword_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUsersRepository_ResetPassword_Call) RunAndReturn(run func(context.Context, string, []byte) (int64, error)) *MockUsersRepository_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // RevertCreateAndInvite provides a mock function with given fields: ctx, id
 func (_m *MockUsersRepository) RevertCreateAndInvite(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -333,6 +767,197 @@ func (_c *MockUsersRepository_RevertCreateAndInvite_Call) RunAndReturn(run func(
 	return _c
 }
 
+// SetActive provides a mock function with given fields: ctx, id, active
+func (_m *MockUsersRepository) SetActive(ctx context.Context, id int64, active bool) error {
+	ret := _m.Called(ctx, id, active)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetActive")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) error); ok {
+		r0 = rf(ctx, id, active)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUsersRepository_SetActive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetActive'
+type MockUsersRepository_SetActive_Call struct {
+	*mock.Call
+}
+
+// SetActive is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - active bool
+func (_e *MockUsersRepository_Expecter) SetActive(ctx interface{}, id interface{}, active interface{}) *MockUsersRepository_SetActive_Call {
+	return &MockUsersRepository_SetActive_Call{Call: _e.mock.On("SetActive", ctx, id, active)}
+}
+
+func (_c *MockUsersRepository_SetActive_Call) Run(run func(ctx context.Context, id int64, active bool)) *MockUsersRepository_SetActive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_SetActive_Call) Return(_a0 error) *MockUsersRepository_SetActive_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUsersRepository_SetActive_Call) RunAndReturn(run func(context.Context, int64, bool) error) *MockUsersRepository_SetActive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Unban provides a mock function with given fields: ctx, id
+func (_m *MockUsersRepository) Unban(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unban")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUsersRepository_Unban_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unban'
+type MockUsersRepository_Unban_Call struct {
+	*mock.Call
+}
+
+// Unban is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *MockUsersRepository_Expecter) Unban(ctx interface{}, id interface{}) *MockUsersRepository_Unban_Call {
+	return &MockUsersRepository_Unban_Call{Call: _e.mock.On("Unban", ctx, id)}
+}
+
+func (_c *MockUsersRepository_Unban_Call) Run(run func(ctx context.Context, id int64)) *MockUsersRepository_Unban_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_Unban_Call) Return(_a0 error) *MockUsersRepository_Unban_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUsersRepository_Unban_Call) RunAndReturn(run func(context.Context, int64) error) *MockUsersRepository_Unban_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateProfile provides a mock function with given fields: ctx, id, payload
+func (_m *MockUsersRepository) UpdateProfile(ctx context.Context, id int64, payload UpdateProfilePayload) error {
+	ret := _m.Called(ctx, id, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, UpdateProfilePayload) error); ok {
+		r0 = rf(ctx, id, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUsersRepository_UpdateProfile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateProfile'
+type MockUsersRepository_UpdateProfile_Call struct {
+	*mock.Call
+}
+
+// UpdateProfile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - payload UpdateProfilePayload
+func (_e *MockUsersRepository_Expecter) UpdateProfile(ctx interface{}, id interface{}, payload interface{}) *MockUsersRepository_UpdateProfile_Call {
+	return &MockUsersRepository_UpdateProfile_Call{Call: _e.mock.On("UpdateProfile", ctx, id, payload)}
+}
+
+func (_c *MockUsersRepository_UpdateProfile_Call) Run(run func(ctx context.Context, id int64, payload UpdateProfilePayload)) *MockUsersRepository_UpdateProfile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(UpdateProfilePayload))
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_UpdateProfile_Call) Return(_a0 error) *MockUsersRepository_UpdateProfile_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUsersRepository_UpdateProfile_Call) RunAndReturn(run func(context.Context, int64, UpdateProfilePayload) error) *MockUsersRepository_UpdateProfile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateRole provides a mock function with given fields: ctx, id, roleID
+func (_m *MockUsersRepository) UpdateRole(ctx context.Context, id int64, roleID int64) error {
+	ret := _m.Called(ctx, id, roleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, roleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUsersRepository_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
+type MockUsersRepository_UpdateRole_Call struct {
+	*mock.Call
+}
+
+// UpdateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - roleID int64
+func (_e *MockUsersRepository_Expecter) UpdateRole(ctx interface{}, id interface{}, roleID interface{}) *MockUsersRepository_UpdateRole_Call {
+	return &MockUsersRepository_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, id, roleID)}
+}
+
+func (_c *MockUsersRepository_UpdateRole_Call) Run(run func(ctx context.Context, id int64, roleID int64)) *MockUsersRepository_UpdateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockUsersRepository_UpdateRole_Call) Return(_a0 error) *MockUsersRepository_UpdateRole_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUsersRepository_UpdateRole_Call) RunAndReturn(run func(context.Context, int64, int64) error) *MockUsersRepository_UpdateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockUsersRepository creates a new instance of MockUsersRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockUsersRepository(t interface {
@@ -0,0 +1,337 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var ErrUnknownOIDCProvider = errors.New("unknown identity provider")
+var ErrInvalidOIDCState = errors.New("invalid or expired login state")
+var ErrOIDCEmailNotVerified = errors.New("email not verified by the identity provider")
+var ErrOIDCLoginFailed = errors.New("login with the identity provider failed")
+
+// OIDCIdentity is the user an identity provider vouches for, from a verified
+// ID token.
+type OIDCIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OIDCProvider is an OpenID Connect identity provider users can log in with,
+// using the authorization code flow with PKCE.
+type OIDCProvider interface {
+	// Name identifies the provider in URLs and in the linked identities.
+	Name() string
+	// AuthCodeURL returns the URL of the provider to send the user to.
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error)
+	// Exchange exchanges the code the provider sent back for the identity of
+	// the user. It verifies the ID token, including its nonce.
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (OIDCIdentity, error)
+}
+
+// OIDCLoginState is what a login remembers between sending the user to the
+// provider and the provider sending them back.
+type OIDCLoginState struct {
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	Expiry       time.Time
+}
+
+// UserIdentity links the identity of a provider to a user.
+type UserIdentity struct {
+	Provider string
+	Subject  string
+	UserID   int64
+	Email    string
+}
+
+type OIDCRepository interface {
+	// SaveState stores the state of a login by the hash of its state
+	// parameter.
+	SaveState(ctx context.Context, hash string, state OIDCLoginState) error
+	// TakeState deletes and returns the state, so it is only used once. It
+	// returns ErrNotFound when the state is unknown or expired.
+	TakeState(ctx context.Context, hash string) (*OIDCLoginState, error)
+	GetIdentity(ctx context.Context, provider string, subject string) (*UserIdentity, error)
+	LinkIdentity(ctx context.Context, identity UserIdentity) error
+	// CreateUser creates an active user linked to the identity, in the same
+	// transaction. It sets the ID of both.
+	CreateUser(ctx context.Context, user *User, identity *UserIdentity) error
+}
+
+type OIDCConfig struct {
+	// StateExp is how long users have to log in with the provider.
+	StateExp time.Duration
+}
+
+// OIDCAuthorization starts a login with a provider: the client sends the
+// user to URL and checks the provider sends back the same State.
+type OIDCAuthorization struct {
+	URL   string `json:"authorization_url"`
+	State string `json:"state"`
+}
+
+type OIDCCallbackPayload struct {
+	Code  string `json:"code" validate:"required,max=2048"`
+	State string `json:"state" validate:"required,max=128"`
+}
+
+// OIDCUseCase logs users in with OpenID Connect providers. Identities are
+// linked to the user with the same email when the provider verified it, and
+// a user is created for the identities of unknown emails.
+type OIDCUseCase struct {
+	config     OIDCConfig
+	auth       *AuthUseCase
+	providers  map[string]OIDCProvider
+	repository OIDCRepository
+	users      UsersRepository
+	roles      RolesRepository
+	audit      AuditLogger
+	now        func() time.Time
+}
+
+func NewOIDCUseCase(
+	config OIDCConfig,
+	auth *AuthUseCase,
+	providers []OIDCProvider,
+	repository OIDCRepository,
+	users UsersRepository,
+	roles RolesRepository,
+	audit AuditLogger,
+) *OIDCUseCase {
+	byName := make(map[string]OIDCProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &OIDCUseCase{
+		config:     config,
+		auth:       auth,
+		providers:  byName,
+		repository: repository,
+		users:      users,
+		roles:      roles,
+		audit:      audit,
+		now:        time.Now,
+	}
+}
+
+// Start starts a login with the provider.
+func (uc *OIDCUseCase) Start(ctx context.Context, providerName string) (OIDCAuthorization, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return OIDCAuthorization{}, ErrUnknownOIDCProvider
+	}
+
+	var values [3]string
+	for i := range values {
+		value, err := oidcRandom()
+		if err != nil {
+			return OIDCAuthorization{}, err
+		}
+		values[i] = value
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	url, err := provider.AuthCodeURL(ctx, state, nonce, pkceChallenge(verifier))
+	if err != nil {
+		return OIDCAuthorization{}, err
+	}
+
+	err = uc.repository.SaveState(ctx, hashToken(state), OIDCLoginState{
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		Expiry:       uc.now().Add(uc.config.StateExp),
+	})
+	if err != nil {
+		return OIDCAuthorization{}, err
+	}
+
+	return OIDCAuthorization{URL: url, State: state}, nil
+}
+
+// Callback completes the login the provider sent the user back from. Like
+// CreateToken, it returns a challenge instead of tokens for users with
+// two-factor authentication.
+func (uc *OIDCUseCase) Callback(ctx context.Context, providerName string, payload OIDCCallbackPayload) (LoginResult, error) {
+	provider, ok := uc.providers[providerName]
+	if !ok {
+		return LoginResult{}, ErrUnknownOIDCProvider
+	}
+
+	state, err := uc.repository.TakeState(ctx, hashToken(payload.State))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return LoginResult{}, ErrInvalidOIDCState
+		}
+		return LoginResult{}, err
+	}
+	if state.Provider != providerName {
+		return LoginResult{}, ErrInvalidOIDCState
+	}
+
+	identity, err := provider.Exchange(ctx, payload.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		return LoginResult{}, fmt.Errorf("%w: %w", ErrOIDCLoginFailed, err)
+	}
+
+	user, err := uc.user(ctx, providerName, identity)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if user.BannedAt != nil {
+		uc.auth.loginFailed(ctx, user.ID, user.Email, "banned")
+		return LoginResult{}, ErrUserBanned
+	}
+	if !user.IsActive {
+		uc.auth.loginFailed(ctx, user.ID, user.Email, "inactive")
+		return LoginResult{}, ErrUserInactive
+	}
+
+	challenge, err := uc.auth.challenge(ctx, user.ID)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if challenge != nil {
+		return LoginResult{Challenge: challenge}, nil
+	}
+
+	tokens, err := uc.auth.issueTokens(ctx, user.ID)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	uc.auth.loggedIn(ctx, user.ID, "oidc:"+providerName)
+	return LoginResult{Tokens: tokens}, nil
+}
+
+// user returns the user linked to the identity, linking or creating one by
+// its email the first time.
+func (uc *OIDCUseCase) user(ctx context.Context, providerName string, identity OIDCIdentity) (*User, error) {
+	linked, err := uc.repository.GetIdentity(ctx, providerName, identity.Subject)
+	switch {
+	case err == nil:
+		return uc.users.GetByID(ctx, linked.UserID)
+	case !errors.Is(err, ErrNotFound):
+		return nil, err
+	}
+
+	// Linking by an unverified email would let anyone claim an account.
+	if !identity.EmailVerified || identity.Email == "" {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	link := UserIdentity{Provider: providerName, Subject: identity.Subject, Email: identity.Email}
+	user, err := uc.users.GetByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		link.UserID = user.ID
+		if err := uc.repository.LinkIdentity(ctx, link); err != nil {
+			return nil, err
+		}
+		uc.linked(ctx, user.ID, providerName, false)
+		return user, nil
+	case !errors.Is(err, ErrNotFound):
+		return nil, err
+	}
+
+	user, err = uc.createUser(ctx, identity, &link)
+	if err != nil {
+		return nil, err
+	}
+	uc.linked(ctx, user.ID, providerName, true)
+	return user, nil
+}
+
+func (uc *OIDCUseCase) linked(ctx context.Context, userID int64, providerName string, created bool) {
+	uc.audit.Record(ctx, AuditEvent{
+		ActorID:    userID,
+		Action:     AuditIdentityLinked,
+		TargetType: AuditTargetUser,
+		TargetID:   userID,
+		Metadata:   map[string]any{"provider": providerName, "created": created},
+	})
+}
+
+// oidcUsernameAttempts is how many usernames are tried for a new user before
+// giving up.
+const oidcUsernameAttempts = 3
+
+func (uc *OIDCUseCase) createUser(ctx context.Context, identity OIDCIdentity, link *UserIdentity) (*User, error) {
+	role, err := uc.roles.GetByRoleType(ctx, RoleTypeUser)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users of a provider log in with it, the password is never told.
+	password, err := oidcRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	base := oidcUsername(identity)
+	for attempt := range oidcUsernameAttempts {
+		user := &User{Username: base, Email: identity.Email, RoleID: role.ID, IsActive: true, Role: *role}
+		if attempt > 0 {
+			suffix, err := oidcRandom()
+			if err != nil {
+				return nil, err
+			}
+			user.Username = fmt.Sprintf("%s-%s", base, strings.ToLower(suffix[:6]))
+		}
+		if err := user.Password.Set(password); err != nil {
+			return nil, err
+		}
+
+		err := uc.repository.CreateUser(ctx, user, link)
+		if errors.Is(err, ErrDuplicateUsername) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+	return nil, ErrDuplicateUsername
+}
+
+var notUsername = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// oidcUsername derives a username from the name of the identity, or from
+// its email.
+func oidcUsername(identity OIDCIdentity) string {
+	name := identity.Name
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+	username := strings.Trim(notUsername.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if username == "" {
+		username = "user"
+	}
+	return username[:min(len(username), 64)]
+}
+
+// oidcRandom returns 32 random bytes, base64url encoded as the state, nonce
+// and PKCE verifier expect.
+func oidcRandom() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// pkceChallenge returns the S256 challenge of the PKCE verifier.
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
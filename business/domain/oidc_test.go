@@ -0,0 +1,222 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOIDCUseCase(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	identity := OIDCIdentity{Subject: "248289761001", Email: "arya@example.com", EmailVerified: true, Name: "Arya Stark"}
+	user := &User{ID: 42, Username: "arya", Email: "arya@example.com", IsActive: true}
+	state := &OIDCLoginState{Provider: "google", Nonce: "nonce", CodeVerifier: "verifier", Expiry: now.Add(time.Minute)}
+	payload := OIDCCallbackPayload{Code: "code", State: "state"}
+
+	type mocks struct {
+		provider      *MockOIDCProvider
+		repository    *MockOIDCRepository
+		users         *MockUsersRepository
+		roles         *MockRolesRepository
+		mfa           *MockMFARepository
+		token         *MockTokenGenerator
+		refreshTokens *MockRefreshTokensRepository
+		sessions      *MockSessionsRepository
+	}
+	newUseCase := func(t *testing.T) (*OIDCUseCase, mocks) {
+		m := mocks{
+			provider:      NewMockOIDCProvider(t),
+			repository:    NewMockOIDCRepository(t),
+			users:         NewMockUsersRepository(t),
+			roles:         NewMockRolesRepository(t),
+			mfa:           NewMockMFARepository(t),
+			token:         NewMockTokenGenerator(t),
+			refreshTokens: NewMockRefreshTokensRepository(t),
+			sessions:      NewMockSessionsRepository(t),
+		}
+		m.provider.On("Name").Return("google")
+		auth := NewAuthUseCase(AuthConfig{RefreshTokenExp: time.Hour}, m.roles, m.users, m.refreshTokens, nil, m.mfa, m.token, nil, nil, auditLogger(t), nil, nil, m.sessions)
+		auth.now = func() time.Time { return now }
+		useCase := NewOIDCUseCase(OIDCConfig{StateExp: 10 * time.Minute}, auth, []OIDCProvider{m.provider}, m.repository, m.users, m.roles, auditLogger(t))
+		useCase.now = func() time.Time { return now }
+		return useCase, m
+	}
+
+	// expectTokens expects the user to log in without two-factor
+	// authentication.
+	expectTokens := func(m mocks, userID int64) {
+		m.mfa.On("GetTOTP", mock.Anything, userID).Return(nil, ErrNotFound)
+		m.sessions.On("Create", mock.Anything, mock.Anything).Return(nil)
+		m.token.On("GenerateToken", mock.Anything, userID, mock.Anything).Return("access", nil)
+		m.refreshTokens.On("Create", mock.Anything, mock.Anything, userID, mock.Anything, now.Add(time.Hour)).Return(nil)
+	}
+
+	t.Run("it should start a login and only store the hash of its state", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		var challenge string
+		m.provider.On("AuthCodeURL", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { challenge = args.String(3) }).
+			Return("https://accounts.example.com/authorize", nil)
+		var hash string
+		var saved OIDCLoginState
+		m.repository.On("SaveState", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				hash = args.String(1)
+				saved = args.Get(2).(OIDCLoginState)
+			}).
+			Return(nil)
+
+		authorization, err := useCase.Start(context.Background(), "google")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://accounts.example.com/authorize", authorization.URL)
+		assert.Equal(t, hashToken(authorization.State), hash)
+		assert.Equal(t, "google", saved.Provider)
+		assert.Equal(t, now.Add(10*time.Minute), saved.Expiry)
+		assert.Equal(t, pkceChallenge(saved.CodeVerifier), challenge)
+	})
+
+	t.Run("it should reject unknown providers", func(t *testing.T) {
+		useCase, _ := newUseCase(t)
+
+		_, err := useCase.Start(context.Background(), "myspace")
+		assert.ErrorIs(t, err, ErrUnknownOIDCProvider)
+
+		_, err = useCase.Callback(context.Background(), "myspace", payload)
+		assert.ErrorIs(t, err, ErrUnknownOIDCProvider)
+	})
+
+	t.Run("it should reject unknown or expired states", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.repository.On("TakeState", mock.Anything, hashToken("state")).Return(nil, ErrNotFound)
+
+		_, err := useCase.Callback(context.Background(), "google", payload)
+
+		assert.ErrorIs(t, err, ErrInvalidOIDCState)
+	})
+
+	t.Run("it should reject states of another provider", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.repository.On("TakeState", mock.Anything, hashToken("state")).
+			Return(&OIDCLoginState{Provider: "github", Nonce: "nonce", CodeVerifier: "verifier"}, nil)
+
+		_, err := useCase.Callback(context.Background(), "google", payload)
+
+		assert.ErrorIs(t, err, ErrInvalidOIDCState)
+	})
+
+	t.Run("it should fail when the provider rejects the code", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.repository.On("TakeState", mock.Anything, hashToken("state")).Return(state, nil)
+		m.provider.On("Exchange", mock.Anything, "code", "verifier", "nonce").Return(OIDCIdentity{}, errors.New("invalid_grant"))
+
+		_, err := useCase.Callback(context.Background(), "google", payload)
+
+		assert.ErrorIs(t, err, ErrOIDCLoginFailed)
+	})
+
+	t.Run("it should log in the user of a linked identity", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.repository.On("TakeState", mock.Anything, hashToken("state")).Return(state, nil)
+		m.provider.On("Exchange", mock.Anything, "code", "verifier", "nonce").Return(identity, nil)
+		m.repository.On("GetIdentity", mock.Anything, "google", identity.Subject).
+			Return(&UserIdentity{Provider: "google", Subject: identity.Subject, UserID: 42}, nil)
+		m.users.On("GetByID", mock.Anything, int64(42)).Return(user, nil)
+		expectTokens(m, 42)
+
+		result, err := useCase.Callback(context.Background(), "google", payload)
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.Challenge)
+		assert.Equal(t, "access", result.Tokens.AccessToken)
+	})
+
+	t.Run("it should link the identity to the user with its verified email", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.repository.On("TakeState", mock.Anything, hashToken("state")).Return(state, nil)
+		m.provider.On("Exchange", mock.Anything, "code", "verifier", "nonce").Return(identity, nil)
+		m.repository.On("GetIdentity", mock.Anything, "google", identity.Subject).Return(nil, ErrNotFound)
+		m.users.On("GetByEmail", mock.Anything, "arya@example.com").Return(user, nil)
+		m.repository.On("LinkIdentity", mock.Anything, UserIdentity{
+			Provider: "google",
+			Subject:  identity.Subject,
+			UserID:   42,
+			Email:    "arya@example.com",
+		}).Return(nil)
+		expectTokens(m, 42)
+
+		_, err := useCase.Callback(context.Background(), "google", payload)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("it should not link unverified emails", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.repository.On("TakeState", mock.Anything, hashToken("state")).Return(state, nil)
+		unverified := identity
+		unverified.EmailVerified = false
+		m.provider.On("Exchange", mock.Anything, "code", "verifier", "nonce").Return(unverified, nil)
+		m.repository.On("GetIdentity", mock.Anything, "google", identity.Subject).Return(nil, ErrNotFound)
+
+		_, err := useCase.Callback(context.Background(), "google", payload)
+
+		assert.ErrorIs(t, err, ErrOIDCEmailNotVerified)
+	})
+
+	t.Run("it should create an active user for unknown emails", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.repository.On("TakeState", mock.Anything, hashToken("state")).Return(state, nil)
+		m.provider.On("Exchange", mock.Anything, "code", "verifier", "nonce").Return(identity, nil)
+		m.repository.On("GetIdentity", mock.Anything, "google", identity.Subject).Return(nil, ErrNotFound)
+		m.users.On("GetByEmail", mock.Anything, "arya@example.com").Return(nil, ErrNotFound)
+		m.roles.On("GetByRoleType", mock.Anything, RoleTypeUser).Return(&Role{ID: 1, Name: "user"}, nil)
+		m.repository.On("CreateUser", mock.Anything, mock.MatchedBy(func(u *User) bool {
+			return u.Username == "arya-stark"
+		}), mock.Anything).Return(ErrDuplicateUsername).Once()
+		var created *User
+		m.repository.On("CreateUser", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				created = args.Get(1).(*User)
+				created.ID = 43
+			}).
+			Return(nil).Once()
+		expectTokens(m, 43)
+
+		_, err := useCase.Callback(context.Background(), "google", payload)
+
+		assert.NoError(t, err)
+		assert.True(t, created.IsActive)
+		assert.Equal(t, "arya@example.com", created.Email)
+		assert.Regexp(t, `^arya-stark-[a-z0-9_-]{6}$`, created.Username)
+	})
+
+	t.Run("it should not log in banned users", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.repository.On("TakeState", mock.Anything, hashToken("state")).Return(state, nil)
+		m.provider.On("Exchange", mock.Anything, "code", "verifier", "nonce").Return(identity, nil)
+		m.repository.On("GetIdentity", mock.Anything, "google", identity.Subject).
+			Return(&UserIdentity{Provider: "google", Subject: identity.Subject, UserID: 42}, nil)
+		m.users.On("GetByID", mock.Anything, int64(42)).Return(&User{ID: 42, BannedAt: &now}, nil)
+
+		_, err := useCase.Callback(context.Background(), "google", payload)
+
+		assert.ErrorIs(t, err, ErrUserBanned)
+	})
+
+	t.Run("it should not log in deactivated users", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.repository.On("TakeState", mock.Anything, hashToken("state")).Return(state, nil)
+		m.provider.On("Exchange", mock.Anything, "code", "verifier", "nonce").Return(identity, nil)
+		m.repository.On("GetIdentity", mock.Anything, "google", identity.Subject).
+			Return(&UserIdentity{Provider: "google", Subject: identity.Subject, UserID: 42}, nil)
+		m.users.On("GetByID", mock.Anything, int64(42)).Return(&User{ID: 42}, nil)
+
+		_, err := useCase.Callback(context.Background(), "google", payload)
+
+		assert.ErrorIs(t, err, ErrUserInactive)
+	})
+}
@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// OutboxKindUserInvitation is the kind of the messages delivering the
+// invitation email of a newly registered user.
+const OutboxKindUserInvitation = "user_invitation"
+
+// OutboxKindPasswordReset is the kind of the messages delivering the
+// password reset email.
+const OutboxKindPasswordReset = "password_reset"
+
+// OutboxMessage is a side effect of a domain change, like an email, stored in
+// the same transaction as the change and delivered later by the
+// OutboxProcessor.
+type OutboxMessage struct {
+	ID        int64
+	Kind      string
+	Payload   json.RawMessage
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// NewOutboxMessage creates a message of the given kind with the json
+// encoding of payload.
+func NewOutboxMessage(kind string, payload any) (OutboxMessage, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return OutboxMessage{}, fmt.Errorf("encode %s outbox message: %w", kind, err)
+	}
+	return OutboxMessage{Kind: kind, Payload: data}, nil
+}
+
+type OutboxRepository interface {
+	// Enqueue queues a message that is not part of a domain change.
+	Enqueue(ctx context.Context, msg OutboxMessage) error
+	// Claim leases up to limit pending messages available at now until
+	// leasedUntil, so concurrent workers skip them, and counts the attempt.
+	// Messages of a crashed worker become available again once the lease ends.
+	Claim(ctx context.Context, limit int, now time.Time, leasedUntil time.Time) ([]OutboxMessage, error)
+	MarkSent(ctx context.Context, id int64) error
+	// Retry makes the message available again at the given time.
+	Retry(ctx context.Context, id int64, at time.Time, lastErr string) error
+	// Release makes a claimed message available again at the given time,
+	// without counting the attempt.
+	Release(ctx context.Context, id int64, at time.Time) error
+	// MarkDead stops delivering the message.
+	MarkDead(ctx context.Context, id int64, lastErr string) error
+	CountPending(ctx context.Context) (int64, error)
+}
+
+// OutboxHandler delivers the messages of one kind.
+type OutboxHandler struct {
+	Deliver func(ctx context.Context, payload json.RawMessage) error
+	// Dead is called once a message could not be delivered after all
+	// attempts. Optional.
+	Dead func(ctx context.Context, payload json.RawMessage) error
+}
+
+type OutboxConfig struct {
+	BatchSize   int
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled on every attempt
+	// up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Lease is how long a claimed message is hidden from other workers.
+	Lease time.Duration
+	// DeliveryTimeout bounds every delivery. It has to be shorter than Lease.
+	DeliveryTimeout time.Duration
+}
+
+// OutboxResult counts what happened to the messages of a batch.
+type OutboxResult struct {
+	Sent   int
+	Failed int
+	Dead   int
+}
+
+func (r OutboxResult) Total() int {
+	return r.Sent + r.Failed + r.Dead
+}
+
+type OutboxProcessor struct {
+	config   OutboxConfig
+	repo     OutboxRepository
+	handlers map[string]OutboxHandler
+	now      func() time.Time
+}
+
+func NewOutboxProcessor(config OutboxConfig, repo OutboxRepository, handlers map[string]OutboxHandler) *OutboxProcessor {
+	return &OutboxProcessor{
+		config:   config,
+		repo:     repo,
+		handlers: handlers,
+		now:      time.Now,
+	}
+}
+
+// ProcessBatch delivers one batch of due messages. Failed messages are
+// retried with exponential backoff and dead-lettered after MaxAttempts.
+// Every delivery has to end within the lease of the batch, or another worker
+// could claim the message again and deliver it twice: once the next delivery
+// could outlast the lease, the messages left are released for a next batch.
+func (p *OutboxProcessor) ProcessBatch(ctx context.Context) (OutboxResult, error) {
+	var result OutboxResult
+
+	now := p.now()
+	leasedUntil := now.Add(p.config.Lease)
+	messages, err := p.repo.Claim(ctx, p.config.BatchSize, now, leasedUntil)
+	if err != nil {
+		return result, fmt.Errorf("claim outbox messages: %w", err)
+	}
+
+	var errs []error
+	for i, msg := range messages {
+		if p.now().Add(p.config.DeliveryTimeout).After(leasedUntil) {
+			for _, msg := range messages[i:] {
+				if err := p.repo.Release(ctx, msg.ID, now); err != nil {
+					errs = append(errs, fmt.Errorf("release outbox message %d: %w", msg.ID, err))
+				}
+			}
+			break
+		}
+
+		handler, ok := p.handlers[msg.Kind]
+		if !ok {
+			err = fmt.Errorf("no handler for outbox message kind %q", msg.Kind)
+		} else {
+			err = p.deliver(ctx, handler, msg)
+		}
+
+		switch {
+		case err == nil:
+			if err := p.repo.MarkSent(ctx, msg.ID); err != nil {
+				// Delivered, but sent again once the lease ends.
+				errs = append(errs, fmt.Errorf("mark outbox message %d sent: %w", msg.ID, err))
+				continue
+			}
+			result.Sent++
+
+		case !ok || msg.Attempts >= p.config.MaxAttempts:
+			if err := p.repo.MarkDead(ctx, msg.ID, err.Error()); err != nil {
+				errs = append(errs, fmt.Errorf("mark outbox message %d dead: %w", msg.ID, err))
+			}
+			if ok && handler.Dead != nil {
+				if err := handler.Dead(ctx, msg.Payload); err != nil {
+					errs = append(errs, fmt.Errorf("dead outbox message %d: %w", msg.ID, err))
+				}
+			}
+			result.Dead++
+
+		default:
+			if err := p.repo.Retry(ctx, msg.ID, now.Add(p.backoff(msg.Attempts)), err.Error()); err != nil {
+				errs = append(errs, fmt.Errorf("retry outbox message %d: %w", msg.ID, err))
+			}
+			result.Failed++
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func (p *OutboxProcessor) deliver(ctx context.Context, handler OutboxHandler, msg OutboxMessage) error {
+	ctx, cancel := context.WithTimeout(ctx, p.config.DeliveryTimeout)
+	defer cancel()
+
+	return handler.Deliver(ctx, msg.Payload)
+}
+
+// Pending returns the number of messages not delivered nor dead yet.
+func (p *OutboxProcessor) Pending(ctx context.Context) (int64, error) {
+	return p.repo.CountPending(ctx)
+}
+
+func (p *OutboxProcessor) backoff(attempts int) time.Duration {
+	delay := p.config.Backoff
+	for i := 1; i < attempts && delay < p.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, p.config.MaxBackoff)
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOutboxProcessor(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	config := OutboxConfig{
+		BatchSize:       10,
+		MaxAttempts:     3,
+		Backoff:         time.Second,
+		MaxBackoff:      3 * time.Second,
+		Lease:           time.Minute,
+		DeliveryTimeout: 20 * time.Second,
+	}
+
+	newProcessor := func(repo OutboxRepository, handler OutboxHandler) *OutboxProcessor {
+		processor := NewOutboxProcessor(config, repo, map[string]OutboxHandler{"test": handler})
+		processor.now = func() time.Time { return now }
+		return processor
+	}
+
+	t.Run("it should mark delivered messages as sent", func(t *testing.T) {
+		repo := NewMockOutboxRepository(t)
+		repo.On("Claim", mock.Anything, 10, now, now.Add(time.Minute)).Return([]OutboxMessage{
+			{ID: 1, Kind: "test", Payload: json.RawMessage(`{}`), Attempts: 1},
+		}, nil)
+		repo.On("MarkSent", mock.Anything, int64(1)).Return(nil)
+
+		processor := newProcessor(repo, OutboxHandler{
+			Deliver: func(ctx context.Context, payload json.RawMessage) error { return nil },
+		})
+
+		result, err := processor.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, OutboxResult{Sent: 1}, result)
+	})
+
+	t.Run("it should retry failed messages with exponential backoff", func(t *testing.T) {
+		repo := NewMockOutboxRepository(t)
+		repo.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]OutboxMessage{
+			{ID: 1, Kind: "test", Attempts: 1},
+			{ID: 2, Kind: "test", Attempts: 2},
+		}, nil)
+		repo.On("Retry", mock.Anything, int64(1), now.Add(time.Second), "boom").Return(nil)
+		repo.On("Retry", mock.Anything, int64(2), now.Add(2*time.Second), "boom").Return(nil)
+
+		processor := newProcessor(repo, OutboxHandler{
+			Deliver: func(ctx context.Context, payload json.RawMessage) error { return errors.New("boom") },
+		})
+
+		result, err := processor.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, OutboxResult{Failed: 2}, result)
+	})
+
+	t.Run("it should dead-letter messages after max attempts", func(t *testing.T) {
+		repo := NewMockOutboxRepository(t)
+		repo.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]OutboxMessage{
+			{ID: 1, Kind: "test", Payload: json.RawMessage(`{"id":1}`), Attempts: 3},
+		}, nil)
+		repo.On("MarkDead", mock.Anything, int64(1), "boom").Return(nil)
+
+		var dead json.RawMessage
+		processor := newProcessor(repo, OutboxHandler{
+			Deliver: func(ctx context.Context, payload json.RawMessage) error { return errors.New("boom") },
+			Dead: func(ctx context.Context, payload json.RawMessage) error {
+				dead = payload
+				return nil
+			},
+		})
+
+		result, err := processor.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, OutboxResult{Dead: 1}, result)
+		assert.JSONEq(t, `{"id":1}`, string(dead))
+	})
+
+	t.Run("it should dead-letter messages of unknown kinds", func(t *testing.T) {
+		repo := NewMockOutboxRepository(t)
+		repo.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]OutboxMessage{
+			{ID: 1, Kind: "unknown", Attempts: 1},
+		}, nil)
+		repo.On("MarkDead", mock.Anything, int64(1), `no handler for outbox message kind "unknown"`).Return(nil)
+
+		processor := newProcessor(repo, OutboxHandler{})
+
+		result, err := processor.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, OutboxResult{Dead: 1}, result)
+	})
+
+	t.Run("it should bound every delivery with the delivery timeout", func(t *testing.T) {
+		repo := NewMockOutboxRepository(t)
+		repo.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]OutboxMessage{
+			{ID: 1, Kind: "test", Attempts: 1},
+		}, nil)
+		repo.On("MarkSent", mock.Anything, int64(1)).Return(nil)
+
+		var deadline time.Time
+		processor := newProcessor(repo, OutboxHandler{
+			Deliver: func(ctx context.Context, payload json.RawMessage) error {
+				deadline, _ = ctx.Deadline()
+				return nil
+			},
+		})
+
+		_, err := processor.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(20*time.Second), deadline, time.Second)
+	})
+
+	t.Run("it should release the messages left once a delivery could outlast the lease", func(t *testing.T) {
+		repo := NewMockOutboxRepository(t)
+		repo.On("Claim", mock.Anything, 10, now, now.Add(time.Minute)).Return([]OutboxMessage{
+			{ID: 1, Kind: "test", Attempts: 1},
+			{ID: 2, Kind: "test", Attempts: 1},
+			{ID: 3, Kind: "test", Attempts: 1},
+		}, nil)
+		repo.On("MarkSent", mock.Anything, int64(1)).Return(nil)
+		repo.On("Release", mock.Anything, int64(2), now).Return(nil)
+		repo.On("Release", mock.Anything, int64(3), now).Return(nil)
+
+		clock := now
+		processor := newProcessor(repo, OutboxHandler{
+			Deliver: func(ctx context.Context, payload json.RawMessage) error {
+				clock = clock.Add(45 * time.Second)
+				return nil
+			},
+		})
+		processor.now = func() time.Time { return clock }
+
+		result, err := processor.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, OutboxResult{Sent: 1}, result)
+	})
+
+	t.Run("it should not count a message as sent until it is marked sent", func(t *testing.T) {
+		repo := NewMockOutboxRepository(t)
+		repo.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]OutboxMessage{
+			{ID: 1, Kind: "test", Attempts: 1},
+		}, nil)
+		repo.On("MarkSent", mock.Anything, int64(1)).Return(errors.New("boom"))
+
+		processor := newProcessor(repo, OutboxHandler{
+			Deliver: func(ctx context.Context, payload json.RawMessage) error { return nil },
+		})
+
+		result, err := processor.ProcessBatch(context.Background())
+
+		assert.EqualError(t, err, "mark outbox message 1 sent: boom")
+		assert.Equal(t, OutboxResult{}, result)
+	})
+
+	t.Run("it should cap the backoff", func(t *testing.T) {
+		processor := newProcessor(nil, OutboxHandler{})
+
+		assert.Equal(t, time.Second, processor.backoff(1))
+		assert.Equal(t, 2*time.Second, processor.backoff(2))
+		assert.Equal(t, 3*time.Second, processor.backoff(3))
+		assert.Equal(t, 3*time.Second, processor.backoff(10))
+	})
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// Cursor is the position of the last item of a keyset paginated page. Items
+// are ordered by creation time with the id as a tie-breaker.
+type Cursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+}
@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// Permission is an action a role can be granted, named resource:action.
+// Actions on resources owned by users are granted with a scope suffix:
+// ":own" for the resources of the user and ":any" for every resource.
+type Permission string
+
+const (
+	PermissionPostsCreate    Permission = "posts:create"
+	PermissionPostsUpdate    Permission = "posts:update"
+	PermissionPostsDelete    Permission = "posts:delete"
+	PermissionCommentsCreate Permission = "comments:create"
+	PermissionCommentsUpdate Permission = "comments:update"
+	PermissionCommentsDelete Permission = "comments:delete"
+	PermissionUsersRead      Permission = "users:read"
+	PermissionUsersUpdate    Permission = "users:update"
+	PermissionUsersBan       Permission = "users:ban"
+	PermissionUsersDelete    Permission = "users:delete"
+	PermissionAuditRead      Permission = "audit:read"
+	PermissionReactionsWrite Permission = "reactions:write"
+	PermissionFollowsWrite   Permission = "follows:write"
+)
+
+// Own returns the permission scoped to the resources of the user.
+func (p Permission) Own() Permission {
+	return p + ":own"
+}
+
+// Any returns the permission scoped to every resource.
+func (p Permission) Any() Permission {
+	return p + ":any"
+}
+
+// Resource is the resource a permission is checked against.
+type Resource struct {
+	OwnerID int64
+}
+
+// permissionsTTL is how long the permissions of a role are cached. They only
+// change with migrations, the cache keeps them out of every request.
+const permissionsTTL = time.Minute
+
+type cachedPermissions struct {
+	permissions map[Permission]struct{}
+	expiry      time.Time
+}
+
+// Authorizer answers whether a user can perform an action, from the
+// permissions granted to their role and their ownership of the resource.
+type Authorizer struct {
+	users *UsersUseCase
+	roles RolesRepository
+	now   func() time.Time
+
+	mu    sync.Mutex
+	cache map[int64]cachedPermissions
+}
+
+func NewAuthorizer(users *UsersUseCase, roles RolesRepository) *Authorizer {
+	return &Authorizer{
+		users: users,
+		roles: roles,
+		now:   time.Now,
+		cache: make(map[int64]cachedPermissions),
+	}
+}
+
+// Can reports whether the user can perform the action. The resource is nil
+// for actions not on a resource owned by a user, like creating a post.
+func (az *Authorizer) Can(ctx context.Context, userID int64, permission Permission, resource *Resource) (bool, error) {
+	user, err := az.users.GetUserById(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	granted, err := az.permissions(ctx, user.RoleID)
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := granted[permission]; ok {
+		return true, nil
+	}
+	if resource == nil {
+		return false, nil
+	}
+	if _, ok := granted[permission.Any()]; ok {
+		return true, nil
+	}
+	if _, ok := granted[permission.Own()]; ok && resource.OwnerID == userID {
+		return true, nil
+	}
+	return false, nil
+}
+
+// Authorize returns ErrForbidden when the user cannot perform the action.
+func (az *Authorizer) Authorize(ctx context.Context, userID int64, permission Permission, resource *Resource) error {
+	ok, err := az.Can(ctx, userID, permission, resource)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (az *Authorizer) permissions(ctx context.Context, roleID int64) (map[Permission]struct{}, error) {
+	az.mu.Lock()
+	cached, ok := az.cache[roleID]
+	az.mu.Unlock()
+	if ok && az.now().Before(cached.expiry) {
+		return cached.permissions, nil
+	}
+
+	list, err := az.roles.GetPermissions(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make(map[Permission]struct{}, len(list))
+	for _, permission := range list {
+		permissions[permission] = struct{}{}
+	}
+
+	az.mu.Lock()
+	az.cache[roleID] = cachedPermissions{permissions: permissions, expiry: az.now().Add(permissionsTTL)}
+	az.mu.Unlock()
+
+	return permissions, nil
+}
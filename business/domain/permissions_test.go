@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthorizer_Can(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	user := &User{ID: 42, RoleID: 1}
+	granted := []Permission{
+		PermissionPostsCreate,
+		PermissionPostsUpdate.Own(),
+		PermissionCommentsDelete.Any(),
+	}
+
+	newAuthorizer := func(t *testing.T) (*Authorizer, *MockRolesRepository) {
+		cache := NewMockUsersCache(t)
+		cache.On("Get", mock.Anything, int64(42)).Return(user, nil).Maybe()
+		roles := NewMockRolesRepository(t)
+		az := NewAuthorizer(NewUsersUseCase(cache, nil, nil, nil), roles)
+		az.now = func() time.Time { return now }
+		return az, roles
+	}
+
+	tests := []struct {
+		name       string
+		permission Permission
+		resource   *Resource
+		want       bool
+	}{
+		{"it should allow a granted permission", PermissionPostsCreate, nil, true},
+		{"it should deny a permission not granted", PermissionUsersBan, nil, false},
+		{"it should allow an own permission on a resource of the user", PermissionPostsUpdate, &Resource{OwnerID: 42}, true},
+		{"it should deny an own permission on a resource of another user", PermissionPostsUpdate, &Resource{OwnerID: 7}, false},
+		{"it should deny a scoped permission without a resource", PermissionPostsUpdate, nil, false},
+		{"it should allow an any permission on a resource of another user", PermissionCommentsDelete, &Resource{OwnerID: 7}, true},
+		{"it should deny a permission granted for another action", PermissionPostsDelete, &Resource{OwnerID: 42}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			az, roles := newAuthorizer(t)
+			roles.On("GetPermissions", mock.Anything, int64(1)).Return(granted, nil)
+
+			ok, err := az.Can(context.Background(), 42, tt.permission, tt.resource)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+
+	t.Run("it should cache the permissions of a role", func(t *testing.T) {
+		az, roles := newAuthorizer(t)
+		roles.On("GetPermissions", mock.Anything, int64(1)).Return(granted, nil).Twice()
+
+		for range 2 {
+			ok, err := az.Can(context.Background(), 42, PermissionPostsCreate, nil)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		}
+
+		now = now.Add(permissionsTTL)
+		ok, err := az.Can(context.Background(), 42, PermissionPostsCreate, nil)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		roles.AssertNumberOfCalls(t, "GetPermissions", 2)
+	})
+
+	t.Run("it should return ErrForbidden when the user cannot perform the action", func(t *testing.T) {
+		az, roles := newAuthorizer(t)
+		roles.On("GetPermissions", mock.Anything, int64(1)).Return(granted, nil)
+
+		err := az.Authorize(context.Background(), 42, PermissionUsersBan, nil)
+
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+}
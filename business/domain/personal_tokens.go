@@ -0,0 +1,193 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidPersonalToken = errors.New("invalid personal access token")
+var ErrInvalidScope = errors.New("invalid scope")
+var ErrInvalidTokenExpiry = errors.New("invalid token expiry")
+
+const (
+	// PersonalTokenPrefix starts every personal access token, telling them
+	// apart from JWTs and making leaked ones easy to scan for.
+	PersonalTokenPrefix = "social_pat_"
+
+	// MaxPersonalTokenLifetime is how far in the future a personal access
+	// token can expire.
+	MaxPersonalTokenLifetime = 365 * 24 * time.Hour
+
+	// personalTokenTouchInterval is how often the last use of a token is
+	// stored, so not every request writes it.
+	personalTokenTouchInterval = time.Minute
+
+	// personalTokenDisplayLength is how much of a token is kept in clear to
+	// recognize it in the list.
+	personalTokenDisplayLength = len(PersonalTokenPrefix) + 4
+)
+
+// personalTokenScopes are the permissions a personal access token can be
+// scoped to.
+var personalTokenScopes = map[Permission]struct{}{
+	PermissionPostsCreate:    {},
+	PermissionPostsUpdate:    {},
+	PermissionPostsDelete:    {},
+	PermissionCommentsCreate: {},
+	PermissionCommentsUpdate: {},
+	PermissionCommentsDelete: {},
+	PermissionUsersRead:      {},
+	PermissionUsersUpdate:    {},
+	PermissionUsersBan:       {},
+	PermissionUsersDelete:    {},
+	PermissionAuditRead:      {},
+	PermissionReactionsWrite: {},
+	PermissionFollowsWrite:   {},
+}
+
+// PersonalToken is a personal access token, used by scripts and bots in
+// place of a JWT. Only its hash is stored, the token itself is shown once on
+// creation. It grants the permissions of its user within its scopes.
+type PersonalToken struct {
+	ID     int64        `json:"id"`
+	UserID int64        `json:"-"`
+	Name   string       `json:"name"`
+	Prefix string       `json:"prefix"`
+	Scopes []Permission `json:"scopes"`
+	// ExpiresAt is required, tokens do not live forever.
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewPersonalToken is a token as created, with the token itself.
+type NewPersonalToken struct {
+	PersonalToken
+	Token string `json:"token"`
+}
+
+type CreatePersonalTokenPayload struct {
+	Name      string       `json:"name" validate:"required,max=100"`
+	Scopes    []Permission `json:"scopes" validate:"min=1,max=32,dive,required"`
+	ExpiresAt time.Time    `json:"expires_at" validate:"required"`
+}
+
+type PersonalTokensRepository interface {
+	// Create stores the token by the hash of its value and sets its ID and
+	// CreatedAt.
+	Create(ctx context.Context, token *PersonalToken, hash string) error
+	ListByUser(ctx context.Context, userID int64) ([]PersonalToken, error)
+	GetByToken(ctx context.Context, hash string) (*PersonalToken, error)
+	Touch(ctx context.Context, id int64, at time.Time) error
+	// Delete returns ErrNotFound when the user has no such token.
+	Delete(ctx context.Context, userID int64, id int64) error
+}
+
+// CreatePersonalToken creates a token for the user. The returned token is
+// the only time its value is available.
+func (auth *AuthUseCase) CreatePersonalToken(ctx context.Context, userID int64, payload CreatePersonalTokenPayload) (*NewPersonalToken, error) {
+	for _, scope := range payload.Scopes {
+		if _, ok := personalTokenScopes[scope]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+	now := auth.now()
+	if !payload.ExpiresAt.After(now) || payload.ExpiresAt.After(now.Add(MaxPersonalTokenLifetime)) {
+		return nil, ErrInvalidTokenExpiry
+	}
+
+	value, err := newPersonalToken()
+	if err != nil {
+		return nil, err
+	}
+
+	token := PersonalToken{
+		UserID:    userID,
+		Name:      payload.Name,
+		Prefix:    value[:personalTokenDisplayLength],
+		Scopes:    payload.Scopes,
+		ExpiresAt: payload.ExpiresAt,
+	}
+	if token.Scopes == nil {
+		token.Scopes = []Permission{}
+	}
+	if err := auth.personalTokens.Create(ctx, &token, hashToken(value)); err != nil {
+		return nil, err
+	}
+
+	auth.personalTokenChanged(ctx, token, AuditPersonalTokenCreated)
+	return &NewPersonalToken{PersonalToken: token, Token: value}, nil
+}
+
+func (auth *AuthUseCase) ListPersonalTokens(ctx context.Context, userID int64) ([]PersonalToken, error) {
+	return auth.personalTokens.ListByUser(ctx, userID)
+}
+
+// RevokePersonalToken deletes a token of the user. It returns ErrNotFound
+// when the user has no such token.
+func (auth *AuthUseCase) RevokePersonalToken(ctx context.Context, userID int64, id int64) error {
+	if err := auth.personalTokens.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+
+	auth.personalTokenChanged(ctx, PersonalToken{ID: id, UserID: userID}, AuditPersonalTokenRevoked)
+	return nil
+}
+
+func (auth *AuthUseCase) personalTokenChanged(ctx context.Context, token PersonalToken, action string) {
+	metadata := map[string]any{"token_id": token.ID}
+	if token.Name != "" {
+		metadata["name"] = token.Name
+	}
+	auth.audit.Record(ctx, AuditEvent{
+		ActorID:    token.UserID,
+		Action:     action,
+		TargetType: AuditTargetUser,
+		TargetID:   token.UserID,
+		Metadata:   metadata,
+	})
+}
+
+// validatePersonalToken returns the claims of a personal access token and
+// records its use.
+func (auth *AuthUseCase) validatePersonalToken(ctx context.Context, value string) (Claims, error) {
+	token, err := auth.personalTokens.GetByToken(ctx, hashToken(value))
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Claims{}, ErrInvalidPersonalToken
+		}
+		return Claims{}, err
+	}
+
+	now := auth.now()
+	if !now.Before(token.ExpiresAt) {
+		return Claims{}, ErrInvalidPersonalToken
+	}
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= personalTokenTouchInterval {
+		if err := auth.personalTokens.Touch(ctx, token.ID, now); err != nil {
+			return Claims{}, err
+		}
+	}
+
+	return Claims{
+		UserID:          token.UserID,
+		ExpiresAt:       token.ExpiresAt,
+		PersonalTokenID: token.ID,
+		Scopes:          token.Scopes,
+	}, nil
+}
+
+// newPersonalToken returns PersonalTokenPrefix followed by 32 random bytes.
+func newPersonalToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)
+	return PersonalTokenPrefix + strings.ToLower(encoded), nil
+}
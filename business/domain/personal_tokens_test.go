@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthUseCase_PersonalTokens(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+
+	newUseCase := func(t *testing.T) (*AuthUseCase, *MockPersonalTokensRepository) {
+		tokens := NewMockPersonalTokensRepository(t)
+		useCase := NewAuthUseCase(AuthConfig{}, nil, nil, nil, nil, nil, nil, nil, nil, auditLogger(t), nil, tokens, nil)
+		useCase.now = func() time.Time { return now }
+		return useCase, tokens
+	}
+
+	t.Run("it should create a token and only store its hash", func(t *testing.T) {
+		useCase, tokens := newUseCase(t)
+		var hash string
+		tokens.On("Create", mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				args.Get(1).(*PersonalToken).ID = 7
+				hash = args.String(2)
+			}).
+			Return(nil)
+
+		token, err := useCase.CreatePersonalToken(context.Background(), 42, CreatePersonalTokenPayload{
+			Name:      "deploy bot",
+			Scopes:    []Permission{PermissionPostsCreate},
+			ExpiresAt: now.Add(30 * 24 * time.Hour),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), token.ID)
+		assert.True(t, strings.HasPrefix(token.Token, PersonalTokenPrefix))
+		assert.True(t, strings.HasPrefix(token.Token, token.Prefix))
+		assert.Equal(t, hashToken(token.Token), hash)
+	})
+
+	t.Run("it should reject unknown scopes", func(t *testing.T) {
+		useCase, _ := newUseCase(t)
+
+		_, err := useCase.CreatePersonalToken(context.Background(), 42, CreatePersonalTokenPayload{
+			Name:      "deploy bot",
+			Scopes:    []Permission{"posts:create:any"},
+			ExpiresAt: now.Add(time.Hour),
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidScope)
+	})
+
+	t.Run("it should reject expiries in the past or too far away", func(t *testing.T) {
+		useCase, _ := newUseCase(t)
+
+		for _, expiresAt := range []time.Time{now, now.Add(MaxPersonalTokenLifetime + time.Hour)} {
+			_, err := useCase.CreatePersonalToken(context.Background(), 42, CreatePersonalTokenPayload{
+				Name:      "deploy bot",
+				ExpiresAt: expiresAt,
+			})
+
+			assert.ErrorIs(t, err, ErrInvalidTokenExpiry)
+		}
+	})
+
+	t.Run("it should validate personal tokens and record their use", func(t *testing.T) {
+		useCase, tokens := newUseCase(t)
+		tokens.On("GetByToken", mock.Anything, hashToken(PersonalTokenPrefix+"secret")).Return(&PersonalToken{
+			ID:        7,
+			UserID:    42,
+			Scopes:    []Permission{PermissionPostsCreate},
+			ExpiresAt: now.Add(time.Hour),
+		}, nil)
+		tokens.On("Touch", mock.Anything, int64(7), now).Return(nil)
+
+		claims, err := useCase.ValidateToken(context.Background(), PersonalTokenPrefix+"secret")
+
+		assert.NoError(t, err)
+		assert.Equal(t, Claims{
+			UserID:          42,
+			ExpiresAt:       now.Add(time.Hour),
+			PersonalTokenID: 7,
+			Scopes:          []Permission{PermissionPostsCreate},
+		}, claims)
+	})
+
+	t.Run("it should not record every use", func(t *testing.T) {
+		useCase, tokens := newUseCase(t)
+		lastUsedAt := now.Add(-10 * time.Second)
+		tokens.On("GetByToken", mock.Anything, mock.Anything).Return(&PersonalToken{
+			ID:         7,
+			UserID:     42,
+			ExpiresAt:  now.Add(time.Hour),
+			LastUsedAt: &lastUsedAt,
+		}, nil)
+
+		_, err := useCase.ValidateToken(context.Background(), PersonalTokenPrefix+"secret")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("it should reject expired and unknown tokens", func(t *testing.T) {
+		useCase, tokens := newUseCase(t)
+		tokens.On("GetByToken", mock.Anything, hashToken(PersonalTokenPrefix+"expired")).Return(&PersonalToken{
+			ID:        7,
+			ExpiresAt: now,
+		}, nil)
+		tokens.On("GetByToken", mock.Anything, hashToken(PersonalTokenPrefix+"unknown")).Return(nil, ErrNotFound)
+
+		_, err := useCase.ValidateToken(context.Background(), PersonalTokenPrefix+"expired")
+		assert.ErrorIs(t, err, ErrInvalidPersonalToken)
+
+		_, err = useCase.ValidateToken(context.Background(), PersonalTokenPrefix+"unknown")
+		assert.ErrorIs(t, err, ErrInvalidPersonalToken)
+	})
+
+	t.Run("it should only revoke tokens of the user", func(t *testing.T) {
+		useCase, tokens := newUseCase(t)
+		tokens.On("Delete", mock.Anything, int64(42), int64(7)).Return(ErrNotFound)
+
+		err := useCase.RevokePersonalToken(context.Background(), 42, 7)
+
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestCreatePersonalTokenPayload_Validate(t *testing.T) {
+	expiresAt := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		scopes []Permission
+		valid  bool
+	}{
+		{name: "it should accept a scoped token", scopes: []Permission{PermissionReactionsWrite}, valid: true},
+		{name: "it should reject a token without scopes", scopes: nil},
+		{name: "it should reject an empty scope", scopes: []Permission{PermissionPostsCreate, ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate.Struct(CreatePersonalTokenPayload{Name: "bot", Scopes: tt.scopes, ExpiresAt: expiresAt})
+
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -17,12 +17,16 @@ type Post struct {
 
 type PostWithMetadata struct {
 	Post
-	CommentsCount int64 `json:"comments_count"`
+	CommentsCount int64     `json:"comments_count"`
+	Reactions     Reactions `json:"reactions"`
 }
 
 type PostsRepository interface {
 	Create(ctx context.Context, post *Post) error
 	GetByID(ctx context.Context, id int64) (*Post, error)
 	Delete(ctx context.Context, id int64) error
+	// Update saves the post when its version is still the one it was read
+	// with, returns ErrEditConflict when it moved on and ErrNotFound when the
+	// post was deleted.
 	Update(ctx context.Context, post *Post) error
 }
@@ -0,0 +1,43 @@
+package domain
+
+import "context"
+
+type ReactionType string
+
+// Allowed values for ReactionType
+const (
+	ReactionTypeLike  ReactionType = "like"
+	ReactionTypeLove  ReactionType = "love"
+	ReactionTypeLaugh ReactionType = "laugh"
+	ReactionTypeWow   ReactionType = "wow"
+	ReactionTypeSad   ReactionType = "sad"
+	ReactionTypeAngry ReactionType = "angry"
+)
+
+// IsValid checks if the reaction is one of the allowed values
+func (r ReactionType) IsValid() bool {
+	switch r {
+	case ReactionTypeLike, ReactionTypeLove, ReactionTypeLaugh, ReactionTypeWow, ReactionTypeSad, ReactionTypeAngry:
+		return true
+	default:
+		return false
+	}
+}
+
+// Reactions aggregates the reactions on a post or a comment as seen by the
+// authenticated user, Mine being the reactions they left.
+type Reactions struct {
+	Total       int64                  `json:"total"`
+	Counts      map[ReactionType]int64 `json:"counts"`
+	ReactedByMe bool                   `json:"reacted_by_me"`
+	Mine        []ReactionType         `json:"mine"`
+}
+
+// ReactionsRepository stores the reactions of users. Adding a reaction the
+// user already left is a no-op, removing a missing one returns ErrNotFound.
+type ReactionsRepository interface {
+	AddToPost(ctx context.Context, postID int64, userID int64, reaction ReactionType) error
+	RemoveFromPost(ctx context.Context, postID int64, userID int64, reaction ReactionType) error
+	AddToComment(ctx context.Context, commentID int64, userID int64, reaction ReactionType) error
+	RemoveFromComment(ctx context.Context, commentID int64, userID int64, reaction ReactionType) error
+}
@@ -20,6 +20,9 @@ const (
 
 type RolesRepository interface {
 	GetByRoleType(ctx context.Context, name RoleType) (*Role, error)
+	// List returns the roles ordered by level.
+	List(ctx context.Context) ([]Role, error)
+	GetPermissions(ctx context.Context, roleID int64) ([]Permission, error)
 }
 
 // IsValid checks if the role is one of the allowed values
@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrSessionRevoked = errors.New("session revoked")
+
+// sessionTouchInterval is how often the last use of a session is stored, so
+// not every request writes it.
+const sessionTouchInterval = time.Minute
+
+// Session is a login of a user on a device, from the login to the expiry of
+// its refresh tokens. It is the refresh token family: its ID is the FamilyID
+// of the tokens and the sid claim of the access tokens issued with them, so
+// revoking it logs the device out at once.
+type Session struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     int64      `json:"-"`
+	Device     string     `json:"device"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"-"`
+	// Current is set for the session of the request.
+	Current bool `json:"current"`
+}
+
+type SessionsRepository interface {
+	Create(ctx context.Context, session *Session) error
+	Get(ctx context.Context, id uuid.UUID) (*Session, error)
+	// ListByUser returns the sessions of the user that are neither revoked
+	// nor expired, the last used first.
+	ListByUser(ctx context.Context, userID int64) ([]Session, error)
+	Touch(ctx context.Context, id uuid.UUID, at time.Time, ip string) error
+	// Revoke revokes the session of the user and deletes its refresh
+	// tokens. It returns ErrNotFound when the user has no such session.
+	Revoke(ctx context.Context, userID int64, id uuid.UUID) error
+	// RevokeOthers revokes the sessions of the user but keep, and deletes
+	// their refresh tokens. It returns how many were revoked.
+	RevokeOthers(ctx context.Context, userID int64, keep uuid.UUID) (int64, error)
+}
+
+// ListSessions lists the sessions of the user, marking the one of the claims
+// as current.
+func (auth *AuthUseCase) ListSessions(ctx context.Context, claims Claims) ([]Session, error) {
+	sessions, err := auth.sessions.ListByUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Device = deviceName(sessions[i].UserAgent)
+		sessions[i].Current = sessions[i].ID == claims.SessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession logs the user out of a session. It returns ErrNotFound when
+// the user has no such session.
+func (auth *AuthUseCase) RevokeSession(ctx context.Context, userID int64, id uuid.UUID) error {
+	if err := auth.sessions.Revoke(ctx, userID, id); err != nil {
+		return err
+	}
+
+	auth.audit.Record(ctx, AuditEvent{
+		ActorID:    userID,
+		Action:     AuditSessionRevoked,
+		TargetType: AuditTargetUser,
+		TargetID:   userID,
+		Metadata:   map[string]any{"session_id": id.String()},
+	})
+	return nil
+}
+
+// RevokeOtherSessions logs the user out of every session but the one of the
+// claims.
+func (auth *AuthUseCase) RevokeOtherSessions(ctx context.Context, claims Claims) error {
+	revoked, err := auth.sessions.RevokeOthers(ctx, claims.UserID, claims.SessionID)
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return nil
+	}
+
+	auth.audit.Record(ctx, AuditEvent{
+		ActorID:    claims.UserID,
+		Action:     AuditSessionRevoked,
+		TargetType: AuditTargetUser,
+		TargetID:   claims.UserID,
+		Metadata:   map[string]any{"kept_session_id": claims.SessionID.String(), "revoked": revoked},
+	})
+	return nil
+}
+
+// startSession starts the session of a login.
+func (auth *AuthUseCase) startSession(ctx context.Context, userID int64, expiry time.Time) (uuid.UUID, error) {
+	client := GetClientInfo(ctx)
+	now := auth.now()
+	session := Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  expiry,
+	}
+	if err := auth.sessions.Create(ctx, &session); err != nil {
+		return uuid.Nil, err
+	}
+	return session.ID, nil
+}
+
+// validateSession checks the session of an access token is still live and
+// records its use.
+func (auth *AuthUseCase) validateSession(ctx context.Context, id uuid.UUID) error {
+	session, err := auth.sessions.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrSessionRevoked
+		}
+		return err
+	}
+
+	now := auth.now()
+	if session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+		return ErrSessionRevoked
+	}
+
+	ip := GetClientInfo(ctx).IP
+	if now.Sub(session.LastUsedAt) >= sessionTouchInterval || (ip != "" && ip != session.IP) {
+		if err := auth.sessions.Touch(ctx, id, now, ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// devicePlatforms and deviceBrowsers are matched in order against user
+// agents, the more specific tokens first: Android agents also claim Linux,
+// iPhones macOS, Chrome Safari and Edge Chrome.
+var (
+	devicePlatforms = []struct{ token, name string }{
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Android", "Android"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+	deviceBrowsers = []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	}
+)
+
+// deviceName describes the device of a user agent, like "Firefox on
+// Windows", or names the client of the agents of other programs.
+func deviceName(userAgent string) string {
+	var platform, browser string
+	for _, p := range devicePlatforms {
+		if strings.Contains(userAgent, p.token) {
+			platform = p.name
+			break
+		}
+	}
+	for _, b := range deviceBrowsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	}
+
+	// Other clients like curl/8.5.0 are named by their product.
+	product, _, _ := strings.Cut(userAgent, "/")
+	if product = strings.TrimSpace(product); product == "" {
+		return "Unknown device"
+	}
+	return product
+}
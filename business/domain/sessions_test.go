@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthUseCase_Sessions(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	sessionID := uuid.New()
+
+	type mocks struct {
+		sessions      *MockSessionsRepository
+		refreshTokens *MockRefreshTokensRepository
+		revokedTokens *MockRevokedTokensRepository
+		token         *MockTokenGenerator
+		tokenValid    *MockTokenValidator
+		audit         *MockAuditLogger
+	}
+	newUseCase := func(t *testing.T) (*AuthUseCase, mocks) {
+		m := mocks{
+			sessions:      NewMockSessionsRepository(t),
+			refreshTokens: NewMockRefreshTokensRepository(t),
+			revokedTokens: NewMockRevokedTokensRepository(t),
+			token:         NewMockTokenGenerator(t),
+			tokenValid:    NewMockTokenValidator(t),
+			audit:         NewMockAuditLogger(t),
+		}
+		useCase := NewAuthUseCase(AuthConfig{RefreshTokenExp: time.Hour}, nil, nil, m.refreshTokens, m.revokedTokens, nil, m.token, m.tokenValid, nil, m.audit, nil, nil, m.sessions)
+		useCase.now = func() time.Time { return now }
+		return useCase, m
+	}
+	// validToken makes "token" a valid access token of the session.
+	validToken := func(m mocks) {
+		m.tokenValid.On("ValidateToken", mock.Anything, "token").Return(Claims{UserID: 42, ID: "jti", SessionID: sessionID}, nil)
+		m.revokedTokens.On("IsRevoked", mock.Anything, "jti").Return(false, nil)
+	}
+
+	t.Run("it should start a session for the tokens of a login", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		ctx := WithClientInfo(context.Background(), ClientInfo{IP: "10.0.0.1", UserAgent: "curl/8.5.0"})
+		var session *Session
+		m.sessions.On("Create", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { session = args.Get(1).(*Session) }).
+			Return(nil)
+		m.token.On("GenerateToken", mock.Anything, int64(42), mock.Anything).Return("access", nil)
+		m.refreshTokens.On("Create", mock.Anything, mock.Anything, int64(42), mock.Anything, now.Add(time.Hour)).Return(nil)
+
+		_, err := useCase.issueTokens(ctx, 42)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "10.0.0.1", session.IP)
+		assert.Equal(t, "curl/8.5.0", session.UserAgent)
+		assert.Equal(t, now.Add(time.Hour), session.ExpiresAt)
+		m.token.AssertCalled(t, "GenerateToken", mock.Anything, int64(42), session.ID)
+		m.refreshTokens.AssertCalled(t, "Create", mock.Anything, mock.Anything, int64(42), session.ID, now.Add(time.Hour))
+	})
+
+	t.Run("it should reject access tokens of revoked or expired sessions", func(t *testing.T) {
+		for _, session := range []*Session{
+			{ID: sessionID, ExpiresAt: now.Add(time.Hour), RevokedAt: &now},
+			{ID: sessionID, ExpiresAt: now},
+		} {
+			useCase, m := newUseCase(t)
+			validToken(m)
+			m.sessions.On("Get", mock.Anything, sessionID).Return(session, nil)
+
+			_, err := useCase.ValidateToken(context.Background(), "token")
+
+			assert.ErrorIs(t, err, ErrSessionRevoked)
+		}
+	})
+
+	t.Run("it should record the use of a session at most once a minute", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		ctx := WithClientInfo(context.Background(), ClientInfo{IP: "10.0.0.1"})
+		validToken(m)
+		m.sessions.On("Get", mock.Anything, sessionID).Return(&Session{
+			ID:         sessionID,
+			IP:         "10.0.0.1",
+			LastUsedAt: now.Add(-30 * time.Second),
+			ExpiresAt:  now.Add(time.Hour),
+		}, nil).Once()
+
+		claims, err := useCase.ValidateToken(ctx, "token")
+		assert.NoError(t, err)
+		assert.Equal(t, sessionID, claims.SessionID)
+
+		m.sessions.On("Get", mock.Anything, sessionID).Return(&Session{
+			ID:         sessionID,
+			IP:         "10.0.0.1",
+			LastUsedAt: now.Add(-time.Minute),
+			ExpiresAt:  now.Add(time.Hour),
+		}, nil).Once()
+		m.sessions.On("Touch", mock.Anything, sessionID, now, "10.0.0.1").Return(nil).Once()
+
+		_, err = useCase.ValidateToken(ctx, "token")
+		assert.NoError(t, err)
+	})
+
+	t.Run("it should list the sessions and mark the current one", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		other := uuid.New()
+		m.sessions.On("ListByUser", mock.Anything, int64(42)).Return([]Session{
+			{ID: sessionID, UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15"},
+			{ID: other},
+		}, nil)
+
+		sessions, err := useCase.ListSessions(context.Background(), Claims{UserID: 42, SessionID: sessionID})
+
+		assert.NoError(t, err)
+		assert.True(t, sessions[0].Current)
+		assert.Equal(t, "Safari on macOS", sessions[0].Device)
+		assert.False(t, sessions[1].Current)
+		assert.Equal(t, "Unknown device", sessions[1].Device)
+	})
+
+	t.Run("it should revoke a session and record it", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.sessions.On("Revoke", mock.Anything, int64(42), sessionID).Return(nil)
+		m.audit.On("Record", mock.Anything, AuditEvent{
+			ActorID:    42,
+			Action:     AuditSessionRevoked,
+			TargetType: AuditTargetUser,
+			TargetID:   42,
+			Metadata:   map[string]any{"session_id": sessionID.String()},
+		})
+
+		assert.NoError(t, useCase.RevokeSession(context.Background(), 42, sessionID))
+	})
+
+	t.Run("it should not record revoking no other session", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		m.sessions.On("RevokeOthers", mock.Anything, int64(42), sessionID).Return(int64(0), nil)
+
+		assert.NoError(t, useCase.RevokeOtherSessions(context.Background(), Claims{UserID: 42, SessionID: sessionID}))
+	})
+
+	t.Run("it should revoke the session on logout", func(t *testing.T) {
+		useCase, m := newUseCase(t)
+		claims := Claims{UserID: 42, ID: "jti", ExpiresAt: now.Add(time.Minute), SessionID: sessionID}
+		m.revokedTokens.On("Revoke", mock.Anything, "jti", claims.ExpiresAt).Return(nil)
+		m.sessions.On("Revoke", mock.Anything, int64(42), sessionID).Return(nil)
+
+		assert.NoError(t, useCase.Logout(context.Background(), claims, ""))
+	})
+}
+
+func TestDeviceName(t *testing.T) {
+	tests := map[string]string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0":           "Edge on Windows",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36":                   "Chrome on Android",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1": "Safari on iPhone",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0":                                                                  "Firefox on Linux",
+		"curl/8.5.0": "curl",
+		"":           "Unknown device",
+	}
+	for userAgent, want := range tests {
+		assert.Equal(t, want, deviceName(userAgent), userAgent)
+	}
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+var ErrRefreshTokenReused = errors.New("refresh token reused")
+var ErrTokenRevoked = errors.New("token revoked")
+
+// AuthTokens is the pair of tokens issued on login and on refresh. The access
+// token is short-lived; the refresh token is exchanged for a new pair.
+type AuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// RefreshToken is a stored refresh token. Tokens issued by rotating one
+// another share a FamilyID, so a reused token can revoke the whole chain. The
+// family is the Session of the login.
+type RefreshToken struct {
+	ID        int64
+	UserID    int64
+	FamilyID  uuid.UUID
+	Expiry    time.Time
+	RevokedAt *time.Time
+}
+
+type RefreshTokenPayload struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type LogoutPayload struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshTokensRepository interface {
+	Create(ctx context.Context, token string, userID int64, familyID uuid.UUID, expiry time.Time) error
+	GetByToken(ctx context.Context, token string) (*RefreshToken, error)
+	// Rotate revokes the old token and stores its replacement in the same
+	// family, extending its session to the new expiry. It returns
+	// ErrNotFound when the old token was already revoked.
+	Rotate(ctx context.Context, old *RefreshToken, token string, expiry time.Time) error
+	// RevokeFamily revokes the tokens of the family and its session.
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+}
+
+// RevokedTokensRepository is the denylist of access tokens revoked before
+// their expiry, keyed by their jti claim.
+type RevokedTokensRepository interface {
+	Revoke(ctx context.Context, jti string, expiry time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
@@ -3,20 +3,70 @@ package domain
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ErrUserInactive is returned for users who have not activated their account
+// or were deactivated by an admin.
+var ErrUserInactive = errors.New("user is not active")
+
 type User struct {
-	ID        int64    `json:"id"`
-	Username  string   `json:"username"`
-	Email     string   `json:"email"`
-	Password  Password `json:"-"`
-	CreatedAt string   `json:"created_at"`
-	IsActive  bool     `json:"is_active"`
-	RoleID    int64    `json:"role_id"`
-	Role      Role     `json:"role"`
+	ID        int64      `json:"id"`
+	Username  string     `json:"username"`
+	Email     string     `json:"email"`
+	Password  Password   `json:"-"`
+	CreatedAt string     `json:"created_at"`
+	IsActive  bool       `json:"is_active"`
+	BannedAt  *time.Time `json:"banned_at"`
+	// ActivatedAt is when the user first activated the account, nil until
+	// they accept the invitation.
+	ActivatedAt *time.Time `json:"activated_at"`
+	RoleID      int64      `json:"role_id"`
+	Role        Role       `json:"role"`
+	Profile     Profile    `json:"profile"`
+}
+
+// Profile is what users tell about themselves to the other users.
+type Profile struct {
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	Location    string `json:"location"`
+	Website     string `json:"website"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+// UpdateProfilePayload changes the profile fields it sets, an empty string
+// clears one. omitempty only skips unset fields, so the URLs accept an empty
+// string with eq=.
+type UpdateProfilePayload struct {
+	DisplayName *string `json:"display_name" validate:"omitempty,max=50"`
+	Bio         *string `json:"bio" validate:"omitempty,max=500"`
+	Location    *string `json:"location" validate:"omitempty,max=100"`
+	Website     *string `json:"website" validate:"omitempty,max=255,eq=|http_url"`
+	AvatarURL   *string `json:"avatar_url" validate:"omitempty,max=255,eq=|http_url"`
+}
+
+// trimmed returns the payload with the surrounding spaces of the fields it
+// sets trimmed.
+func (p UpdateProfilePayload) trimmed() UpdateProfilePayload {
+	trim := func(value *string) *string {
+		if value == nil {
+			return nil
+		}
+		trimmed := strings.TrimSpace(*value)
+		return &trimmed
+	}
+	return UpdateProfilePayload{
+		DisplayName: trim(p.DisplayName),
+		Bio:         trim(p.Bio),
+		Location:    trim(p.Location),
+		Website:     trim(p.Website),
+		AvatarURL:   trim(p.AvatarURL),
+	}
 }
 
 type Password struct {
@@ -44,17 +94,20 @@ type UsersUseCase struct {
 	cache       UsersCache
 	usersRepo   UsersRepository
 	followsRepo FollowsRepository
+	audit       AuditLogger
 }
 
 func NewUsersUseCase(
 	cache UsersCache,
 	usersRepo UsersRepository,
 	followsRepo FollowsRepository,
+	audit AuditLogger,
 ) *UsersUseCase {
 	return &UsersUseCase{
 		cache:       cache,
 		usersRepo:   usersRepo,
 		followsRepo: followsRepo,
+		audit:       audit,
 	}
 }
 
@@ -77,6 +130,22 @@ func (uc *UsersUseCase) GetUserById(ctx context.Context, userID int64) (*User, e
 	return user, nil
 }
 
+// GetActiveUser returns the user if they may act: users who are not active get
+// ErrUserInactive and banned users ErrUserBanned.
+func (uc *UsersUseCase) GetActiveUser(ctx context.Context, userID int64) (*User, error) {
+	user, err := uc.GetUserById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.BannedAt != nil {
+		return nil, ErrUserBanned
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	return user, nil
+}
+
 func (uc *UsersUseCase) FollowUser(ctx context.Context, userID int64, followerID int64) error {
 	return uc.followsRepo.Follow(ctx, userID, followerID)
 }
@@ -85,20 +154,84 @@ func (uc *UsersUseCase) UnfollowUser(ctx context.Context, userID int64, follower
 	return uc.followsRepo.Unfollow(ctx, userID, followerID)
 }
 
+// UpdateProfile changes the profile fields the payload sets and returns the
+// updated user. The other fields are left as they are, so concurrent updates
+// of different fields do not overwrite each other. The cached user is
+// evicted, so the other users see the change at once.
+func (uc *UsersUseCase) UpdateProfile(ctx context.Context, userID int64, payload UpdateProfilePayload) (*User, error) {
+	if err := uc.usersRepo.UpdateProfile(ctx, userID, payload.trimmed()); err != nil {
+		return nil, err
+	}
+
+	// A user that could not be evicted expires from the cache on its own.
+	_ = uc.cache.Delete(ctx, userID)
+	return uc.usersRepo.GetByID(ctx, userID)
+}
+
+// ActivateUser activates the user with the token of their invitation.
 func (uc *UsersUseCase) ActivateUser(ctx context.Context, token string) error {
-	return uc.usersRepo.Activate(ctx, token)
+	userID, err := uc.usersRepo.Activate(ctx, hashToken(token))
+	if err != nil {
+		return err
+	}
+
+	uc.audit.Record(ctx, AuditEvent{
+		ActorID:    userID,
+		Action:     AuditUserActivated,
+		TargetType: AuditTargetUser,
+		TargetID:   userID,
+		Metadata:   map[string]any{"via": "invitation"},
+	})
+	return nil
 }
 
 type UsersCache interface {
 	Get(ctx context.Context, id int64) (*User, error)
 	Set(ctx context.Context, user *User) error
+	Delete(ctx context.Context, id int64) error
 }
 
 type UsersRepository interface {
 	Create(ctx context.Context, tx *sql.Tx, user *User) error
 	GetByID(ctx context.Context, id int64) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
-	CreateAndInvite(ctx context.Context, user *User, token string, expiration time.Duration) error
+	// CreateAndInvite creates the user and the invitation, and queues the
+	// invitation outbox message in the same transaction.
+	CreateAndInvite(ctx context.Context, user *User, token string, expiration time.Duration, invitation OutboxMessage) error
 	RevertCreateAndInvite(ctx context.Context, id int64) error
-	Activate(ctx context.Context, token string) error
+	// Reinvite replaces the invitations of the user with a new one and
+	// queues its outbox message in the same transaction.
+	Reinvite(ctx context.Context, userID int64, token string, expiration time.Duration, invitation OutboxMessage) error
+	// DeleteExpiredInvitations deletes the invitations past their expiry
+	// and returns how many.
+	DeleteExpiredInvitations(ctx context.Context) (int64, error)
+	// DeleteUnactivated deletes the users created before createdBefore who
+	// never activated their account and have no pending invitation, banned
+	// users aside, and returns how many.
+	DeleteUnactivated(ctx context.Context, createdBefore time.Time) (int64, error)
+	// Activate activates the user of the invitation and returns their id.
+	Activate(ctx context.Context, token string) (int64, error)
+	// CreatePasswordReset replaces the pending password resets of the user
+	// with a new one and queues its email in the same transaction.
+	CreatePasswordReset(ctx context.Context, userID int64, token string, expiration time.Duration, email OutboxMessage) error
+	// ResetPassword consumes the password reset token, sets the password,
+	// revokes the refresh tokens, sessions and personal access tokens of the
+	// user, and returns their id. It returns ErrNotFound when the token is
+	// unknown, used or expired.
+	ResetPassword(ctx context.Context, token string, password []byte) (int64, error)
+	// List returns a page of users, with their role, whose username or
+	// email contains the search.
+	List(ctx context.Context, query UsersQuery) (UsersPage, error)
+	UpdateRole(ctx context.Context, id int64, roleID int64) error
+	// UpdateProfile sets the profile fields of the user the payload sets and
+	// keeps the others, it returns ErrNotFound for an unknown user.
+	UpdateProfile(ctx context.Context, id int64, payload UpdateProfilePayload) error
+	// SetActive activates or deactivates the user. Deactivating also revokes
+	// their refresh tokens, sessions and personal access tokens.
+	SetActive(ctx context.Context, id int64, active bool) error
+	// Ban bans the user and revokes their refresh tokens and personal
+	// access tokens in the same transaction.
+	Ban(ctx context.Context, id int64, at time.Time) error
+	Unban(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64) error
 }
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUsersUseCase_GetActiveUser(t *testing.T) {
+	bannedAt := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+
+	t.Run("it should return the cached user", func(t *testing.T) {
+		cache := NewMockUsersCache(t)
+		useCase := NewUsersUseCase(cache, nil, nil, nil)
+		cache.On("Get", mock.Anything, int64(42)).Return(&User{ID: 42, IsActive: true}, nil)
+
+		user, err := useCase.GetActiveUser(context.Background(), 42)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), user.ID)
+	})
+
+	t.Run("it should load and cache a user missing from the cache", func(t *testing.T) {
+		cache := NewMockUsersCache(t)
+		users := NewMockUsersRepository(t)
+		useCase := NewUsersUseCase(cache, users, nil, nil)
+		user := &User{ID: 42, IsActive: true}
+		cache.On("Get", mock.Anything, int64(42)).Return(nil, nil)
+		users.On("GetByID", mock.Anything, int64(42)).Return(user, nil)
+		cache.On("Set", mock.Anything, user).Return(nil)
+
+		_, err := useCase.GetActiveUser(context.Background(), 42)
+
+		assert.NoError(t, err)
+	})
+
+	tests := []struct {
+		name string
+		user *User
+		err  error
+	}{
+		{name: "it should reject users who are not active", user: &User{ID: 42}, err: ErrUserInactive},
+		{name: "it should reject banned users", user: &User{ID: 42, IsActive: true, BannedAt: &bannedAt}, err: ErrUserBanned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := NewMockUsersCache(t)
+			useCase := NewUsersUseCase(cache, nil, nil, nil)
+			cache.On("Get", mock.Anything, int64(42)).Return(tt.user, nil)
+
+			_, err := useCase.GetActiveUser(context.Background(), 42)
+
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestUsersUseCase_UpdateProfile(t *testing.T) {
+	bio := "  Not today.  "
+	website := ""
+
+	t.Run("it should change the fields of the payload and evict the cached user", func(t *testing.T) {
+		cache := NewMockUsersCache(t)
+		users := NewMockUsersRepository(t)
+		useCase := NewUsersUseCase(cache, users, nil, nil)
+		updated := &User{ID: 42, Profile: Profile{DisplayName: "Arya", Bio: "Not today."}}
+		users.On("UpdateProfile", mock.Anything, int64(42), mock.Anything).Return(nil)
+		cache.On("Delete", mock.Anything, int64(42)).Return(nil)
+		users.On("GetByID", mock.Anything, int64(42)).Return(updated, nil)
+
+		user, err := useCase.UpdateProfile(context.Background(), 42, UpdateProfilePayload{Bio: &bio, Website: &website})
+
+		assert.NoError(t, err)
+		assert.Equal(t, updated, user)
+		trimmed := "Not today."
+		users.AssertCalled(t, "UpdateProfile", mock.Anything, int64(42), UpdateProfilePayload{Bio: &trimmed, Website: &website})
+	})
+
+	t.Run("it should keep the cached user when the update fails", func(t *testing.T) {
+		cache := NewMockUsersCache(t)
+		users := NewMockUsersRepository(t)
+		useCase := NewUsersUseCase(cache, users, nil, nil)
+		users.On("UpdateProfile", mock.Anything, int64(42), mock.Anything).Return(ErrNotFound)
+
+		_, err := useCase.UpdateProfile(context.Background(), 42, UpdateProfilePayload{Bio: &bio})
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateProfilePayload_Validate(t *testing.T) {
+	text := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		payload UpdateProfilePayload
+		valid   bool
+	}{
+		{name: "it should accept an empty payload", valid: true},
+		{name: "it should accept clearing a url", payload: UpdateProfilePayload{Website: text("")}, valid: true},
+		{name: "it should accept http urls", payload: UpdateProfilePayload{AvatarURL: text("https://cdn.example.com/arya.png")}, valid: true},
+		{name: "it should reject urls of other schemes", payload: UpdateProfilePayload{Website: text("javascript:alert(1)")}},
+		{name: "it should reject a long display name", payload: UpdateProfilePayload{DisplayName: text(strings.Repeat("a", 51))}},
+		{name: "it should reject a long bio", payload: UpdateProfilePayload{Bio: text(strings.Repeat("a", 501))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate.Struct(tt.payload)
+
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
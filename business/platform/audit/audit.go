@@ -0,0 +1,40 @@
+// Package audit records audit events.
+package audit
+
+import (
+	"context"
+
+	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/logger"
+	"github.com/sergdort/Social/foundation/otel"
+)
+
+// Logger records audit events in the audit repository, with the client and
+// the trace of the request they happened in. Events that cannot be stored
+// are logged instead, so they are not lost.
+type Logger struct {
+	log  *logger.Logger
+	repo domain.AuditRepository
+}
+
+func NewLogger(log *logger.Logger, repo domain.AuditRepository) *Logger {
+	return &Logger{log: log, repo: repo}
+}
+
+func (l *Logger) Record(ctx context.Context, event domain.AuditEvent) {
+	client := domain.GetClientInfo(ctx)
+	event.IP = client.IP
+	event.UserAgent = client.UserAgent
+	event.TraceID = otel.GetTraceID(ctx)
+
+	if err := l.repo.Append(ctx, event); err != nil {
+		l.log.Error(ctx, "audit: append event",
+			"err", err,
+			"actor_id", event.ActorID,
+			"action", event.Action,
+			"target_type", event.TargetType,
+			"target_id", event.TargetID,
+			"metadata", event.Metadata,
+		)
+	}
+}
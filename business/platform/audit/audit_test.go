@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLogger_Record(t *testing.T) {
+	event := domain.AuditEvent{
+		ActorID:    1,
+		Action:     domain.AuditUserBanned,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   42,
+	}
+	ctx := domain.WithClientInfo(context.Background(), domain.ClientInfo{
+		IP:        "203.0.113.7",
+		UserAgent: "curl/8.5.0",
+	})
+
+	t.Run("it should append the event with the client of the request", func(t *testing.T) {
+		var buf bytes.Buffer
+		repo := domain.NewMockAuditRepository(t)
+		repo.On("Append", mock.Anything, mock.MatchedBy(func(e domain.AuditEvent) bool {
+			return e.Action == domain.AuditUserBanned &&
+				e.IP == "203.0.113.7" &&
+				e.UserAgent == "curl/8.5.0" &&
+				e.TraceID != ""
+		})).Return(nil)
+
+		NewLogger(logger.New(&buf, logger.LevelInfo, "test", nil), repo).Record(ctx, event)
+
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("it should log the event when it cannot be appended", func(t *testing.T) {
+		var buf bytes.Buffer
+		repo := domain.NewMockAuditRepository(t)
+		repo.On("Append", mock.Anything, mock.Anything).Return(errors.New("connection refused"))
+
+		NewLogger(logger.New(&buf, logger.LevelInfo, "test", nil), repo).Record(ctx, event)
+
+		assert.Contains(t, buf.String(), "audit: append event")
+		assert.Contains(t, buf.String(), domain.AuditUserBanned)
+	})
+}
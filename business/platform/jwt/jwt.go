@@ -2,8 +2,10 @@ package jwt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/sergdort/Social/business/domain"
 	"strconv"
 	"time"
@@ -11,6 +13,7 @@ import (
 
 type JWTAutheticator struct {
 	secretKey string
+	keys      *KeySet
 	audience  string
 	issuer    string
 	tokenHost string
@@ -30,7 +33,21 @@ func NewJWTAutheticator(
 	}
 }
 
-func (auth *JWTAutheticator) GenerateToken(ctx context.Context, userID int64) (string, error) {
+// NewJWTAutheticatorWithKeys creates an authenticator signing tokens with the
+// active key of the set, so they can be verified with the published public
+// keys. HS256 tokens signed with secretKey are still accepted unless it is
+// empty, to not log users out while switching.
+func NewJWTAutheticatorWithKeys(
+	keys *KeySet,
+	secretKey, audience, issuer, tokenHost string,
+	expire time.Duration,
+) *JWTAutheticator {
+	auth := NewJWTAutheticator(secretKey, audience, issuer, tokenHost, expire)
+	auth.keys = keys
+	return auth
+}
+
+func (auth *JWTAutheticator) GenerateToken(ctx context.Context, userID int64, sessionID uuid.UUID) (string, error) {
 	claims := jwt.MapClaims{
 		"sub": fmt.Sprintf("%d", userID),
 		"exp": time.Now().Add(auth.expire).Unix(),
@@ -38,6 +55,10 @@ func (auth *JWTAutheticator) GenerateToken(ctx context.Context, userID int64) (s
 		"nbf": time.Now().Unix(),
 		"iss": auth.tokenHost,
 		"aud": auth.tokenHost,
+		"jti": uuid.New().String(),
+	}
+	if sessionID != uuid.Nil {
+		claims["sid"] = sessionID.String()
 	}
 
 	return auth.generate(claims)
@@ -49,14 +70,51 @@ func (auth *JWTAutheticator) ValidateToken(ctx context.Context, token string) (d
 		return domain.Claims{}, err
 	}
 	claims := jwtToken.Claims.(jwt.MapClaims)
-	userID, err := strconv.ParseInt(claims["sub"].(string), 10, 64)
+	subject, err := claims.GetSubject()
+	if err != nil {
+		return domain.Claims{}, err
+	}
+	userID, err := strconv.ParseInt(subject, 10, 64)
+	if err != nil {
+		return domain.Claims{}, err
+	}
+	// The jti is what revocation is keyed by, tokens without one could
+	// never be revoked.
+	jti, ok := claims["jti"].(string)
+	if !ok || jti == "" {
+		return domain.Claims{}, errors.New("token has no jti claim")
+	}
+	exp, err := claims.GetExpirationTime()
 	if err != nil {
 		return domain.Claims{}, err
 	}
-	return domain.Claims{userID}, nil
+	// Tokens from before sessions have no sid.
+	var sessionID uuid.UUID
+	if sid, ok := claims["sid"].(string); ok {
+		if sessionID, err = uuid.Parse(sid); err != nil {
+			return domain.Claims{}, fmt.Errorf("invalid sid claim: %w", err)
+		}
+	}
+	return domain.Claims{UserID: userID, ID: jti, ExpiresAt: exp.Time, SessionID: sessionID}, nil
+}
+
+// JWKS returns the public keys tokens are verified with as a JSON Web Key
+// Set. It is empty when tokens are signed with the shared secret.
+func (auth *JWTAutheticator) JWKS() ([]byte, error) {
+	if auth.keys == nil {
+		return []byte(`{"keys":[]}`), nil
+	}
+	return auth.keys.JWKS()
 }
 
 func (auth *JWTAutheticator) generate(claims jwt.Claims) (string, error) {
+	if auth.keys != nil {
+		key := auth.keys.active
+		token := jwt.NewWithClaims(key.method, claims)
+		token.Header["kid"] = key.ID
+		return token.SignedString(key.private)
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	tokenString, err := token.SignedString([]byte(auth.secretKey))
@@ -67,16 +125,49 @@ func (auth *JWTAutheticator) generate(claims jwt.Claims) (string, error) {
 }
 
 func (auth *JWTAutheticator) validate(token string) (*jwt.Token, error) {
-	return jwt.Parse(token, func(t *jwt.Token) (any, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
-		}
-
-		return []byte(auth.secretKey), nil
-	},
+	return jwt.Parse(token, auth.verificationKey,
 		jwt.WithExpirationRequired(),
 		jwt.WithAudience(auth.audience),
 		jwt.WithIssuer(auth.issuer),
-		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}),
+		jwt.WithValidMethods(auth.methods()),
 	)
 }
+
+// verificationKey returns the key to verify the token with: the key named by
+// its kid header, or the shared secret for HS256 tokens.
+func (auth *JWTAutheticator) verificationKey(t *jwt.Token) (any, error) {
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok {
+		if auth.secretKey == "" {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		return []byte(auth.secretKey), nil
+	}
+
+	if auth.keys == nil {
+		return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+	}
+
+	kid, ok := t.Header["kid"].(string)
+	if !ok {
+		return nil, errors.New("token has no kid header")
+	}
+	key, ok := auth.keys.get(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown key %s", kid)
+	}
+	if t.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %v for key %s", t.Header["alg"], kid)
+	}
+	return key.public, nil
+}
+
+func (auth *JWTAutheticator) methods() []string {
+	var methods []string
+	if auth.keys != nil {
+		methods = auth.keys.methods()
+	}
+	if auth.secretKey != "" {
+		methods = append(methods, jwt.SigningMethodHS256.Name)
+	}
+	return methods
+}
@@ -0,0 +1,169 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTAutheticator(t *testing.T) {
+	ctx := context.Background()
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	// newKeySet names the keys 2025-01, 2025-02 in order, nil skipping a name.
+	newKeySet := func(t *testing.T, active string, keys ...any) *KeySet {
+		ids := []string{"2025-01", "2025-02"}
+		var set []*Key
+		for i, k := range keys {
+			if k == nil {
+				continue
+			}
+			key, err := NewKey(ids[i], k)
+			require.NoError(t, err)
+			set = append(set, key)
+		}
+		keySet, err := NewKeySet(active, set...)
+		require.NoError(t, err)
+		return keySet
+	}
+
+	t.Run("it should sign with the active key and its kid", func(t *testing.T) {
+		for _, key := range []any{edKey, rsaKey} {
+			auth := NewJWTAutheticatorWithKeys(newKeySet(t, "2025-01", key), "", "social", "social", "social", time.Minute)
+
+			sessionID := uuid.New()
+			token, err := auth.GenerateToken(ctx, 42, sessionID)
+			require.NoError(t, err)
+
+			claims, err := auth.ValidateToken(ctx, token)
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), claims.UserID)
+			assert.NotEmpty(t, claims.ID)
+			assert.Equal(t, sessionID, claims.SessionID)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, "2025-01", parsed.Header["kid"])
+		}
+	})
+
+	t.Run("it should verify tokens of the previous key after a rotation", func(t *testing.T) {
+		before := NewJWTAutheticatorWithKeys(newKeySet(t, "2025-01", rsaKey), "", "social", "social", "social", time.Minute)
+		token, err := before.GenerateToken(ctx, 42, uuid.Nil)
+		require.NoError(t, err)
+
+		// The old key is only kept to verify.
+		after := NewJWTAutheticatorWithKeys(
+			newKeySet(t, "2025-02", &rsaKey.PublicKey, edKey),
+			"", "social", "social", "social", time.Minute,
+		)
+
+		claims, err := after.ValidateToken(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), claims.UserID)
+	})
+
+	t.Run("it should reject tokens of unknown keys", func(t *testing.T) {
+		other := NewJWTAutheticatorWithKeys(newKeySet(t, "2025-02", nil, edKey), "", "social", "social", "social", time.Minute)
+		token, err := other.GenerateToken(ctx, 42, uuid.Nil)
+		require.NoError(t, err)
+
+		auth := NewJWTAutheticatorWithKeys(newKeySet(t, "2025-01", rsaKey), "", "social", "social", "social", time.Minute)
+		_, err = auth.ValidateToken(ctx, token)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("it should only accept HS256 tokens as a fallback", func(t *testing.T) {
+		legacy := NewJWTAutheticator("secret", "social", "social", "social", time.Minute)
+		token, err := legacy.GenerateToken(ctx, 42, uuid.Nil)
+		require.NoError(t, err)
+
+		fallback := NewJWTAutheticatorWithKeys(newKeySet(t, "2025-01", edKey), "secret", "social", "social", "social", time.Minute)
+		_, err = fallback.ValidateToken(ctx, token)
+		assert.NoError(t, err)
+
+		strict := NewJWTAutheticatorWithKeys(newKeySet(t, "2025-01", edKey), "", "social", "social", "social", time.Minute)
+		_, err = strict.ValidateToken(ctx, token)
+		assert.Error(t, err)
+	})
+
+	t.Run("it should publish the public keys", func(t *testing.T) {
+		auth := NewJWTAutheticatorWithKeys(newKeySet(t, "2025-02", rsaKey, edKey), "", "social", "social", "social", time.Minute)
+
+		data, err := auth.JWKS()
+		require.NoError(t, err)
+
+		var jwks struct {
+			Keys []map[string]string `json:"keys"`
+		}
+		require.NoError(t, json.Unmarshal(data, &jwks))
+		require.Len(t, jwks.Keys, 2)
+		assert.Equal(t, "2025-01", jwks.Keys[0]["kid"])
+		assert.Equal(t, "RSA", jwks.Keys[0]["kty"])
+		assert.Equal(t, "RS256", jwks.Keys[0]["alg"])
+		assert.Equal(t, "AQAB", jwks.Keys[0]["e"])
+		assert.Equal(t, "2025-02", jwks.Keys[1]["kid"])
+		assert.Equal(t, "OKP", jwks.Keys[1]["kty"])
+		assert.Equal(t, "Ed25519", jwks.Keys[1]["crv"])
+		assert.NotContains(t, string(data), `"d"`)
+	})
+}
+
+func TestLoadKeys(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writePEM := func(name, blockType string, der []byte) {
+		data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+	}
+
+	public, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	writePEM("2025-01.pem", "PUBLIC KEY", public)
+	private, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+	writePEM("2025-02.pem", "PRIVATE KEY", private)
+	writePEM("2025-03.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	t.Run("it should activate the last private key", func(t *testing.T) {
+		keys, err := LoadKeys(dir, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "2025-03", keys.active.ID)
+		assert.Len(t, keys.keys, 3)
+	})
+
+	t.Run("it should activate the given key", func(t *testing.T) {
+		keys, err := LoadKeys(dir, "2025-02")
+
+		require.NoError(t, err)
+		assert.Equal(t, "2025-02", keys.active.ID)
+	})
+
+	t.Run("it should not activate a public key", func(t *testing.T) {
+		_, err := LoadKeys(dir, "2025-01")
+
+		assert.Error(t, err)
+	})
+}
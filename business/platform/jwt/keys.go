@@ -0,0 +1,207 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key is a key tokens are signed or verified with, identified by the kid
+// header of the tokens.
+type Key struct {
+	ID     string
+	method jwt.SigningMethod
+	// private is nil for keys that only verify tokens.
+	private any
+	public  any
+}
+
+// NewKey creates a key from an RSA or Ed25519 key. Private keys sign and
+// verify tokens, public keys only verify them.
+func NewKey(id string, key any) (*Key, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &Key{ID: id, method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return &Key{ID: id, method: jwt.SigningMethodRS256, public: k}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: id, method: jwt.SigningMethodEdDSA, private: k, public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: id, method: jwt.SigningMethodEdDSA, public: k}, nil
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T", id, key)
+	}
+}
+
+// CanSign reports whether the key has its private part.
+func (k *Key) CanSign() bool {
+	return k.private != nil
+}
+
+// KeySet holds the active key new tokens are signed with, and the keys of
+// the tokens still in flight from before a rotation.
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+// NewKeySet creates a set signing with the key identified by activeID.
+func NewKeySet(activeID string, keys ...*Key) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*Key, len(keys))}
+	for _, key := range keys {
+		if _, ok := set.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key %s", key.ID)
+		}
+		set.keys[key.ID] = key
+	}
+
+	active, ok := set.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("active key %s not found", activeID)
+	}
+	if !active.CanSign() {
+		return nil, fmt.Errorf("active key %s has no private key", activeID)
+	}
+	set.active = active
+
+	return set, nil
+}
+
+// LoadKeys loads the keys from the PEM files of dir, the kid of a key being
+// its file name without the .pem extension. Files hold either a private key
+// or a public key, to keep verifying tokens of a retired key.
+//
+// When activeID is empty the last private key in name order is active, so
+// with date based names like 2025-03-19.pem adding a key file rotates keys.
+func LoadKeys(dir string, activeID string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+
+	var keys []*Key
+	for _, file := range files {
+		id := strings.TrimSuffix(filepath.Base(file), ".pem")
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := parsePEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		key, err := NewKey(id, parsed)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys found in %s", dir)
+	}
+
+	if activeID == "" {
+		for _, key := range keys {
+			if key.CanSign() {
+				activeID = key.ID
+			}
+		}
+	}
+
+	return NewKeySet(activeID, keys...)
+}
+
+func parsePEM(data []byte) (any, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+func (s *KeySet) get(id string) (*Key, bool) {
+	key, ok := s.keys[id]
+	return key, ok
+}
+
+func (s *KeySet) methods() []string {
+	var methods []string
+	for _, key := range s.keys {
+		if !slices.Contains(methods, key.method.Alg()) {
+			methods = append(methods, key.method.Alg())
+		}
+	}
+	return methods
+}
+
+// jwk is a public key in the JSON Web Key format of RFC 7517.
+type jwk struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS returns the public keys of the set as a JSON Web Key Set.
+func (s *KeySet) JWKS() ([]byte, error) {
+	ids := make([]string, 0, len(s.keys))
+	for id := range s.keys {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	keys := make([]jwk, 0, len(ids))
+	for _, id := range ids {
+		key := s.keys[id]
+		entry := jwk{KeyID: key.ID, Use: "sig", Algorithm: key.method.Alg()}
+
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			entry.KeyType = "RSA"
+			entry.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			entry.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			entry.KeyType = "OKP"
+			entry.Curve = "Ed25519"
+			entry.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+
+		keys = append(keys, entry)
+	}
+
+	return json.Marshal(struct {
+		Keys []jwk `json:"keys"`
+	}{keys})
+}
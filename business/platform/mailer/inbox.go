@@ -0,0 +1,127 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// InboxMessage is an email kept by the InboxMailer.
+type InboxMessage struct {
+	ID        int       `json:"id"`
+	Template  string    `json:"template"`
+	To        string    `json:"to"`
+	Subject   string    `json:"subject"`
+	PlainBody string    `json:"plain_body"`
+	HTMLBody  string    `json:"html_body"`
+	SentAt    time.Time `json:"sent_at"`
+	Path      string    `json:"path"`
+}
+
+// InboxMailer is a Mailer for local development and tests. Instead of
+// delivering emails it keeps them in memory and writes them to a directory
+// as .eml files.
+type InboxMailer struct {
+	fromEmail string
+	dir       string
+
+	mu       sync.RWMutex
+	messages []InboxMessage
+}
+
+func NewInboxMailer(fromEmail, dir string) (*InboxMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create inbox: %w", err)
+	}
+	return &InboxMailer{
+		fromEmail: fromEmail,
+		dir:       dir,
+	}, nil
+}
+
+func (m *InboxMailer) Send(ctx context.Context, templateFile, username, email string, data any) error {
+	msg, err := render(templateFile, data)
+	if err != nil {
+		return err
+	}
+
+	from := mail.Address{Name: FromName, Address: m.fromEmail}
+	to := mail.Address{Name: username, Address: email}
+	sentAt := time.Now().UTC()
+
+	body, err := msg.bytes(from, to, sentAt)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := len(m.messages) + 1
+	path := filepath.Join(m.dir, fmt.Sprintf("%s-%04d.eml", sentAt.Format("20060102T150405"), id))
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return err
+	}
+
+	m.messages = append(m.messages, InboxMessage{
+		ID:        id,
+		Template:  templateFile,
+		To:        to.String(),
+		Subject:   msg.subject,
+		PlainBody: msg.plain,
+		HTMLBody:  msg.html,
+		SentAt:    sentAt,
+		Path:      path,
+	})
+
+	return nil
+}
+
+// Messages returns the emails sent so far, oldest first.
+func (m *InboxMailer) Messages() []InboxMessage {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	messages := make([]InboxMessage, len(m.messages))
+	copy(messages, m.messages)
+	return messages
+}
+
+// Handler serves the inbox under /debug/mail/: the list of emails as json
+// and every email's HTML body at /debug/mail/{id}.
+func (m *InboxMailer) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /debug/mail/{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(m.Messages())
+	})
+
+	mux.HandleFunc("GET /debug/mail/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+
+		if id < 1 || id > len(m.messages) {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(m.messages[id-1].HTMLBody))
+	})
+
+	return mux
+}
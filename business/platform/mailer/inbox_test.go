@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type invitation struct {
+	Username      string
+	ActivationURL string
+}
+
+func TestInboxMailer(t *testing.T) {
+	data := invitation{
+		Username:      "arya",
+		ActivationURL: "http://localhost:5173/confirm/token?a=1&b=2",
+	}
+
+	t.Run("it should write the rendered email to the inbox", func(t *testing.T) {
+		inbox, err := NewInboxMailer("noreply@social.dev", t.TempDir())
+		require.NoError(t, err)
+
+		require.NoError(t, inbox.Send(context.Background(), UserWelcomeTemplate, "arya", "arya@winterfell.com", data))
+
+		messages := inbox.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, "Finish Registration with GopherSocial", messages[0].Subject)
+		assert.Equal(t, `"arya" <arya@winterfell.com>`, messages[0].To)
+		assert.Contains(t, messages[0].PlainBody, data.ActivationURL)
+		assert.Contains(t, messages[0].HTMLBody, "http://localhost:5173/confirm/token?a=1&amp;b=2")
+
+		eml, err := os.ReadFile(messages[0].Path)
+		require.NoError(t, err)
+		assert.Contains(t, string(eml), "Content-Type: multipart/alternative")
+		assert.Contains(t, string(eml), `Content-Type: text/plain; charset="utf-8"`)
+		assert.Contains(t, string(eml), `Content-Type: text/html; charset="utf-8"`)
+	})
+
+	t.Run("it should serve the inbox", func(t *testing.T) {
+		inbox, err := NewInboxMailer("noreply@social.dev", t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, inbox.Send(context.Background(), UserWelcomeTemplate, "arya", "arya@winterfell.com", data))
+
+		rr := httptest.NewRecorder()
+		inbox.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/mail/", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		var messages []InboxMessage
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&messages))
+		assert.Len(t, messages, 1)
+
+		rr = httptest.NewRecorder()
+		inbox.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/mail/1", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.True(t, strings.Contains(rr.Body.String(), "Hi arya,"))
+
+		rr = httptest.NewRecorder()
+		inbox.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/mail/2", nil))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -1,5 +1,9 @@
 package mailer
 
+import "context"
+
+// Mailer makes a single attempt at delivering an email within the context.
+// Retrying failed deliveries is up to the caller, like the outbox.
 type Mailer interface {
-	Send(templateFile, username, email string, data any) error
+	Send(ctx context.Context, templateFile, username, email string, data any) error
 }
@@ -0,0 +1,125 @@
+package mailer
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// message is an email rendered from one of the embedded templates. Templates
+// define a "subject" and an HTML "body", and optionally a "plainBody" used
+// as the plain text alternative.
+type message struct {
+	subject string
+	plain   string
+	html    string
+}
+
+func render(templateFile string, data any) (message, error) {
+	path := "templates/" + templateFile
+
+	tmpl, err := htmltemplate.ParseFS(FS, path)
+	if err != nil {
+		return message{}, err
+	}
+
+	subject := new(bytes.Buffer)
+	if err := tmpl.ExecuteTemplate(subject, "subject", data); err != nil {
+		return message{}, err
+	}
+
+	body := new(bytes.Buffer)
+	if err := tmpl.ExecuteTemplate(body, "body", data); err != nil {
+		return message{}, err
+	}
+
+	msg := message{
+		subject: strings.TrimSpace(subject.String()),
+		html:    body.String(),
+	}
+
+	if tmpl.Lookup("plainBody") != nil {
+		// Plain text must not be HTML escaped.
+		textTmpl, err := texttemplate.ParseFS(FS, path)
+		if err != nil {
+			return message{}, err
+		}
+		plain := new(bytes.Buffer)
+		if err := textTmpl.ExecuteTemplate(plain, "plainBody", data); err != nil {
+			return message{}, err
+		}
+		msg.plain = strings.TrimSpace(plain.String())
+	}
+
+	return msg, nil
+}
+
+// bytes encodes the message in the RFC 5322 format, as a multipart/alternative
+// message when it has a plain text body.
+func (m message) bytes(from, to mail.Address, date time.Time) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	header := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", m.subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+
+	if m.plain == "" {
+		header("Content-Type", `text/html; charset="utf-8"`)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(buf, m.html); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(buf)
+	header("Content-Type", `multipart/alternative; boundary="`+parts.Boundary()+`"`)
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{contentType: `text/plain; charset="utf-8"`, body: m.plain},
+		{contentType: `text/html; charset="utf-8"`, body: m.html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
@@ -1,16 +1,16 @@
 package mailer
 
 import (
-	"bytes"
+	"context"
 	"embed"
+	"fmt"
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
-	"html/template"
+	"net/http"
 )
 
 const (
 	FromName            = "Social"
-	MaxRetries          = 3
 	UserWelcomeTemplate = "user_invitation.tmpl"
 )
 
@@ -31,30 +31,24 @@ func NewSendgridMailer(fromEmail, apiKey string) Mailer {
 	}
 }
 
-func (m *SendgridMailer) Send(templateFile, username, email string, data any) error {
+func (m *SendgridMailer) Send(ctx context.Context, templateFile, username, email string, data any) error {
 	from := mail.NewEmail(FromName, m.fromEmail)
 	to := mail.NewEmail(username, email)
-	// Template parsing and building
 
-	tmpl, err := template.ParseFS(FS, "templates/"+templateFile)
-	if err != nil {
-		return err
-	}
-	subject := new(bytes.Buffer)
-	err = tmpl.ExecuteTemplate(subject, "subject", data)
+	msg, err := render(templateFile, data)
 	if err != nil {
 		return err
 	}
 
-	body := new(bytes.Buffer)
+	message := mail.NewSingleEmail(from, msg.subject, to, msg.plain, msg.html)
 
-	if err = tmpl.ExecuteTemplate(body, "body", data); err != nil {
+	response, err := m.client.SendWithContext(ctx, message)
+	switch {
+	case err != nil:
 		return err
+	case response.StatusCode >= http.StatusBadRequest:
+		return fmt.Errorf("sendgrid: status %d: %s", response.StatusCode, response.Body)
+	default:
+		return nil
 	}
-
-	message := mail.NewSingleEmail(from, subject.String(), to, "", body.String())
-
-	_, err = m.client.Send(message)
-
-	return err
 }
@@ -0,0 +1,143 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPSecurity is how the connection to the SMTP server is secured.
+type SMTPSecurity string
+
+// Allowed values for SMTPSecurity
+const (
+	// SMTPSecurityStartTLS upgrades a plain connection with STARTTLS,
+	// usually on port 587.
+	SMTPSecurityStartTLS SMTPSecurity = "starttls"
+	// SMTPSecurityTLS connects over TLS from the start, usually on port 465.
+	SMTPSecurityTLS SMTPSecurity = "tls"
+	// SMTPSecurityNone sends in plain text, only meant for local servers.
+	SMTPSecurityNone SMTPSecurity = "none"
+)
+
+type SMTPConfig struct {
+	Host      string
+	Port      int
+	Username  string
+	Password  string
+	FromEmail string
+	Security  SMTPSecurity
+	// Timeout bounds a whole SMTP session.
+	Timeout time.Duration
+	// TLSConfig overrides the TLS configuration, mostly for tests.
+	TLSConfig *tls.Config
+}
+
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) (Mailer, error) {
+	switch config.Security {
+	case SMTPSecurityStartTLS, SMTPSecurityTLS, SMTPSecurityNone:
+	default:
+		return nil, fmt.Errorf("unknown smtp security %q", config.Security)
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.TLSConfig == nil {
+		config.TLSConfig = &tls.Config{ServerName: config.Host}
+	}
+	return &SMTPMailer{config: config}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, templateFile, username, email string, data any) error {
+	msg, err := render(templateFile, data)
+	if err != nil {
+		return err
+	}
+
+	from := mail.Address{Name: FromName, Address: m.config.FromEmail}
+	to := mail.Address{Name: username, Address: email}
+
+	body, err := msg.bytes(from, to, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return m.send(ctx, from.Address, to.Address, body)
+}
+
+func (m *SMTPMailer) send(ctx context.Context, from, to string, body []byte) error {
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	dialer := &net.Dialer{Timeout: m.config.Timeout}
+
+	var conn net.Conn
+	var err error
+	if m.config.Security == SMTPSecurityTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: m.config.TLSConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(m.config.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	// Closing the connection aborts the session when the context is done.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if m.config.Security == SMTPSecurityStartTLS {
+		if err := client.StartTLS(m.config.TLSConfig); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+
+	if m.config.Username != "" {
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
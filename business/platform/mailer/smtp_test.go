@@ -0,0 +1,165 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts a single session per connection and records the
+// envelope and data it receives. rcptCode is the reply to RCPT TO.
+type fakeSMTPServer struct {
+	listener net.Listener
+	rcptCode int
+	sessions chan fakeSMTPSession
+}
+
+type fakeSMTPSession struct {
+	from string
+	to   string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T, rcptCode int) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeSMTPServer{
+		listener: listener,
+		rcptCode: rcptCode,
+		sessions: make(chan fakeSMTPSession, 10),
+	}
+	go server.serve()
+	return server
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	var session fakeSMTPSession
+
+	_ = tp.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250 localhost")
+		case "MAIL":
+			session.from = line
+			_ = tp.PrintfLine("250 OK")
+		case "RCPT":
+			session.to = line
+			_ = tp.PrintfLine(strconv.Itoa(s.rcptCode) + " recipient")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			session.data = string(data)
+			_ = tp.PrintfLine("250 OK")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			s.sessions <- session
+			return
+		default:
+			_ = tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPMailer(t *testing.T) {
+	data := invitation{
+		Username:      "arya",
+		ActivationURL: "http://localhost:5173/confirm/token",
+	}
+
+	t.Run("it should deliver a multipart email", func(t *testing.T) {
+		server := newFakeSMTPServer(t, 250)
+		mailer, err := NewSMTPMailer(SMTPConfig{
+			Host:      "127.0.0.1",
+			Port:      server.port(),
+			FromEmail: "noreply@social.dev",
+			Security:  SMTPSecurityNone,
+			Timeout:   time.Second,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, mailer.Send(context.Background(), UserWelcomeTemplate, "arya", "arya@winterfell.com", data))
+
+		session := <-server.sessions
+		assert.Equal(t, "MAIL FROM:<noreply@social.dev>", session.from)
+		assert.Equal(t, "RCPT TO:<arya@winterfell.com>", session.to)
+		assert.Contains(t, session.data, "Subject: Finish Registration with GopherSocial")
+		assert.Contains(t, session.data, "Content-Type: multipart/alternative")
+		assert.Contains(t, session.data, data.ActivationURL)
+	})
+
+	t.Run("it should return the reply of a rejected recipient", func(t *testing.T) {
+		server := newFakeSMTPServer(t, 550)
+		mailer, err := NewSMTPMailer(SMTPConfig{
+			Host:      "127.0.0.1",
+			Port:      server.port(),
+			FromEmail: "noreply@social.dev",
+			Security:  SMTPSecurityNone,
+			Timeout:   time.Second,
+		})
+		require.NoError(t, err)
+
+		err = mailer.Send(context.Background(), UserWelcomeTemplate, "arya", "arya@winterfell.com", data)
+
+		var reply *textproto.Error
+		require.ErrorAs(t, err, &reply)
+		assert.Equal(t, 550, reply.Code)
+	})
+
+	t.Run("it should not send once the context is done", func(t *testing.T) {
+		server := newFakeSMTPServer(t, 250)
+		mailer, err := NewSMTPMailer(SMTPConfig{
+			Host:      "127.0.0.1",
+			Port:      server.port(),
+			FromEmail: "noreply@social.dev",
+			Security:  SMTPSecurityNone,
+			Timeout:   time.Second,
+		})
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err = mailer.Send(ctx, UserWelcomeTemplate, "arya", "arya@winterfell.com", data)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("it should reject an unknown security mode", func(t *testing.T) {
+		_, err := NewSMTPMailer(SMTPConfig{Security: "ssl"})
+
+		assert.EqualError(t, err, `unknown smtp security "ssl"`)
+	})
+}
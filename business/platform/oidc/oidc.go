@@ -0,0 +1,288 @@
+// Package oidc provides support for logging in with OpenID Connect identity
+// providers, using the authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sergdort/Social/business/domain"
+)
+
+// ErrInvalidIDToken is returned when the ID token of the provider does not
+// verify.
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+const (
+	// keysRefreshInterval is the least time between two fetches of the
+	// provider keys, so tokens with unknown key ids cannot make us hammer
+	// the provider.
+	keysRefreshInterval = time.Minute
+
+	// maxResponseSize limits what is read from the provider.
+	maxResponseSize = 1 << 20
+)
+
+type Config struct {
+	// Name identifies the provider in URLs and in the linked identities.
+	Name string
+	// Issuer is the issuer URL of the provider, its configuration is
+	// discovered from Issuer/.well-known/openid-configuration.
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is where the provider sends users back to, registered
+	// with the provider.
+	RedirectURL string
+	// Scopes are requested on top of openid, email and profile by default.
+	Scopes []string
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is an OpenID Connect provider. Its configuration and keys are
+// fetched on first use and the keys again when tokens are signed with an
+// unknown one, after a rotation.
+type Provider struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+
+	mu            sync.Mutex
+	discovery     *discovery
+	keys          map[string]any
+	keysFetchedAt time.Time
+}
+
+// New constructs a provider using the given http client.
+func New(config Config, client *http.Client) *Provider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"email", "profile"}
+	}
+	return &Provider{
+		config: config,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL returns the authorization URL of the provider for the state,
+// nonce and S256 PKCE challenge.
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(append([]string{"openid"}, p.config.Scopes...), " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+// Exchange exchanges the code for tokens and returns the identity of the
+// verified ID token.
+func (p *Provider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (domain.OIDCIdentity, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return domain.OIDCIdentity{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return domain.OIDCIdentity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := p.do(req, &tokens); err != nil {
+		return domain.OIDCIdentity{}, fmt.Errorf("exchange code: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return domain.OIDCIdentity{}, fmt.Errorf("%w: missing from the token response", ErrInvalidIDToken)
+	}
+
+	return p.verify(ctx, d, tokens.IDToken, nonce)
+}
+
+// idTokenClaims are the claims of an ID token.
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce           string `json:"nonce"`
+	AuthorizedParty string `json:"azp"`
+	Email           string `json:"email"`
+	EmailVerified   flag   `json:"email_verified"`
+	Name            string `json:"name"`
+}
+
+func (p *Provider) verify(ctx context.Context, d *discovery, idToken string, nonce string) (domain.OIDCIdentity, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(idToken, &claims,
+		func(t *jwt.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+			return p.key(ctx, d, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(p.now),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return domain.OIDCIdentity{}, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	// The nonce binds the token to the login that asked for it.
+	if claims.Nonce != nonce {
+		return domain.OIDCIdentity{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return domain.OIDCIdentity{}, fmt.Errorf("%w: authorized party mismatch", ErrInvalidIDToken)
+	}
+	if claims.Subject == "" {
+		return domain.OIDCIdentity{}, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return domain.OIDCIdentity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var d discovery
+	if err := p.do(req, &d); err != nil {
+		return nil, fmt.Errorf("discover %s: %w", p.config.Issuer, err)
+	}
+	// The issuer must match the one configured, or another provider could
+	// issue tokens in its name.
+	if d.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("discover %s: issuer mismatch %q", p.config.Issuer, d.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("discover %s: incomplete configuration", p.config.Issuer)
+	}
+
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+// key returns the key of the kid, fetching the keys again when it is
+// unknown.
+func (p *Provider) key(ctx context.Context, d *discovery, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	if p.keys != nil && p.now().Sub(p.keysFetchedAt) < keysRefreshInterval {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set jwkSet
+	if err := p.do(req, &set); err != nil {
+		return nil, fmt.Errorf("fetch keys: %w", err)
+	}
+
+	p.keys = set.keys()
+	p.keysFetchedAt = p.now()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+func (p *Provider) do(req *http.Request, v any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, v)
+}
+
+// flag decodes the booleans some providers send as strings.
+type flag bool
+
+func (f *flag) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*f = true
+	case "false", "null":
+		*f = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/business/platform/oidc"
+	"github.com/sergdort/Social/business/platform/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	const verifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := sha256.Sum256([]byte(verifier))
+
+	newProvider := func(server *oidctest.Server) *oidc.Provider {
+		return oidc.New(oidc.Config{
+			Name:         "test",
+			Issuer:       server.Issuer(),
+			ClientID:     oidctest.ClientID,
+			ClientSecret: oidctest.ClientSecret,
+			RedirectURL:  oidctest.RedirectURL,
+		}, server.Client())
+	}
+
+	// login authorizes with the nonce and returns the code.
+	login := func(t *testing.T, server *oidctest.Server, provider *oidc.Provider, nonce string) string {
+		authURL, err := provider.AuthCodeURL(ctx, "state", nonce, base64.RawURLEncoding.EncodeToString(challenge[:]))
+		require.NoError(t, err)
+		code, state, err := server.Authorize(authURL)
+		require.NoError(t, err)
+		require.Equal(t, "state", state)
+		return code
+	}
+
+	t.Run("it should build the authorization URL", func(t *testing.T) {
+		server := oidctest.NewServer(t)
+
+		authURL, err := newProvider(server).AuthCodeURL(ctx, "state", "nonce", "challenge")
+		require.NoError(t, err)
+
+		u, err := url.Parse(authURL)
+		require.NoError(t, err)
+		assert.Equal(t, server.Issuer()+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+		assert.Equal(t, url.Values{
+			"response_type":         {"code"},
+			"client_id":             {oidctest.ClientID},
+			"redirect_uri":          {oidctest.RedirectURL},
+			"scope":                 {"openid email profile"},
+			"state":                 {"state"},
+			"nonce":                 {"nonce"},
+			"code_challenge":        {"challenge"},
+			"code_challenge_method": {"S256"},
+		}, u.Query())
+	})
+
+	t.Run("it should exchange the code for the identity", func(t *testing.T) {
+		server := oidctest.NewServer(t)
+		provider := newProvider(server)
+		code := login(t, server, provider, "nonce")
+
+		identity, err := provider.Exchange(ctx, code, verifier, "nonce")
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.OIDCIdentity{
+			Subject:       "248289761001",
+			Email:         "arya@example.com",
+			EmailVerified: true,
+			Name:          "Arya Stark",
+		}, identity)
+	})
+
+	t.Run("it should accept email_verified as a string", func(t *testing.T) {
+		server := oidctest.NewServer(t)
+		server.Claims["email_verified"] = "true"
+		provider := newProvider(server)
+		code := login(t, server, provider, "nonce")
+
+		identity, err := provider.Exchange(ctx, code, verifier, "nonce")
+
+		require.NoError(t, err)
+		assert.True(t, identity.EmailVerified)
+	})
+
+	t.Run("it should fail with the wrong code verifier", func(t *testing.T) {
+		server := oidctest.NewServer(t)
+		provider := newProvider(server)
+		code := login(t, server, provider, "nonce")
+
+		_, err := provider.Exchange(ctx, code, "wrong", "nonce")
+
+		assert.ErrorContains(t, err, "invalid_grant")
+	})
+
+	t.Run("it should reject a nonce mismatch", func(t *testing.T) {
+		server := oidctest.NewServer(t)
+		provider := newProvider(server)
+		code := login(t, server, provider, "nonce")
+
+		_, err := provider.Exchange(ctx, code, verifier, "other")
+
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	t.Run("it should reject tokens for another audience", func(t *testing.T) {
+		server := oidctest.NewServer(t)
+		server.Claims["aud"] = "someone-else"
+		provider := newProvider(server)
+		code := login(t, server, provider, "nonce")
+
+		_, err := provider.Exchange(ctx, code, verifier, "nonce")
+
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	t.Run("it should reject tokens for several audiences without the authorized party", func(t *testing.T) {
+		server := oidctest.NewServer(t)
+		server.Claims["aud"] = []string{oidctest.ClientID, "someone-else"}
+		server.Claims["azp"] = "someone-else"
+		provider := newProvider(server)
+		code := login(t, server, provider, "nonce")
+
+		_, err := provider.Exchange(ctx, code, verifier, "nonce")
+
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	t.Run("it should reject expired tokens", func(t *testing.T) {
+		server := oidctest.NewServer(t)
+		server.Claims["exp"] = time.Now().Add(-time.Hour).Unix()
+		provider := newProvider(server)
+		code := login(t, server, provider, "nonce")
+
+		_, err := provider.Exchange(ctx, code, verifier, "nonce")
+
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	t.Run("it should reject tokens signed with another key", func(t *testing.T) {
+		server := oidctest.NewServer(t)
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		server.SigningKey = key
+		provider := newProvider(server)
+		code := login(t, server, provider, "nonce")
+
+		_, err = provider.Exchange(ctx, code, verifier, "nonce")
+
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	t.Run("it should reject tokens without a subject", func(t *testing.T) {
+		server := oidctest.NewServer(t)
+		server.Claims["sub"] = nil
+		provider := newProvider(server)
+		code := login(t, server, provider, "nonce")
+
+		_, err := provider.Exchange(ctx, code, verifier, "nonce")
+
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+}
@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/sergdort/Social/business/domain"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+)
+
+type AuditStore struct {
+	queries *sqlc2.Queries
+}
+
+func (s *AuditStore) Append(ctx context.Context, event domain.AuditEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	metadata := []byte("{}")
+	if len(event.Metadata) > 0 {
+		var err error
+		if metadata, err = json.Marshal(event.Metadata); err != nil {
+			return err
+		}
+	}
+
+	return s.queries.CreateAuditEvent(ctx, sqlc2.CreateAuditEventParams{
+		ActorID:    toNullID(event.ActorID),
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   toNullID(event.TargetID),
+		Ip:         event.IP,
+		UserAgent:  event.UserAgent,
+		TraceID:    event.TraceID,
+		Metadata:   metadata,
+	})
+}
+
+func (s *AuditStore) Query(ctx context.Context, q domain.AuditQuery) (domain.AuditPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	params := sqlc2.ListAuditEventsParams{
+		ActorID:    toNullInt64(q.ActorID),
+		Action:     q.Action,
+		TargetType: q.TargetType,
+		TargetID:   toNullInt64(q.TargetID),
+		Since:      toNullTime(q.Since),
+		Until:      toNullTime(q.Until),
+		// Fetch one extra row to know whether there is a next page.
+		PageLimit: int32(q.Limit + 1),
+	}
+	if q.Cursor != nil {
+		params.CursorCreatedAt = sql.NullTime{Time: q.Cursor.CreatedAt, Valid: true}
+		params.CursorID = sql.NullInt64{Int64: q.Cursor.ID, Valid: true}
+	}
+
+	rows, err := s.queries.ListAuditEvents(ctx, params)
+	if err != nil {
+		return domain.AuditPage{}, err
+	}
+
+	var next *domain.Cursor
+	if len(rows) > q.Limit {
+		rows = rows[:q.Limit]
+		last := rows[len(rows)-1]
+		next = &domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	events := make([]domain.AuditEvent, 0, len(rows))
+	for _, row := range rows {
+		var metadata map[string]any
+		if err := json.Unmarshal(row.Metadata, &metadata); err != nil {
+			return domain.AuditPage{}, err
+		}
+		events = append(events, domain.AuditEvent{
+			ID:         row.ID,
+			ActorID:    row.ActorID.Int64,
+			Action:     row.Action,
+			TargetType: row.TargetType,
+			TargetID:   row.TargetID.Int64,
+			IP:         row.Ip,
+			UserAgent:  row.UserAgent,
+			TraceID:    row.TraceID,
+			Metadata:   metadata,
+			CreatedAt:  row.CreatedAt,
+		})
+	}
+
+	return domain.AuditPage{Events: events, NextCursor: next}, nil
+}
+
+// toNullID maps the 0 id of anonymous actors and unknown targets to NULL.
+func toNullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"github.com/sergdort/Social/business/domain"
+	"sync"
+	"time"
+)
+
+// LoginAttemptsStore counts failed logins in Redis, shared by every
+// instance of the API.
+type LoginAttemptsStore struct {
+	rdb *redis.Client
+}
+
+func (s *LoginAttemptsStore) Fail(ctx context.Context, key string, window time.Duration) (int, error) {
+	pipe := s.rdb.TxPipeline()
+	incr := pipe.Incr(ctx, failuresKey(key))
+	pipe.Expire(ctx, failuresKey(key), window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
+
+func (s *LoginAttemptsStore) Lock(ctx context.Context, key string, d time.Duration) error {
+	return s.rdb.Set(ctx, lockKey(key), 1, d).Err()
+}
+
+func (s *LoginAttemptsStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.rdb.PTTL(ctx, lockKey(key)).Result()
+	if err != nil {
+		return 0, err
+	}
+	// Negative for keys that do not exist.
+	return max(ttl, 0), nil
+}
+
+func (s *LoginAttemptsStore) Reset(ctx context.Context, key string) error {
+	return s.rdb.Del(ctx, failuresKey(key)).Err()
+}
+
+func failuresKey(key string) string {
+	return key + ":failures"
+}
+
+func lockKey(key string) string {
+	return key + ":lock"
+}
+
+// sweepInterval is how often MemoryLoginAttemptsStore drops expired entries.
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	value  int
+	expiry time.Time
+}
+
+// MemoryLoginAttemptsStore counts failed logins in memory, per instance of
+// the API. It is used when Redis is disabled or failing.
+type MemoryLoginAttemptsStore struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	nextSweep time.Time
+}
+
+func NewMemoryLoginAttemptsStore() *MemoryLoginAttemptsStore {
+	return &MemoryLoginAttemptsStore{
+		now:     time.Now,
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+func (s *MemoryLoginAttemptsStore) Fail(ctx context.Context, key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	entry := s.get(failuresKey(key), now)
+	entry.value++
+	entry.expiry = now.Add(window)
+	s.entries[failuresKey(key)] = entry
+	return entry.value, nil
+}
+
+func (s *MemoryLoginAttemptsStore) Lock(ctx context.Context, key string, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[lockKey(key)] = memoryEntry{value: 1, expiry: s.now().Add(d)}
+	return nil
+}
+
+func (s *MemoryLoginAttemptsStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	entry := s.get(lockKey(key), now)
+	if entry.value == 0 {
+		return 0, nil
+	}
+	return entry.expiry.Sub(now), nil
+}
+
+func (s *MemoryLoginAttemptsStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, failuresKey(key))
+	return nil
+}
+
+// get returns the entry of the key, zero once expired.
+func (s *MemoryLoginAttemptsStore) get(key string, now time.Time) memoryEntry {
+	entry, ok := s.entries[key]
+	if !ok || !entry.expiry.After(now) {
+		return memoryEntry{}
+	}
+	return entry
+}
+
+func (s *MemoryLoginAttemptsStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	for key, entry := range s.entries {
+		if !entry.expiry.After(now) {
+			delete(s.entries, key)
+		}
+	}
+	s.nextSweep = now.Add(sweepInterval)
+}
+
+// fallbackLoginAttemptsStore counts failed logins in the primary store, and
+// in the fallback one while the primary fails, so logins stay throttled and
+// available when Redis is down.
+type fallbackLoginAttemptsStore struct {
+	primary  domain.LoginAttemptsRepository
+	fallback domain.LoginAttemptsRepository
+}
+
+func (s *fallbackLoginAttemptsStore) Fail(ctx context.Context, key string, window time.Duration) (int, error) {
+	if failures, err := s.primary.Fail(ctx, key, window); err == nil {
+		return failures, nil
+	}
+	return s.fallback.Fail(ctx, key, window)
+}
+
+func (s *fallbackLoginAttemptsStore) Lock(ctx context.Context, key string, d time.Duration) error {
+	if err := s.primary.Lock(ctx, key, d); err == nil {
+		return nil
+	}
+	return s.fallback.Lock(ctx, key, d)
+}
+
+// LockedFor checks both stores, a lockout made while the primary was failing
+// must hold once it recovers.
+func (s *fallbackLoginAttemptsStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	fallback, err := s.fallback.LockedFor(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	primary, err := s.primary.LockedFor(ctx, key)
+	if err != nil {
+		return fallback, nil
+	}
+	return max(primary, fallback), nil
+}
+
+func (s *fallbackLoginAttemptsStore) Reset(ctx context.Context, key string) error {
+	if err := s.fallback.Reset(ctx, key); err != nil {
+		return err
+	}
+	// Left to expire in the primary when it cannot be reached.
+	_ = s.primary.Reset(ctx, key)
+	return nil
+}
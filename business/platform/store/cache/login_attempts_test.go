@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLoginAttemptsStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	newStore := func() *MemoryLoginAttemptsStore {
+		store := NewMemoryLoginAttemptsStore()
+		store.now = func() time.Time { return now }
+		return store
+	}
+
+	t.Run("it should count failures within the window", func(t *testing.T) {
+		store := newStore()
+
+		first, _ := store.Fail(ctx, "key", time.Minute)
+		second, _ := store.Fail(ctx, "key", time.Minute)
+		now = now.Add(2 * time.Minute)
+		third, _ := store.Fail(ctx, "key", time.Minute)
+
+		assert.Equal(t, []int{1, 2, 1}, []int{first, second, third})
+	})
+
+	t.Run("it should lock until the lockout expires", func(t *testing.T) {
+		store := newStore()
+
+		assert.NoError(t, store.Lock(ctx, "key", time.Minute))
+		now = now.Add(20 * time.Second)
+		lockedFor, _ := store.LockedFor(ctx, "key")
+		assert.Equal(t, 40*time.Second, lockedFor)
+
+		now = now.Add(time.Minute)
+		lockedFor, _ = store.LockedFor(ctx, "key")
+		assert.Zero(t, lockedFor)
+	})
+
+	t.Run("it should reset failures but keep the lockout", func(t *testing.T) {
+		store := newStore()
+		_, _ = store.Fail(ctx, "key", time.Minute)
+		_ = store.Lock(ctx, "key", time.Minute)
+
+		assert.NoError(t, store.Reset(ctx, "key"))
+
+		failures, _ := store.Fail(ctx, "key", time.Minute)
+		lockedFor, _ := store.LockedFor(ctx, "key")
+		assert.Equal(t, 1, failures)
+		assert.Equal(t, time.Minute, lockedFor)
+	})
+}
+
+func TestFallbackLoginAttemptsStore(t *testing.T) {
+	ctx := context.Background()
+	fallback := NewMemoryLoginAttemptsStore()
+	store := &fallbackLoginAttemptsStore{primary: failingLoginAttempts{}, fallback: fallback}
+
+	failures, err := store.Fail(ctx, "key", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, failures)
+
+	assert.NoError(t, store.Lock(ctx, "key", time.Minute))
+	lockedFor, err := store.LockedFor(ctx, "key")
+	assert.NoError(t, err)
+	assert.Positive(t, lockedFor)
+}
+
+type failingLoginAttempts struct{}
+
+var errUnavailable = errors.New("unavailable")
+
+func (failingLoginAttempts) Fail(context.Context, string, time.Duration) (int, error) {
+	return 0, errUnavailable
+}
+
+func (failingLoginAttempts) Lock(context.Context, string, time.Duration) error {
+	return errUnavailable
+}
+
+func (failingLoginAttempts) LockedFor(context.Context, string) (time.Duration, error) {
+	return 0, errUnavailable
+}
+
+func (failingLoginAttempts) Reset(context.Context, string) error {
+	return errUnavailable
+}
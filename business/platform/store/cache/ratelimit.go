@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/sergdort/Social/foundation/ratelimit"
+	"time"
+)
+
+// rateLimitRetries is how often a request is counted again when another
+// instance changed the same key in the meantime.
+const rateLimitRetries = 5
+
+// RateLimitStore counts requests in Redis, shared by every instance of the
+// API. Keys are updated in optimistic transactions, so both algorithms of
+// ratelimit run unchanged.
+type RateLimitStore struct {
+	rdb *redis.Client
+	now func() time.Time
+}
+
+func NewRateLimitStore(rdb *redis.Client) *RateLimitStore {
+	return &RateLimitStore{rdb: rdb, now: time.Now}
+}
+
+func (s *RateLimitStore) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	var result ratelimit.Result
+	take := func(tx *redis.Tx) error {
+		var state ratelimit.State
+		data, err := tx.Get(ctx, key).Bytes()
+		switch {
+		case errors.Is(err, redis.Nil):
+		case err != nil:
+			return err
+		default:
+			if err := json.Unmarshal(data, &state); err != nil {
+				return err
+			}
+		}
+
+		state, result = limit.Take(state, s.now())
+		data, err = json.Marshal(state)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, data, limit.TTL())
+			return nil
+		})
+		return err
+	}
+
+	for range rateLimitRetries {
+		err := s.rdb.Watch(ctx, take, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		return result, err
+	}
+	return ratelimit.Result{}, fmt.Errorf("rate limit %s: too much contention", key)
+}
@@ -3,14 +3,29 @@ package cache
 import (
 	"github.com/redis/go-redis/v9"
 	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/ratelimit"
 )
 
 type Storage struct {
-	Users domain.UsersCache
+	Users         domain.UsersCache
+	LoginAttempts domain.LoginAttemptsRepository
+	RateLimits    ratelimit.Store
 }
 
 func NewStorage(rdb *redis.Client) Storage {
+	var loginAttempts domain.LoginAttemptsRepository = NewMemoryLoginAttemptsStore()
+	var rateLimits ratelimit.Store = ratelimit.NewMemoryStore()
+	if rdb != nil {
+		loginAttempts = &fallbackLoginAttemptsStore{
+			primary:  &LoginAttemptsStore{rdb: rdb},
+			fallback: loginAttempts,
+		}
+		rateLimits = NewRateLimitStore(rdb)
+	}
+
 	return Storage{
-		Users: &UsersStore{rdb: rdb},
+		Users:         &UsersStore{rdb: rdb},
+		LoginAttempts: loginAttempts,
+		RateLimits:    rateLimits,
 	}
 }
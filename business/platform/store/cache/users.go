@@ -46,3 +46,7 @@ func (s *UsersStore) Set(ctx context.Context, user *domain.User) error {
 func cacheKey(userID int64) string {
 	return fmt.Sprintf("user-%d", userID)
 }
+
+func (s *UsersStore) Delete(ctx context.Context, id int64) error {
+	return s.rdb.Del(ctx, cacheKey(id)).Err()
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"github.com/sergdort/Social/business/domain"
 	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
 	"github.com/sergdort/Social/foundation/slices"
@@ -25,6 +26,8 @@ func (s *CommentStore) Create(ctx context.Context, comment *domain.Comment) erro
 			String: comment.Content,
 			Valid:  true,
 		},
+		ParentCommentID: toNullInt64(comment.ParentID),
+		Depth:           int32(comment.Depth),
 	})
 
 	if err != nil {
@@ -33,35 +36,246 @@ func (s *CommentStore) Create(ctx context.Context, comment *domain.Comment) erro
 
 	comment.ID = result.ID
 	comment.CreatedAt = result.CreatedAt.String()
+	comment.UpdatedAt = comment.CreatedAt
 
 	return nil
 }
 
-func (s *CommentStore) GetAllByPostID(ctx context.Context, postID int64) ([]domain.Comment, error) {
+func (s *CommentStore) GetByID(ctx context.Context, id int64) (*domain.Comment, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 
 	defer cancel()
 
-	rows, err := s.queries.GetAllCommentsByPostID(ctx, postID)
+	row, err := s.queries.GetCommentByID(ctx, id)
 	if err != nil {
-		return nil, err
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, domain.ErrNotFound
+		default:
+			return nil, err
+		}
 	}
 
-	comments := slices.Map(rows, convertToComment)
+	return &domain.Comment{
+		ID:           row.ID,
+		PostID:       row.PostID,
+		UserID:       row.UserID,
+		ParentID:     fromNullInt64(row.ParentCommentID),
+		Depth:        int(row.Depth),
+		Content:      row.Content.String,
+		RepliesCount: row.RepliesCount,
+		CreatedAt:    row.CreatedAt.String(),
+		UpdatedAt:    row.UpdatedAt.String(),
+		User: domain.User{
+			ID:       row.UserID,
+			Username: row.Username,
+		},
+	}, nil
+}
+
+func (s *CommentStore) GetPageByPostID(ctx context.Context, postID int64, q domain.CommentsQuery) (domain.CommentsPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+
+	defer cancel()
+
+	params := sqlc2.GetCommentsByPostIDParams{
+		ViewerID: q.ViewerID,
+		PostID:   postID,
+		// Fetch one extra row to know whether there is a next page.
+		PageLimit: int32(q.Limit + 1),
+	}
+	if q.Cursor != nil {
+		params.CursorCreatedAt = sql.NullTime{Time: q.Cursor.CreatedAt, Valid: true}
+		params.CursorID = sql.NullInt64{Int64: q.Cursor.ID, Valid: true}
+	}
 
-	return comments, nil
+	rows, err := s.queries.GetCommentsByPostID(ctx, params)
+	if err != nil {
+		return domain.CommentsPage{}, err
+	}
+
+	return toCommentsPage(rows, q.Limit, convertToPagedComment, func(row sqlc2.GetCommentsByPostIDRow) domain.Cursor {
+		return domain.Cursor{CreatedAt: row.CreatedAt, ID: row.ID}
+	}), nil
 }
 
-func convertToComment(row sqlc2.GetAllCommentsByPostIDRow) domain.Comment {
+func (s *CommentStore) GetRepliesPage(ctx context.Context, commentID int64, q domain.CommentsQuery) (domain.CommentsPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+
+	defer cancel()
+
+	params := sqlc2.GetCommentRepliesParams{
+		ViewerID: q.ViewerID,
+		ParentID: commentID,
+		// Fetch one extra row to know whether there is a next page.
+		PageLimit: int32(q.Limit + 1),
+	}
+	if q.Cursor != nil {
+		params.CursorCreatedAt = sql.NullTime{Time: q.Cursor.CreatedAt, Valid: true}
+		params.CursorID = sql.NullInt64{Int64: q.Cursor.ID, Valid: true}
+	}
+
+	rows, err := s.queries.GetCommentReplies(ctx, params)
+	if err != nil {
+		return domain.CommentsPage{}, err
+	}
+
+	return toCommentsPage(rows, q.Limit, convertToReply, func(row sqlc2.GetCommentRepliesRow) domain.Cursor {
+		return domain.Cursor{CreatedAt: row.CreatedAt, ID: row.ID}
+	}), nil
+}
+
+func (s *CommentStore) GetThread(ctx context.Context, commentID int64, viewerID int64) (domain.CommentThread, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+
+	defer cancel()
+
+	rows, err := s.queries.GetCommentThread(ctx, sqlc2.GetCommentThreadParams{
+		CommentID: commentID,
+		ViewerID:  viewerID,
+		// Fetch one extra row to know whether the thread is truncated.
+		ThreadLimit: int32(domain.MaxThreadReplies + 1),
+	})
+	if err != nil {
+		return domain.CommentThread{}, err
+	}
+
+	truncated := len(rows) > domain.MaxThreadReplies
+	if truncated {
+		rows = rows[:domain.MaxThreadReplies]
+	}
+
+	return domain.CommentThread{
+		Replies:   slices.Map(rows, convertToThreadReply),
+		Truncated: truncated,
+	}, nil
+}
+
+// toCommentsPage converts the rows of a page fetched with one extra row, the
+// extra row only tells there is a next page, which starts after the cursor of
+// the last row kept.
+func toCommentsPage[T any](rows []T, limit int, convert func(T) domain.Comment, cursor func(T) domain.Cursor) domain.CommentsPage {
+	var next *domain.Cursor
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := cursor(rows[len(rows)-1])
+		next = &last
+	}
+
+	return domain.CommentsPage{
+		Comments:   slices.Map(rows, convert),
+		NextCursor: next,
+	}
+}
+
+func (s *CommentStore) Update(ctx context.Context, comment *domain.Comment) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+
+	defer cancel()
+
+	updatedAt, err := s.queries.UpdateComment(ctx, sqlc2.UpdateCommentParams{
+		Content: sql.NullString{
+			String: comment.Content,
+			Valid:  true,
+		},
+		ID: comment.ID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return domain.ErrNotFound
+		default:
+			return err
+		}
+	}
+
+	comment.UpdatedAt = updatedAt.String()
+
+	return nil
+}
+
+func (s *CommentStore) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+
+	defer cancel()
+
+	rows, err := s.queries.DeleteCommentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func convertToPagedComment(row sqlc2.GetCommentsByPostIDRow) domain.Comment {
+	return domain.Comment{
+		ID:           row.ID,
+		PostID:       row.PostID,
+		UserID:       row.UserID,
+		ParentID:     fromNullInt64(row.ParentCommentID),
+		Depth:        int(row.Depth),
+		Content:      row.Content.String,
+		RepliesCount: row.RepliesCount,
+		CreatedAt:    row.CreatedAt.String(),
+		UpdatedAt:    row.UpdatedAt.String(),
+		User: domain.User{
+			ID:       row.UserID,
+			Username: row.Username,
+		},
+		Reactions: toReactions(row.ReactionCounts, row.MyReactions),
+	}
+}
+
+func convertToReply(row sqlc2.GetCommentRepliesRow) domain.Comment {
 	return domain.Comment{
-		ID:        row.ID,
-		PostID:    row.PostID,
-		UserID:    row.UserID,
-		Content:   row.Content.String,
-		CreatedAt: row.CreatedAt.String(),
+		ID:           row.ID,
+		PostID:       row.PostID,
+		UserID:       row.UserID,
+		ParentID:     fromNullInt64(row.ParentCommentID),
+		Depth:        int(row.Depth),
+		Content:      row.Content.String,
+		RepliesCount: row.RepliesCount,
+		CreatedAt:    row.CreatedAt.String(),
+		UpdatedAt:    row.UpdatedAt.String(),
 		User: domain.User{
 			ID:       row.UserID,
 			Username: row.Username,
 		},
+		Reactions: toReactions(row.ReactionCounts, row.MyReactions),
+	}
+}
+
+func convertToThreadReply(row sqlc2.GetCommentThreadRow) domain.Comment {
+	return domain.Comment{
+		ID:           row.ID,
+		PostID:       row.PostID,
+		UserID:       row.UserID,
+		ParentID:     fromNullInt64(row.ParentCommentID),
+		Depth:        int(row.Depth),
+		Content:      row.Content.String,
+		RepliesCount: row.RepliesCount,
+		CreatedAt:    row.CreatedAt.String(),
+		UpdatedAt:    row.UpdatedAt.String(),
+		User: domain.User{
+			ID:       row.UserID,
+			Username: row.Username,
+		},
+		Reactions: toReactions(row.ReactionCounts, row.MyReactions),
+	}
+}
+
+func toNullInt64(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
+
+func fromNullInt64(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
 	}
+	return &v.Int64
 }
@@ -0,0 +1,292 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/sergdort/Social/business/domain"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommentStore(t *testing.T) {
+
+	t.Run("it should query a page of comments with keyset predicates", func(t *testing.T) {
+		query := `-- name: GetCommentsByPostID :many
+SELECT c.id,
+       c.post_id,
+       c.user_id,
+       c.content,
+       c.created_at,
+       c.updated_at,
+       c.parent_comment_id,
+       c.depth,
+       (SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id) AS replies_count,
+       (SELECT COALESCE(jsonb_object_agg(r.type, r.count), '{}')
+        FROM (SELECT cr.type, COUNT(*) AS count
+              FROM comment_reactions cr
+              WHERE cr.comment_id = c.id
+              GROUP BY cr.type) r)::jsonb AS reaction_counts,
+       ARRAY(SELECT cr.type
+             FROM comment_reactions cr
+             WHERE cr.comment_id = c.id
+               AND cr.user_id = $1
+             ORDER BY cr.type)::varchar[] AS my_reactions,
+       u.username
+FROM comments c
+         JOIN users u ON u.id = c.user_id
+WHERE c.post_id = $2
+  AND c.parent_comment_id IS NULL
+  AND ($3::timestamptz IS NULL OR
+       (c.created_at, c.id) < ($3::timestamptz, $4::bigint))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT $5
+`
+		postID := int64(42)
+		createdAt := time.Date(2025, 3, 19, 10, 8, 25, 0, time.UTC)
+		ctx := context.Background()
+		mockDB := sqlc2.NewMockDBTX(t)
+
+		fakeError := errors.New("something went wrong")
+		mockDB.On(
+			"QueryContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(nil, fakeError)
+
+		store := CommentStore{
+			queries: sqlc2.New(mockDB),
+		}
+
+		page, err := store.GetPageByPostID(ctx, postID, domain.CommentsQuery{
+			Limit:    20,
+			Cursor:   &domain.Cursor{CreatedAt: createdAt, ID: 117},
+			ViewerID: 7,
+		})
+
+		assert.Nil(t, page.Comments)
+		assert.EqualError(t, err, fakeError.Error())
+		mockDB.AssertCalled(
+			t,
+			"QueryContext",
+			mock.Anything,
+			query,
+			int64(7),
+			postID,
+			sql.NullTime{Time: createdAt, Valid: true},
+			sql.NullInt64{Int64: 117, Valid: true},
+			int32(21),
+		)
+		mockDB.AssertNumberOfCalls(t, "QueryContext", 1)
+	})
+
+	t.Run("it should query replies oldest first after the cursor", func(t *testing.T) {
+		query := `-- name: GetCommentReplies :many
+SELECT c.id,
+       c.post_id,
+       c.user_id,
+       c.content,
+       c.created_at,
+       c.updated_at,
+       c.parent_comment_id,
+       c.depth,
+       (SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id) AS replies_count,
+       (SELECT COALESCE(jsonb_object_agg(r.type, r.count), '{}')
+        FROM (SELECT cr.type, COUNT(*) AS count
+              FROM comment_reactions cr
+              WHERE cr.comment_id = c.id
+              GROUP BY cr.type) r)::jsonb AS reaction_counts,
+       ARRAY(SELECT cr.type
+             FROM comment_reactions cr
+             WHERE cr.comment_id = c.id
+               AND cr.user_id = $1
+             ORDER BY cr.type)::varchar[] AS my_reactions,
+       u.username
+FROM comments c
+         JOIN users u ON u.id = c.user_id
+WHERE c.parent_comment_id = $2::bigint
+  AND ($3::timestamptz IS NULL OR
+       (c.created_at, c.id) > ($3::timestamptz, $4::bigint))
+ORDER BY c.created_at, c.id
+LIMIT $5
+`
+		commentID := int64(42)
+		ctx := context.Background()
+		mockDB := sqlc2.NewMockDBTX(t)
+
+		fakeError := errors.New("something went wrong")
+		mockDB.On(
+			"QueryContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(nil, fakeError)
+
+		store := CommentStore{
+			queries: sqlc2.New(mockDB),
+		}
+
+		page, err := store.GetRepliesPage(ctx, commentID, domain.CommentsQuery{Limit: 10, ViewerID: 7})
+
+		assert.Nil(t, page.Comments)
+		assert.EqualError(t, err, fakeError.Error())
+		mockDB.AssertCalled(
+			t,
+			"QueryContext",
+			mock.Anything,
+			query,
+			int64(7),
+			commentID,
+			sql.NullTime{},
+			sql.NullInt64{},
+			int32(11),
+		)
+		mockDB.AssertNumberOfCalls(t, "QueryContext", 1)
+	})
+
+	t.Run("it should walk the replies to a comment recursively", func(t *testing.T) {
+		commentID := int64(42)
+		ctx := context.Background()
+		mockDB := sqlc2.NewMockDBTX(t)
+
+		fakeError := errors.New("something went wrong")
+		mockDB.On("QueryContext", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, fakeError)
+
+		store := CommentStore{
+			queries: sqlc2.New(mockDB),
+		}
+
+		thread, err := store.GetThread(ctx, commentID, 7)
+
+		assert.Nil(t, thread.Replies)
+		assert.EqualError(t, err, fakeError.Error())
+		mockDB.AssertCalled(
+			t,
+			"QueryContext",
+			mock.Anything,
+			mock.MatchedBy(func(query string) bool {
+				return strings.HasPrefix(query, "-- name: GetCommentThread ") &&
+					strings.Contains(query, "WITH RECURSIVE thread") &&
+					strings.Contains(query, "JOIN thread t ON c.parent_comment_id = t.id") &&
+					strings.Contains(query, "ORDER BY t.path")
+			}),
+			commentID,
+			int64(7),
+			int32(domain.MaxThreadReplies+1),
+		)
+	})
+
+	t.Run("it should page replies with the cursor of the last reply kept", func(t *testing.T) {
+		createdAt := time.Date(2025, 3, 19, 10, 8, 25, 0, time.UTC)
+		rows := []sqlc2.GetCommentRepliesRow{
+			{
+				ID:              1,
+				PostID:          42,
+				UserID:          7,
+				Content:         sql.NullString{String: "first", Valid: true},
+				CreatedAt:       createdAt,
+				UpdatedAt:       createdAt,
+				ParentCommentID: sql.NullInt64{Int64: 117, Valid: true},
+				Depth:           1,
+				RepliesCount:    2,
+				ReactionCounts:  []byte(`{"like":3}`),
+				MyReactions:     []string{"like"},
+				Username:        "arya",
+			},
+			{ID: 2, CreatedAt: createdAt.Add(time.Minute)},
+		}
+
+		page := toCommentsPage(rows, 1, convertToReply, func(row sqlc2.GetCommentRepliesRow) domain.Cursor {
+			return domain.Cursor{CreatedAt: row.CreatedAt, ID: row.ID}
+		})
+
+		parentID := int64(117)
+		assert.Equal(t, []domain.Comment{
+			{
+				ID:           1,
+				PostID:       42,
+				UserID:       7,
+				ParentID:     &parentID,
+				Depth:        1,
+				Content:      "first",
+				RepliesCount: 2,
+				CreatedAt:    createdAt.String(),
+				UpdatedAt:    createdAt.String(),
+				User:         domain.User{ID: 7, Username: "arya"},
+				Reactions:    domain.Reactions{Total: 3, Counts: map[domain.ReactionType]int64{domain.ReactionTypeLike: 3}, ReactedByMe: true, Mine: []domain.ReactionType{domain.ReactionTypeLike}},
+			},
+		}, page.Comments)
+		assert.Equal(t, &domain.Cursor{CreatedAt: createdAt, ID: 1}, page.NextCursor)
+	})
+
+	t.Run("it should call correct query on delete", func(t *testing.T) {
+		query := `-- name: DeleteCommentByID :execrows
+DELETE
+FROM comments
+WHERE id = $1
+`
+		commentID := int64(42)
+		ctx := context.Background()
+		mockDB := sqlc2.NewMockDBTX(t)
+
+		mockDB.On(
+			"ExecContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(&FakeSqlResult{
+			InsertID:      0,
+			InsertError:   nil,
+			AffectedRows:  1,
+			AffectedError: nil,
+		}, nil)
+
+		store := CommentStore{
+			queries: sqlc2.New(mockDB),
+		}
+
+		err := store.Delete(ctx, commentID)
+
+		assert.NoError(t, err)
+		mockDB.AssertCalled(t, "ExecContext", mock.Anything, query, commentID)
+		mockDB.AssertNumberOfCalls(t, "ExecContext", 1)
+	})
+
+	t.Run("it returns NotFound error if no rows affected when delete", func(t *testing.T) {
+		ctx := context.Background()
+		mockDB := sqlc2.NewMockDBTX(t)
+
+		mockDB.On(
+			"ExecContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(&FakeSqlResult{
+			InsertID:      0,
+			InsertError:   nil,
+			AffectedRows:  0,
+			AffectedError: nil,
+		}, nil)
+
+		store := CommentStore{
+			queries: sqlc2.New(mockDB),
+		}
+
+		err := store.Delete(ctx, 42)
+
+		assert.EqualError(t, err, domain.ErrNotFound.Error())
+	})
+}
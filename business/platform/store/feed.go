@@ -2,26 +2,59 @@ package store
 
 import (
 	"context"
+	"database/sql"
 	"github.com/sergdort/Social/business/domain"
 	"github.com/sergdort/Social/business/platform/store/sqlc"
 	"github.com/sergdort/Social/foundation/slices"
+	"time"
 )
 
 type FeedStore struct {
 	queries *sqlc.Queries
 }
 
-func (s *FeedStore) GetUserFeed(ctx context.Context, userId int64, q domain.PaginatedFeedQuery) ([]domain.PostWithMetadata, error) {
-	feed, err := s.queries.GetUserFeed(ctx, sqlc.GetUserFeedParams{
+func (s *FeedStore) GetUserFeed(ctx context.Context, userId int64, q domain.PaginatedFeedQuery) (domain.FeedPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	since, until := q.TimeWindow()
+	params := sqlc.GetUserFeedParams{
 		UserID:  userId,
-		Limit:   int32(q.Limit),
-		Offset:  int32(q.Offset),
-		Column4: q.Search,
+		Search:  q.Search,
 		Tags:    q.Tags,
-	})
+		Since:   toNullTime(since),
+		Until:   toNullTime(until),
+		SortAsc: q.IsAscending(),
+		// Fetch one extra row to know whether there is a next page.
+		PageLimit:  int32(q.Limit + 1),
+		PageOffset: int32(q.Offset),
+	}
+	if q.Cursor != nil {
+		params.CursorCreatedAt = sql.NullTime{Time: q.Cursor.CreatedAt, Valid: true}
+		params.CursorID = sql.NullInt64{Int64: q.Cursor.ID, Valid: true}
+	}
+
+	feed, err := s.queries.GetUserFeed(ctx, params)
 	if err != nil {
-		return nil, err
+		return domain.FeedPage{}, err
+	}
+
+	var next *domain.Cursor
+	if len(feed) > q.Limit {
+		feed = feed[:q.Limit]
+		last := feed[len(feed)-1]
+		next = &domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return domain.FeedPage{
+		Posts:      slices.Map(feed, convertToPostWithMetadata),
+		NextCursor: next,
+	}, nil
+}
+
+func toNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
 	}
-	postsWithMetadata := slices.Map(feed, convertToPostWithMetadata)
-	return postsWithMetadata, nil
+	return sql.NullTime{Time: *t, Valid: true}
 }
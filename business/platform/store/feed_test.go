@@ -0,0 +1,244 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/sergdort/Social/business/domain"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestFeedStore(t *testing.T) {
+
+	t.Run("it should query own posts and posts of followed users", func(t *testing.T) {
+		query := `-- name: GetUserFeed :many
+SELECT p.id,
+       p.user_id,
+       p.title,
+       p.content,
+       p.created_at,
+       p.tags,
+       COUNT(c.id) AS comments_count,
+       (SELECT COALESCE(jsonb_object_agg(r.type, r.count), '{}')
+        FROM (SELECT pr.type, COUNT(*) AS count
+              FROM post_reactions pr
+              WHERE pr.post_id = p.id
+              GROUP BY pr.type) r)::jsonb AS reaction_counts,
+       ARRAY(SELECT pr.type
+             FROM post_reactions pr
+             WHERE pr.post_id = p.id
+               AND pr.user_id = $1
+             ORDER BY pr.type)::varchar[] AS my_reactions,
+       u.username
+FROM posts p
+         LEFT JOIN comments c ON c.post_id = p.id
+         LEFT JOIN users u ON p.user_id = u.id
+WHERE (p.user_id = $1 OR p.user_id IN (SELECT f.follower_id
+                                             FROM followers f
+                                             WHERE f.user_id = $1))
+  AND ($2::text = '' OR LOWER(p.title) LIKE LOWER('%' || $2 || '%') OR
+       LOWER(p.content) LIKE LOWER('%' || $2 || '%'))
+  AND (p.tags @> $3::varchar[] OR $3::varchar[] = '{}')
+  AND ($4::timestamptz IS NULL OR p.created_at >= $4::timestamptz)
+  AND ($5::timestamptz IS NULL OR p.created_at <= $5::timestamptz)
+  AND ($6::timestamptz IS NULL OR
+       ($7::bool AND
+        (p.created_at, p.id) > ($6::timestamptz, $8::bigint)) OR
+       (NOT $7::bool AND
+        (p.created_at, p.id) < ($6::timestamptz, $8::bigint)))
+GROUP BY p.id, u.username
+ORDER BY CASE WHEN $7::bool THEN p.created_at END,
+         CASE WHEN $7::bool THEN p.id END,
+         p.created_at DESC,
+         p.id DESC
+LIMIT $9 OFFSET $10
+`
+		userID := int64(42)
+		ctx := context.Background()
+		mockDB := sqlc2.NewMockDBTX(t)
+
+		fakeError := errors.New("something went wrong")
+		mockDB.On(
+			"QueryContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(nil, fakeError)
+
+		store := FeedStore{
+			queries: sqlc2.New(mockDB),
+		}
+
+		_, _ = store.GetUserFeed(ctx, userID, domain.PaginatedFeedQuery{
+			Limit:  20,
+			Offset: 10,
+			SortBy: "desc",
+			Search: "winter",
+			Tags:   []string{"Stark"},
+		})
+
+		mockDB.AssertCalled(
+			t,
+			"QueryContext",
+			mock.Anything,
+			query,
+			userID,
+			"winter",
+			mock.Anything,
+			sql.NullTime{},
+			sql.NullTime{},
+			sql.NullTime{},
+			false,
+			sql.NullInt64{},
+			int32(21),
+			int32(10),
+		)
+		mockDB.AssertNumberOfCalls(t, "QueryContext", 1)
+	})
+
+	t.Run("it should return error if it fails to query the feed", func(t *testing.T) {
+		ctx := context.Background()
+		mockDB := sqlc2.NewMockDBTX(t)
+
+		fakeError := errors.New("something went wrong")
+		mockDB.On(
+			"QueryContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(nil, fakeError)
+
+		store := FeedStore{
+			queries: sqlc2.New(mockDB),
+		}
+
+		page, err := store.GetUserFeed(ctx, 42, domain.PaginatedFeedQuery{Limit: 20})
+
+		assert.Nil(t, page.Posts)
+		assert.Nil(t, page.NextCursor)
+		assert.EqualError(t, err, fakeError.Error())
+	})
+
+	t.Run("it should use keyset predicates when paginating with a cursor", func(t *testing.T) {
+		userID := int64(42)
+		createdAt := time.Date(2025, 3, 19, 10, 8, 25, 0, time.UTC)
+		ctx := context.Background()
+		mockDB := sqlc2.NewMockDBTX(t)
+
+		fakeError := errors.New("something went wrong")
+		mockDB.On(
+			"QueryContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(nil, fakeError)
+
+		store := FeedStore{
+			queries: sqlc2.New(mockDB),
+		}
+
+		_, _ = store.GetUserFeed(ctx, userID, domain.PaginatedFeedQuery{
+			Limit:  20,
+			Cursor: &domain.Cursor{CreatedAt: createdAt, ID: 117},
+		})
+
+		mockDB.AssertCalled(
+			t,
+			"QueryContext",
+			mock.Anything,
+			mock.Anything,
+			userID,
+			"",
+			mock.Anything,
+			sql.NullTime{},
+			sql.NullTime{},
+			sql.NullTime{Time: createdAt, Valid: true},
+			false,
+			sql.NullInt64{Int64: 117, Valid: true},
+			int32(21),
+			int32(0),
+		)
+	})
+
+	t.Run("it should filter by time window and sort ascending", func(t *testing.T) {
+		userID := int64(42)
+		ctx := context.Background()
+		mockDB := sqlc2.NewMockDBTX(t)
+
+		fakeError := errors.New("something went wrong")
+		mockDB.On(
+			"QueryContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(nil, fakeError)
+
+		store := FeedStore{
+			queries: sqlc2.New(mockDB),
+		}
+
+		_, _ = store.GetUserFeed(ctx, userID, domain.PaginatedFeedQuery{
+			Limit:  20,
+			SortBy: "asc",
+			Since:  "2025-03-01 00:00:00",
+			Until:  "2025-03-31 23:59:59",
+		})
+
+		mockDB.AssertCalled(
+			t,
+			"QueryContext",
+			mock.Anything,
+			mock.Anything,
+			userID,
+			"",
+			mock.Anything,
+			sql.NullTime{Time: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+			sql.NullTime{Time: time.Date(2025, 3, 31, 23, 59, 59, 0, time.UTC), Valid: true},
+			sql.NullTime{},
+			true,
+			sql.NullInt64{},
+			int32(21),
+			int32(0),
+		)
+	})
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"errors"
+	"github.com/sergdort/Social/business/domain"
 	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -135,6 +136,6 @@ WHERE user_id = $1
 
 		err := store.Unfollow(ctx, userID, followerID)
 
-		assert.EqualError(t, err, ErrNotFound.Error())
+		assert.EqualError(t, err, domain.ErrNotFound.Error())
 	})
 }
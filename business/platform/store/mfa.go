@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/sergdort/Social/business/domain"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+	"time"
+)
+
+type MFAStore struct {
+	db      *sql.DB
+	queries *sqlc2.Queries
+}
+
+func (s *MFAStore) GetTOTP(ctx context.Context, userID int64) (*domain.TOTPSecret, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	row, err := s.queries.GetUserTOTP(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, domain.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	secret := &domain.TOTPSecret{
+		UserID:       row.UserID,
+		Secret:       row.Secret,
+		LastUsedStep: row.LastUsedStep,
+	}
+	if row.ConfirmedAt.Valid {
+		secret.ConfirmedAt = &row.ConfirmedAt.Time
+	}
+	return secret, nil
+}
+
+func (s *MFAStore) SaveTOTP(ctx context.Context, userID int64, secret string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.UpsertUserTOTP(ctx, sqlc2.UpsertUserTOTPParams{
+		UserID: userID,
+		Secret: secret,
+	})
+}
+
+func (s *MFAStore) ConfirmTOTP(ctx context.Context, userID int64, recoveryCodes []string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	codes := make([][]byte, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		codes[i] = []byte(code)
+	}
+
+	return withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		queries := s.queries.WithTx(tx)
+
+		if err := queries.ConfirmUserTOTP(ctx, userID); err != nil {
+			return err
+		}
+		if err := queries.DeleteUserRecoveryCodes(ctx, userID); err != nil {
+			return err
+		}
+		return queries.CreateUserRecoveryCodes(ctx, sqlc2.CreateUserRecoveryCodesParams{
+			UserID: userID,
+			Codes:  codes,
+		})
+	})
+}
+
+func (s *MFAStore) DeleteTOTP(ctx context.Context, userID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		queries := s.queries.WithTx(tx)
+
+		if err := queries.DeleteUserRecoveryCodes(ctx, userID); err != nil {
+			return err
+		}
+		return queries.DeleteUserTOTP(ctx, userID)
+	})
+}
+
+func (s *MFAStore) UseTOTPStep(ctx context.Context, userID int64, step int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.queries.UseUserTOTPStep(ctx, sqlc2.UseUserTOTPStepParams{
+		Step:   step,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *MFAStore) UseRecoveryCode(ctx context.Context, userID int64, code string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.queries.UseUserRecoveryCode(ctx, sqlc2.UseUserRecoveryCodeParams{
+		UserID: userID,
+		Code:   []byte(code),
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *MFAStore) CreateChallenge(ctx context.Context, token string, userID int64, expiry time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.CreateMFAChallenge(ctx, sqlc2.CreateMFAChallengeParams{
+		Token:  []byte(token),
+		UserID: userID,
+		Expiry: expiry,
+	})
+}
+
+func (s *MFAStore) AttemptChallenge(ctx context.Context, token string) (*domain.MFAChallengeAttempt, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	row, err := s.queries.AttemptMFAChallenge(ctx, []byte(token))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, domain.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &domain.MFAChallengeAttempt{
+		UserID:   row.UserID,
+		Expiry:   row.Expiry,
+		Attempts: int(row.Attempts),
+	}, nil
+}
+
+func (s *MFAStore) DeleteChallenge(ctx context.Context, token string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.DeleteMFAChallenge(ctx, []byte(token))
+}
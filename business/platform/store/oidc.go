@@ -0,0 +1,120 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/sergdort/Social/business/domain"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+)
+
+type OIDCStore struct {
+	db      *sql.DB
+	queries *sqlc2.Queries
+	users   *UserStore
+}
+
+// SaveState stores the state of a login. Abandoned logins are pruned on the
+// way.
+func (s *OIDCStore) SaveState(ctx context.Context, hash string, state domain.OIDCLoginState) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.queries.DeleteExpiredOIDCLoginStates(ctx); err != nil {
+		return err
+	}
+
+	return s.queries.CreateOIDCLoginState(ctx, sqlc2.CreateOIDCLoginStateParams{
+		State:        []byte(hash),
+		Provider:     state.Provider,
+		Nonce:        state.Nonce,
+		CodeVerifier: state.CodeVerifier,
+		Expiry:       state.Expiry,
+	})
+}
+
+func (s *OIDCStore) TakeState(ctx context.Context, hash string) (*domain.OIDCLoginState, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	row, err := s.queries.TakeOIDCLoginState(ctx, []byte(hash))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, domain.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &domain.OIDCLoginState{
+		Provider:     row.Provider,
+		Nonce:        row.Nonce,
+		CodeVerifier: row.CodeVerifier,
+		Expiry:       row.Expiry,
+	}, nil
+}
+
+func (s *OIDCStore) GetIdentity(ctx context.Context, provider string, subject string) (*domain.UserIdentity, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	row, err := s.queries.GetUserIdentity(ctx, sqlc2.GetUserIdentityParams{
+		Provider: provider,
+		Subject:  subject,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, domain.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &domain.UserIdentity{
+		Provider: row.Provider,
+		Subject:  row.Subject,
+		UserID:   row.UserID,
+		Email:    row.Email,
+	}, nil
+}
+
+func (s *OIDCStore) LinkIdentity(ctx context.Context, identity domain.UserIdentity) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return createUserIdentity(ctx, s.queries, identity)
+}
+
+func (s *OIDCStore) CreateUser(ctx context.Context, user *domain.User, identity *domain.UserIdentity) error {
+	return withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		if err := s.users.Create(ctx, tx, user); err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+		defer cancel()
+
+		queries := s.queries.WithTx(tx)
+		err := queries.SetUserActive(ctx, sqlc2.SetUserActiveParams{
+			ID:       user.ID,
+			IsActive: true,
+		})
+		if err != nil {
+			return err
+		}
+
+		identity.UserID = user.ID
+		return createUserIdentity(ctx, queries, *identity)
+	})
+}
+
+func createUserIdentity(ctx context.Context, queries *sqlc2.Queries, identity domain.UserIdentity) error {
+	return queries.CreateUserIdentity(ctx, sqlc2.CreateUserIdentityParams{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		UserID:   identity.UserID,
+		Email:    identity.Email,
+	})
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"github.com/sergdort/Social/business/domain"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+	"github.com/sergdort/Social/foundation/slices"
+	"time"
+)
+
+type OutboxStore struct {
+	queries *sqlc2.Queries
+}
+
+func (s *OutboxStore) Enqueue(ctx context.Context, msg domain.OutboxMessage) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.CreateOutboxMessage(ctx, sqlc2.CreateOutboxMessageParams{
+		Kind:    msg.Kind,
+		Payload: msg.Payload,
+	})
+}
+
+func (s *OutboxStore) Claim(ctx context.Context, limit int, now time.Time, leasedUntil time.Time) ([]domain.OutboxMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.queries.ClaimOutboxMessages(ctx, sqlc2.ClaimOutboxMessagesParams{
+		LeasedUntil: leasedUntil,
+		Now:         now,
+		BatchSize:   int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Map(rows, func(row sqlc2.ClaimOutboxMessagesRow) domain.OutboxMessage {
+		return domain.OutboxMessage{
+			ID:        row.ID,
+			Kind:      row.Kind,
+			Payload:   row.Payload,
+			Attempts:  int(row.Attempts),
+			CreatedAt: row.CreatedAt,
+		}
+	}), nil
+}
+
+func (s *OutboxStore) MarkSent(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.MarkOutboxMessageSent(ctx, id)
+}
+
+func (s *OutboxStore) Retry(ctx context.Context, id int64, at time.Time, lastErr string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.RetryOutboxMessage(ctx, sqlc2.RetryOutboxMessageParams{
+		ID:          id,
+		AvailableAt: at,
+		LastError:   sql.NullString{String: lastErr, Valid: true},
+	})
+}
+
+func (s *OutboxStore) Release(ctx context.Context, id int64, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.ReleaseOutboxMessage(ctx, sqlc2.ReleaseOutboxMessageParams{
+		ID:          id,
+		AvailableAt: at,
+	})
+}
+
+func (s *OutboxStore) MarkDead(ctx context.Context, id int64, lastErr string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.MarkOutboxMessageDead(ctx, sqlc2.MarkOutboxMessageDeadParams{
+		ID:        id,
+		LastError: sql.NullString{String: lastErr, Valid: true},
+	})
+}
+
+func (s *OutboxStore) CountPending(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.CountPendingOutboxMessages(ctx)
+}
+
+// createOutboxMessage queues the message in the transaction of the domain
+// change it belongs to.
+func createOutboxMessage(ctx context.Context, tx *sql.Tx, queries *sqlc2.Queries, msg domain.OutboxMessage) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return queries.WithTx(tx).CreateOutboxMessage(ctx, sqlc2.CreateOutboxMessageParams{
+		Kind:    msg.Kind,
+		Payload: msg.Payload,
+	})
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestOutboxStore(t *testing.T) {
+
+	t.Run("it should call correct query on retry", func(t *testing.T) {
+		query := `-- name: RetryOutboxMessage :exec
+UPDATE outbox_messages
+SET available_at = $2,
+    last_error   = $3
+WHERE id = $1
+`
+		id := int64(42)
+		at := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+		ctx := context.Background()
+		mockDB := sqlc2.NewMockDBTX(t)
+
+		mockDB.On(
+			"ExecContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(&FakeSqlResult{AffectedRows: 1}, nil)
+
+		store := OutboxStore{
+			queries: sqlc2.New(mockDB),
+		}
+
+		err := store.Retry(ctx, id, at, "boom")
+
+		assert.NoError(t, err)
+		mockDB.AssertCalled(t, "ExecContext", mock.Anything, query, id, at, sql.NullString{String: "boom", Valid: true})
+		mockDB.AssertNumberOfCalls(t, "ExecContext", 1)
+	})
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/sergdort/Social/business/domain"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+	"github.com/sergdort/Social/foundation/slices"
+	"time"
+)
+
+type PersonalTokenStore struct {
+	queries *sqlc2.Queries
+}
+
+func (s *PersonalTokenStore) Create(ctx context.Context, token *domain.PersonalToken, hash string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	row, err := s.queries.CreatePersonalAccessToken(ctx, sqlc2.CreatePersonalAccessTokenParams{
+		UserID: token.UserID,
+		Name:   token.Name,
+		Token:  []byte(hash),
+		Prefix: token.Prefix,
+		Scopes: slices.Map(token.Scopes, func(scope domain.Permission) string {
+			return string(scope)
+		}),
+		Expiry: token.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	token.ID = row.ID
+	token.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (s *PersonalTokenStore) ListByUser(ctx context.Context, userID int64) ([]domain.PersonalToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.queries.ListPersonalAccessTokensByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Map(rows, toPersonalToken), nil
+}
+
+func (s *PersonalTokenStore) GetByToken(ctx context.Context, hash string) (*domain.PersonalToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	row, err := s.queries.GetPersonalAccessTokenByToken(ctx, []byte(hash))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, domain.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	token := toPersonalToken(row)
+	return &token, nil
+}
+
+func (s *PersonalTokenStore) Touch(ctx context.Context, id int64, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.TouchPersonalAccessToken(ctx, sqlc2.TouchPersonalAccessTokenParams{
+		UsedAt: sql.NullTime{Time: at, Valid: true},
+		ID:     id,
+	})
+}
+
+func (s *PersonalTokenStore) Delete(ctx context.Context, userID int64, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.queries.DeletePersonalAccessToken(ctx, sqlc2.DeletePersonalAccessTokenParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func toPersonalToken(row sqlc2.PersonalAccessToken) domain.PersonalToken {
+	return domain.PersonalToken{
+		ID:     row.ID,
+		UserID: row.UserID,
+		Name:   row.Name,
+		Prefix: row.Prefix,
+		Scopes: slices.Map(row.Scopes, func(scope string) domain.Permission {
+			return domain.Permission(scope)
+		}),
+		ExpiresAt:  row.Expiry,
+		LastUsedAt: fromNullTime(row.LastUsedAt),
+		CreatedAt:  row.CreatedAt,
+	}
+}
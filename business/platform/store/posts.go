@@ -94,7 +94,15 @@ func (s *PostStore) Update(ctx context.Context, post *domain.Post) error {
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return domain.ErrNotFound
+			// Either the post is gone or its version moved on since it was read.
+			exists, err := s.queries.PostExists(ctx, post.ID)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return domain.ErrNotFound
+			}
+			return domain.ErrEditConflict
 		default:
 			return err
 		}
@@ -120,5 +128,6 @@ func convertToPostWithMetadata(feedRow sqlc.GetUserFeedRow) domain.PostWithMetad
 			},
 		},
 		CommentsCount: feedRow.CommentsCount,
+		Reactions:     toReactions(feedRow.ReactionCounts, feedRow.MyReactions),
 	}
 }
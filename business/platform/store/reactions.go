@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sergdort/Social/business/domain"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+	"github.com/sergdort/Social/foundation/slices"
+)
+
+type ReactionStore struct {
+	queries *sqlc2.Queries
+}
+
+func (s *ReactionStore) AddToPost(ctx context.Context, postID int64, userID int64, reaction domain.ReactionType) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.CreatePostReaction(ctx, sqlc2.CreatePostReactionParams{
+		PostID: postID,
+		UserID: userID,
+		Type:   string(reaction),
+	})
+}
+
+func (s *ReactionStore) RemoveFromPost(ctx context.Context, postID int64, userID int64, reaction domain.ReactionType) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.queries.DeletePostReaction(ctx, sqlc2.DeletePostReactionParams{
+		PostID: postID,
+		UserID: userID,
+		Type:   string(reaction),
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *ReactionStore) AddToComment(ctx context.Context, commentID int64, userID int64, reaction domain.ReactionType) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.CreateCommentReaction(ctx, sqlc2.CreateCommentReactionParams{
+		CommentID: commentID,
+		UserID:    userID,
+		Type:      string(reaction),
+	})
+}
+
+func (s *ReactionStore) RemoveFromComment(ctx context.Context, commentID int64, userID int64, reaction domain.ReactionType) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.queries.DeleteCommentReaction(ctx, sqlc2.DeleteCommentReactionParams{
+		CommentID: commentID,
+		UserID:    userID,
+		Type:      string(reaction),
+	})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// toReactions builds the reactions summary out of the reaction_counts json
+// object and the my_reactions array selected by the feed and comments
+// queries.
+func toReactions(counts json.RawMessage, mine []string) domain.Reactions {
+	reactions := domain.Reactions{
+		Counts: map[domain.ReactionType]int64{},
+		Mine: slices.Map(mine, func(r string) domain.ReactionType {
+			return domain.ReactionType(r)
+		}),
+	}
+	if len(counts) > 0 {
+		if err := json.Unmarshal(counts, &reactions.Counts); err != nil {
+			reactions.Counts = map[domain.ReactionType]int64{}
+		}
+	}
+	for _, count := range reactions.Counts {
+		reactions.Total += count
+	}
+	reactions.ReactedByMe = len(reactions.Mine) > 0
+
+	return reactions
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sergdort/Social/business/domain"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestReactionStore(t *testing.T) {
+
+	t.Run("it should call correct query on post reaction", func(t *testing.T) {
+		query := `-- name: CreatePostReaction :exec
+INSERT INTO post_reactions (post_id, user_id, type)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING
+`
+		postID := int64(42)
+		userID := int64(43)
+		ctx := context.Background()
+		mockDB := sqlc2.NewMockDBTX(t)
+
+		mockDB.On(
+			"ExecContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(&FakeSqlResult{
+			InsertID:      0,
+			InsertError:   nil,
+			AffectedRows:  1,
+			AffectedError: nil,
+		}, nil)
+
+		store := ReactionStore{
+			queries: sqlc2.New(mockDB),
+		}
+
+		err := store.AddToPost(ctx, postID, userID, domain.ReactionTypeLike)
+
+		assert.NoError(t, err)
+		mockDB.AssertCalled(t, "ExecContext", mock.Anything, query, postID, userID, "like")
+		mockDB.AssertNumberOfCalls(t, "ExecContext", 1)
+	})
+
+	t.Run("it returns NotFound error if no rows affected when removing a comment reaction", func(t *testing.T) {
+		ctx := context.Background()
+		mockDB := sqlc2.NewMockDBTX(t)
+
+		mockDB.On(
+			"ExecContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(&FakeSqlResult{
+			InsertID:      0,
+			InsertError:   nil,
+			AffectedRows:  0,
+			AffectedError: nil,
+		}, nil)
+
+		store := ReactionStore{
+			queries: sqlc2.New(mockDB),
+		}
+
+		err := store.RemoveFromComment(ctx, 42, 43, domain.ReactionTypeLove)
+
+		assert.EqualError(t, err, domain.ErrNotFound.Error())
+	})
+
+	t.Run("it should aggregate reaction counts", func(t *testing.T) {
+		reactions := toReactions(json.RawMessage(`{"like": 3, "wow": 1}`), []string{"like"})
+
+		assert.Equal(t, domain.Reactions{
+			Total: 4,
+			Counts: map[domain.ReactionType]int64{
+				domain.ReactionTypeLike: 3,
+				domain.ReactionTypeWow:  1,
+			},
+			ReactedByMe: true,
+			Mine:        []domain.ReactionType{domain.ReactionTypeLike},
+		}, reactions)
+	})
+
+	t.Run("it should report no reactions", func(t *testing.T) {
+		reactions := toReactions(json.RawMessage(`{}`), nil)
+
+		assert.Equal(t, int64(0), reactions.Total)
+		assert.Empty(t, reactions.Counts)
+		assert.False(t, reactions.ReactedByMe)
+		assert.Empty(t, reactions.Mine)
+	})
+}
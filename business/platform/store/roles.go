@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/sergdort/Social/business/domain"
 	"github.com/sergdort/Social/business/platform/store/sqlc"
+	"github.com/sergdort/Social/foundation/slices"
 )
 
 type RolesStore struct {
@@ -22,3 +23,34 @@ func (s *RolesStore) GetByRoleType(ctx context.Context, name domain.RoleType) (*
 		Level:       int64(row.Level),
 	}, nil
 }
+
+func (s *RolesStore) GetPermissions(ctx context.Context, roleID int64) ([]domain.Permission, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	names, err := s.queries.GetPermissionsByRoleID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Map(names, func(name string) domain.Permission {
+		return domain.Permission(name)
+	}), nil
+}
+
+func (s *RolesStore) List(ctx context.Context) ([]domain.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.queries.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Map(rows, func(row sqlc.Role) domain.Role {
+		return domain.Role{
+			ID:          row.ID,
+			Name:        row.Name,
+			Description: row.Description.String,
+			Level:       int64(row.Level),
+		}
+	}), nil
+}
@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/sergdort/Social/business/domain"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+	"github.com/sergdort/Social/foundation/slices"
+	"time"
+)
+
+type SessionStore struct {
+	db      *sql.DB
+	queries *sqlc2.Queries
+}
+
+func (s *SessionStore) Create(ctx context.Context, session *domain.Session) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.CreateSession(ctx, sqlc2.CreateSessionParams{
+		ID:         session.ID,
+		UserID:     session.UserID,
+		UserAgent:  session.UserAgent,
+		Ip:         session.IP,
+		Expiry:     session.ExpiresAt,
+		LastUsedAt: session.LastUsedAt,
+		CreatedAt:  session.CreatedAt,
+	})
+}
+
+func (s *SessionStore) Get(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	row, err := s.queries.GetSession(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, domain.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	session := toSession(row)
+	return &session, nil
+}
+
+func (s *SessionStore) ListByUser(ctx context.Context, userID int64) ([]domain.Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.queries.ListActiveSessionsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Map(rows, toSession), nil
+}
+
+func (s *SessionStore) Touch(ctx context.Context, id uuid.UUID, at time.Time, ip string) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.TouchSession(ctx, sqlc2.TouchSessionParams{
+		UsedAt: at,
+		Ip:     ip,
+		ID:     id,
+	})
+}
+
+func (s *SessionStore) Revoke(ctx context.Context, userID int64, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		queries := s.queries.WithTx(tx)
+
+		rows, err := queries.RevokeUserSession(ctx, sqlc2.RevokeUserSessionParams{
+			ID:     id,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return domain.ErrNotFound
+		}
+
+		// Deleted rather than revoked, presenting a revoked refresh token
+		// would pass for reuse.
+		return queries.DeleteRefreshTokenFamily(ctx, id)
+	})
+}
+
+func (s *SessionStore) RevokeOthers(ctx context.Context, userID int64, keep uuid.UUID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var revoked int64
+	err := withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		queries := s.queries.WithTx(tx)
+
+		rows, err := queries.RevokeOtherUserSessions(ctx, sqlc2.RevokeOtherUserSessionsParams{
+			UserID: userID,
+			ID:     keep,
+		})
+		if err != nil {
+			return err
+		}
+		revoked = rows
+
+		return queries.DeleteOtherUserRefreshTokens(ctx, sqlc2.DeleteOtherUserRefreshTokensParams{
+			UserID:   userID,
+			FamilyID: keep,
+		})
+	})
+	return revoked, err
+}
+
+func toSession(row sqlc2.Session) domain.Session {
+	return domain.Session{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		UserAgent:  row.UserAgent,
+		IP:         row.Ip,
+		CreatedAt:  row.CreatedAt,
+		LastUsedAt: row.LastUsedAt,
+		ExpiresAt:  row.Expiry,
+		RevokedAt:  fromNullTime(row.RevokedAt),
+	}
+}
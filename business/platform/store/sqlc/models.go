@@ -6,14 +6,40 @@ package sqlc
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+type AuditEvent struct {
+	ID         int64
+	ActorID    sql.NullInt64
+	Action     string
+	TargetType string
+	TargetID   sql.NullInt64
+	Ip         string
+	UserAgent  string
+	TraceID    string
+	Metadata   json.RawMessage
+	CreatedAt  time.Time
+}
+
 type Comment struct {
-	ID        int64
-	PostID    int64
+	ID              int64
+	PostID          int64
+	UserID          int64
+	Content         sql.NullString
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ParentCommentID sql.NullInt64
+	Depth           int32
+}
+
+type CommentReaction struct {
+	CommentID int64
 	UserID    int64
-	Content   sql.NullString
+	Type      string
 	CreatedAt time.Time
 }
 
@@ -23,6 +49,57 @@ type Follower struct {
 	CreatedAt  time.Time
 }
 
+type MfaChallenge struct {
+	Token    []byte
+	UserID   int64
+	Expiry   time.Time
+	Attempts int32
+}
+
+type OidcLoginState struct {
+	State        []byte
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	Expiry       time.Time
+}
+
+type OutboxMessage struct {
+	ID          int64
+	Kind        string
+	Payload     json.RawMessage
+	Status      string
+	Attempts    int32
+	LastError   sql.NullString
+	AvailableAt time.Time
+	CreatedAt   time.Time
+	ProcessedAt sql.NullTime
+}
+
+type PasswordReset struct {
+	Token  []byte
+	UserID int64
+	Expiry time.Time
+}
+
+type Permission struct {
+	ID          int64
+	Name        string
+	Description sql.NullString
+}
+
+type PersonalAccessToken struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	Token      []byte
+	Prefix     string
+	Scopes     []string
+	Expiry     time.Time
+	LastUsedAt sql.NullTime
+	CreatedAt  time.Time
+}
+
 type Post struct {
 	ID        int64
 	Title     string
@@ -34,6 +111,28 @@ type Post struct {
 	Version   sql.NullInt32
 }
 
+type PostReaction struct {
+	PostID    int64
+	UserID    int64
+	Type      string
+	CreatedAt time.Time
+}
+
+type RefreshToken struct {
+	ID        int64
+	Token     []byte
+	UserID    int64
+	FamilyID  uuid.UUID
+	Expiry    time.Time
+	RevokedAt sql.NullTime
+	CreatedAt time.Time
+}
+
+type RevokedToken struct {
+	Jti    string
+	Expiry time.Time
+}
+
 type Role struct {
 	ID          int64
 	Name        string
@@ -41,14 +140,45 @@ type Role struct {
 	Description sql.NullString
 }
 
+type RolePermission struct {
+	RoleID       int64
+	PermissionID int64
+}
+
+type Session struct {
+	ID         uuid.UUID
+	UserID     int64
+	UserAgent  string
+	Ip         string
+	Expiry     time.Time
+	LastUsedAt time.Time
+	RevokedAt  sql.NullTime
+	CreatedAt  time.Time
+}
+
 type User struct {
-	ID        int64
+	ID          int64
+	Email       string
+	Username    string
+	Password    []byte
+	CreatedAt   time.Time
+	IsActive    bool
+	RoleID      int32
+	BannedAt    sql.NullTime
+	ActivatedAt sql.NullTime
+	DisplayName string
+	Bio         string
+	Location    string
+	Website     string
+	AvatarUrl   string
+}
+
+type UserIdentity struct {
+	Provider  string
+	Subject   string
+	UserID    int64
 	Email     string
-	Username  string
-	Password  []byte
 	CreatedAt time.Time
-	IsActive  bool
-	RoleID    int32
 }
 
 type UserInvitation struct {
@@ -56,3 +186,18 @@ type UserInvitation struct {
 	UserID int64
 	Expiry time.Time
 }
+
+type UserRecoveryCode struct {
+	ID     int64
+	UserID int64
+	Code   []byte
+	UsedAt sql.NullTime
+}
+
+type UserTotp struct {
+	UserID       int64
+	Secret       string
+	ConfirmedAt  sql.NullTime
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
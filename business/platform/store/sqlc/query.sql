@@ -8,22 +8,9 @@ WHERE user_id = $1
 INSERT INTO followers (user_id, follower_id)
 VALUES ($1, $2);
 
--- name: GetAllCommentsByPostID :many
-SELECT c.id,
-       c.post_id,
-       c.user_id,
-       c.content,
-       c.created_at,
-       u.username,
-       u.id
-FROM comments c
-         JOIN users u ON u.id = c.user_id
-WHERE c.post_id = $1
-ORDER BY c.created_at DESC;
-
 -- name: CreateComment :one
-INSERT INTO comments (post_id, user_id, content)
-VALUES ($1, $2, $3)
+INSERT INTO comments (post_id, user_id, content, parent_comment_id, depth)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING id, created_at;
 
 -- name: DeleteUserInvitationByUserID :exec
@@ -54,6 +41,9 @@ WHERE id = $3
   AND version = $4
 RETURNING version;
 
+-- name: PostExists :one
+SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1);
+
 -- name: DeletePostByID :execrows
 DELETE
 FROM posts
@@ -80,6 +70,13 @@ SELECT users.id,
        users.email,
        users.created_at,
        users.is_active,
+       users.banned_at,
+       users.activated_at,
+       users.display_name,
+       users.bio,
+       users.location,
+       users.website,
+       users.avatar_url,
        r.id          as role_id,
        r.name        as role_name,
        r.description as role_description,
@@ -89,17 +86,19 @@ FROM users
 WHERE users.id = $1;
 
 -- name: GetUserByEmail :one
-SELECT id, email, password, username, created_at, is_active
+SELECT id, email, password, username, created_at, is_active, banned_at, activated_at
 FROM users
 WHERE email = $1;
 
--- name: ActiveUserByInvitationToken :exec
+-- name: ActiveUserByInvitationToken :one
 UPDATE users u
-SET is_active = TRUE
+SET is_active    = TRUE,
+    activated_at = COALESCE(u.activated_at, NOW())
 FROM user_invitations i
 WHERE i.user_id = u.id
   AND i.token = $1
-  AND i.expiry > $2;
+  AND i.expiry > $2
+RETURNING u.id;
 
 -- name: GetPostByID :one
 SELECT id,
@@ -121,14 +120,584 @@ SELECT p.id,
        p.created_at,
        p.tags,
        COUNT(c.id) AS comments_count,
+       (SELECT COALESCE(jsonb_object_agg(r.type, r.count), '{}')
+        FROM (SELECT pr.type, COUNT(*) AS count
+              FROM post_reactions pr
+              WHERE pr.post_id = p.id
+              GROUP BY pr.type) r)::jsonb AS reaction_counts,
+       ARRAY(SELECT pr.type
+             FROM post_reactions pr
+             WHERE pr.post_id = p.id
+               AND pr.user_id = @user_id
+             ORDER BY pr.type)::varchar[] AS my_reactions,
        u.username
 FROM posts p
          LEFT JOIN comments c ON c.post_id = p.id
          LEFT JOIN users u ON p.user_id = u.id
-    OR p.user_id = $1
-WHERE p.user_id = $1
-  AND ($4 = '' OR LOWER(p.title) LIKE LOWER('%' || $4 || '%') OR LOWER(p.content) LIKE LOWER('%' || $4 || '%'))
-  AND (p.tags @> $5 OR $5 = '{}')
+WHERE (p.user_id = @user_id OR p.user_id IN (SELECT f.follower_id
+                                             FROM followers f
+                                             WHERE f.user_id = @user_id))
+  AND (@search::text = '' OR LOWER(p.title) LIKE LOWER('%' || @search || '%') OR
+       LOWER(p.content) LIKE LOWER('%' || @search || '%'))
+  AND (p.tags @> @tags::varchar[] OR @tags::varchar[] = '{}')
+  AND (sqlc.narg(since)::timestamptz IS NULL OR p.created_at >= sqlc.narg(since)::timestamptz)
+  AND (sqlc.narg(until)::timestamptz IS NULL OR p.created_at <= sqlc.narg(until)::timestamptz)
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL OR
+       (@sort_asc::bool AND
+        (p.created_at, p.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint)) OR
+       (NOT @sort_asc::bool AND
+        (p.created_at, p.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint)))
 GROUP BY p.id, u.username
-ORDER BY p.created_at DESC
-LIMIT $2 OFFSET $3;
\ No newline at end of file
+ORDER BY CASE WHEN @sort_asc::bool THEN p.created_at END,
+         CASE WHEN @sort_asc::bool THEN p.id END,
+         p.created_at DESC,
+         p.id DESC
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: GetCommentByID :one
+SELECT c.id,
+       c.post_id,
+       c.user_id,
+       c.content,
+       c.created_at,
+       c.updated_at,
+       c.parent_comment_id,
+       c.depth,
+       (SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id) AS replies_count,
+       u.username
+FROM comments c
+         JOIN users u ON u.id = c.user_id
+WHERE c.id = $1;
+
+-- name: GetCommentsByPostID :many
+SELECT c.id,
+       c.post_id,
+       c.user_id,
+       c.content,
+       c.created_at,
+       c.updated_at,
+       c.parent_comment_id,
+       c.depth,
+       (SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id) AS replies_count,
+       (SELECT COALESCE(jsonb_object_agg(r.type, r.count), '{}')
+        FROM (SELECT cr.type, COUNT(*) AS count
+              FROM comment_reactions cr
+              WHERE cr.comment_id = c.id
+              GROUP BY cr.type) r)::jsonb AS reaction_counts,
+       ARRAY(SELECT cr.type
+             FROM comment_reactions cr
+             WHERE cr.comment_id = c.id
+               AND cr.user_id = @viewer_id
+             ORDER BY cr.type)::varchar[] AS my_reactions,
+       u.username
+FROM comments c
+         JOIN users u ON u.id = c.user_id
+WHERE c.post_id = @post_id
+  AND c.parent_comment_id IS NULL
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL OR
+       (c.created_at, c.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT @page_limit;
+
+-- name: GetCommentReplies :many
+SELECT c.id,
+       c.post_id,
+       c.user_id,
+       c.content,
+       c.created_at,
+       c.updated_at,
+       c.parent_comment_id,
+       c.depth,
+       (SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id) AS replies_count,
+       (SELECT COALESCE(jsonb_object_agg(r.type, r.count), '{}')
+        FROM (SELECT cr.type, COUNT(*) AS count
+              FROM comment_reactions cr
+              WHERE cr.comment_id = c.id
+              GROUP BY cr.type) r)::jsonb AS reaction_counts,
+       ARRAY(SELECT cr.type
+             FROM comment_reactions cr
+             WHERE cr.comment_id = c.id
+               AND cr.user_id = @viewer_id
+             ORDER BY cr.type)::varchar[] AS my_reactions,
+       u.username
+FROM comments c
+         JOIN users u ON u.id = c.user_id
+WHERE c.parent_comment_id = @parent_id::bigint
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL OR
+       (c.created_at, c.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY c.created_at, c.id
+LIMIT @page_limit;
+
+-- name: GetCommentThread :many
+-- Walks the replies to a comment at every depth. The path of a reply is the
+-- ids of its ancestors below the comment and its own, so sorting by it lists
+-- every reply after its parent and the siblings oldest first.
+WITH RECURSIVE thread AS (SELECT c.id,
+                                 ARRAY [c.id] AS path
+                          FROM comments c
+                          WHERE c.parent_comment_id = @comment_id::bigint
+                          UNION ALL
+                          SELECT c.id,
+                                 t.path || c.id
+                          FROM comments c
+                                   JOIN thread t ON c.parent_comment_id = t.id)
+SELECT c.id,
+       c.post_id,
+       c.user_id,
+       c.content,
+       c.created_at,
+       c.updated_at,
+       c.parent_comment_id,
+       c.depth,
+       (SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id) AS replies_count,
+       (SELECT COALESCE(jsonb_object_agg(r.type, r.count), '{}')
+        FROM (SELECT cr.type, COUNT(*) AS count
+              FROM comment_reactions cr
+              WHERE cr.comment_id = c.id
+              GROUP BY cr.type) r)::jsonb AS reaction_counts,
+       ARRAY(SELECT cr.type
+             FROM comment_reactions cr
+             WHERE cr.comment_id = c.id
+               AND cr.user_id = @viewer_id
+             ORDER BY cr.type)::varchar[] AS my_reactions,
+       u.username
+FROM thread t
+         JOIN comments c ON c.id = t.id
+         JOIN users u ON u.id = c.user_id
+ORDER BY t.path
+LIMIT @thread_limit;
+
+-- name: UpdateComment :one
+UPDATE comments
+SET content    = $1,
+    updated_at = NOW()
+WHERE id = $2
+RETURNING updated_at;
+
+-- name: DeleteCommentByID :execrows
+DELETE
+FROM comments
+WHERE id = $1;
+
+-- name: CreatePostReaction :exec
+INSERT INTO post_reactions (post_id, user_id, type)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: DeletePostReaction :execrows
+DELETE
+FROM post_reactions
+WHERE post_id = $1
+  AND user_id = $2
+  AND type = $3;
+
+-- name: CreateCommentReaction :exec
+INSERT INTO comment_reactions (comment_id, user_id, type)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteCommentReaction :execrows
+DELETE
+FROM comment_reactions
+WHERE comment_id = $1
+  AND user_id = $2
+  AND type = $3;
+
+-- name: CreateOutboxMessage :exec
+INSERT INTO outbox_messages (kind, payload)
+VALUES ($1, $2);
+
+-- name: ClaimOutboxMessages :many
+UPDATE outbox_messages
+SET attempts     = attempts + 1,
+    available_at = @leased_until
+WHERE id IN (SELECT o.id
+             FROM outbox_messages o
+             WHERE o.status = 'pending'
+               AND o.available_at <= @now
+             ORDER BY o.id
+             LIMIT @batch_size FOR UPDATE SKIP LOCKED)
+RETURNING id, kind, payload, attempts, created_at;
+
+-- name: MarkOutboxMessageSent :exec
+UPDATE outbox_messages
+SET status       = 'sent',
+    processed_at = NOW()
+WHERE id = $1;
+
+-- name: RetryOutboxMessage :exec
+UPDATE outbox_messages
+SET available_at = $2,
+    last_error   = $3
+WHERE id = $1;
+
+-- name: ReleaseOutboxMessage :exec
+UPDATE outbox_messages
+SET attempts     = attempts - 1,
+    available_at = $2
+WHERE id = $1;
+
+-- name: MarkOutboxMessageDead :exec
+UPDATE outbox_messages
+SET status       = 'dead',
+    processed_at = NOW(),
+    last_error   = $2
+WHERE id = $1;
+
+-- name: CountPendingOutboxMessages :one
+SELECT COUNT(*)
+FROM outbox_messages
+WHERE status = 'pending';
+
+-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (token, user_id, family_id, expiry)
+VALUES ($1, $2, $3, $4);
+
+-- name: GetRefreshTokenByToken :one
+SELECT id, user_id, family_id, expiry, revoked_at
+FROM refresh_tokens
+WHERE token = $1;
+
+-- name: RevokeRefreshToken :execrows
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE id = $1
+  AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE family_id = $1
+  AND revoked_at IS NULL;
+
+-- name: CreateRevokedToken :exec
+INSERT INTO revoked_tokens (jti, expiry)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteExpiredRevokedTokens :exec
+DELETE
+FROM revoked_tokens
+WHERE expiry < NOW();
+
+-- name: IsTokenRevoked :one
+SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1);
+
+-- name: DeletePasswordResetsByUserID :exec
+DELETE
+FROM password_resets
+WHERE user_id = $1;
+
+-- name: CreatePasswordReset :exec
+INSERT INTO password_resets (token, user_id, expiry)
+VALUES ($1, $2, $3);
+
+-- name: ConsumePasswordReset :one
+DELETE
+FROM password_resets
+WHERE token = $1
+  AND expiry > $2
+RETURNING user_id;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET password = $2
+WHERE id = $1;
+
+-- name: RevokeUserRefreshTokens :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE user_id = $1
+  AND revoked_at IS NULL;
+
+-- name: GetUserTOTP :one
+SELECT user_id, secret, confirmed_at, last_used_step
+FROM user_totp
+WHERE user_id = $1;
+
+-- name: UpsertUserTOTP :exec
+INSERT INTO user_totp (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+    SET secret         = EXCLUDED.secret,
+        confirmed_at   = NULL,
+        last_used_step = 0,
+        created_at     = NOW();
+
+-- name: ConfirmUserTOTP :exec
+UPDATE user_totp
+SET confirmed_at = NOW()
+WHERE user_id = $1;
+
+-- name: UseUserTOTPStep :execrows
+UPDATE user_totp
+SET last_used_step = @step
+WHERE user_id = @user_id
+  AND last_used_step < @step;
+
+-- name: DeleteUserTOTP :exec
+DELETE
+FROM user_totp
+WHERE user_id = $1;
+
+-- name: DeleteUserRecoveryCodes :exec
+DELETE
+FROM user_recovery_codes
+WHERE user_id = $1;
+
+-- name: CreateUserRecoveryCodes :exec
+INSERT INTO user_recovery_codes (user_id, code)
+SELECT @user_id::bigint, UNNEST(@codes::bytea[]);
+
+-- name: UseUserRecoveryCode :execrows
+UPDATE user_recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1
+  AND code = $2
+  AND used_at IS NULL;
+
+-- name: CreateMFAChallenge :exec
+INSERT INTO mfa_challenges (token, user_id, expiry)
+VALUES ($1, $2, $3);
+
+-- name: AttemptMFAChallenge :one
+UPDATE mfa_challenges
+SET attempts = attempts + 1
+WHERE token = $1
+RETURNING user_id, expiry, attempts;
+
+-- name: DeleteMFAChallenge :exec
+DELETE
+FROM mfa_challenges
+WHERE token = $1;
+
+-- name: GetPermissionsByRoleID :many
+SELECT p.name
+FROM permissions p
+         JOIN role_permissions rp ON rp.permission_id = p.id
+WHERE rp.role_id = $1
+ORDER BY p.name;
+
+-- name: ListUsers :many
+SELECT users.id,
+       users.username,
+       users.email,
+       users.created_at,
+       users.is_active,
+       users.banned_at,
+       users.activated_at,
+       r.id          as role_id,
+       r.name        as role_name,
+       r.description as role_description,
+       r.level       as role_level
+FROM users
+         JOIN roles r ON (users.role_id = r.id)
+WHERE (@search::text = '' OR LOWER(users.username) LIKE LOWER('%' || @search || '%') OR
+       LOWER(users.email) LIKE LOWER('%' || @search || '%'))
+ORDER BY users.id
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: CountUsers :one
+SELECT COUNT(*)
+FROM users
+WHERE (@search::text = '' OR LOWER(users.username) LIKE LOWER('%' || @search || '%') OR
+       LOWER(users.email) LIKE LOWER('%' || @search || '%'));
+
+-- name: UpdateUserRole :exec
+UPDATE users
+SET role_id = $2
+WHERE id = $1;
+
+-- name: SetUserActive :exec
+UPDATE users
+SET is_active    = $2,
+    activated_at = CASE WHEN $2 THEN COALESCE(activated_at, NOW()) ELSE activated_at END
+WHERE id = $1;
+
+-- name: SetUserBannedAt :exec
+UPDATE users
+SET banned_at = sqlc.narg(banned_at)
+WHERE id = @id;
+
+-- name: ListRoles :many
+SELECT id, name, description, level
+FROM roles
+ORDER BY level;
+
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (actor_id, action, target_type, target_id, ip, user_agent, trace_id, metadata)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListAuditEvents :many
+SELECT id,
+       actor_id,
+       action,
+       target_type,
+       target_id,
+       ip,
+       user_agent,
+       trace_id,
+       metadata,
+       created_at
+FROM audit_events
+WHERE (sqlc.narg(actor_id)::bigint IS NULL OR actor_id = sqlc.narg(actor_id)::bigint)
+  AND (@action::text = '' OR action = @action)
+  AND (@target_type::text = '' OR target_type = @target_type)
+  AND (sqlc.narg(target_id)::bigint IS NULL OR target_id = sqlc.narg(target_id)::bigint)
+  AND (sqlc.narg(since)::timestamptz IS NULL OR created_at >= sqlc.narg(since)::timestamptz)
+  AND (sqlc.narg(until)::timestamptz IS NULL OR created_at <= sqlc.narg(until)::timestamptz)
+  AND (sqlc.narg(cursor_created_at)::timestamptz IS NULL OR
+       (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT @page_limit;
+
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (user_id, name, token, prefix, scopes, expiry)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at;
+
+-- name: ListPersonalAccessTokensByUser :many
+SELECT id, user_id, name, token, prefix, scopes, expiry, last_used_at, created_at
+FROM personal_access_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetPersonalAccessTokenByToken :one
+SELECT id, user_id, name, token, prefix, scopes, expiry, last_used_at, created_at
+FROM personal_access_tokens
+WHERE token = $1;
+
+-- name: TouchPersonalAccessToken :exec
+UPDATE personal_access_tokens
+SET last_used_at = @used_at
+WHERE id = @id;
+
+-- name: DeletePersonalAccessToken :execrows
+DELETE
+FROM personal_access_tokens
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: DeleteUserPersonalAccessTokens :exec
+DELETE
+FROM personal_access_tokens
+WHERE user_id = $1;
+
+-- name: DeleteExpiredOIDCLoginStates :exec
+DELETE
+FROM oidc_login_states
+WHERE expiry <= NOW();
+
+-- name: CreateOIDCLoginState :exec
+INSERT INTO oidc_login_states (state, provider, nonce, code_verifier, expiry)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: TakeOIDCLoginState :one
+DELETE
+FROM oidc_login_states
+WHERE state = $1
+  AND expiry > NOW()
+RETURNING provider, nonce, code_verifier, expiry;
+
+-- name: GetUserIdentity :one
+SELECT provider, subject, user_id, email, created_at
+FROM user_identities
+WHERE provider = $1
+  AND subject = $2;
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (provider, subject, user_id, email)
+VALUES ($1, $2, $3, $4);
+
+-- name: CreateSession :exec
+INSERT INTO sessions (id, user_id, user_agent, ip, expiry, last_used_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: GetSession :one
+SELECT id, user_id, user_agent, ip, expiry, last_used_at, revoked_at, created_at
+FROM sessions
+WHERE id = $1;
+
+-- name: ListActiveSessionsByUser :many
+SELECT id, user_id, user_agent, ip, expiry, last_used_at, revoked_at, created_at
+FROM sessions
+WHERE user_id = $1
+  AND revoked_at IS NULL
+  AND expiry > NOW()
+ORDER BY last_used_at DESC;
+
+-- name: TouchSession :exec
+UPDATE sessions
+SET last_used_at = @used_at,
+    ip           = @ip
+WHERE id = @id;
+
+-- name: ExtendSession :exec
+UPDATE sessions
+SET expiry       = @expiry,
+    last_used_at = NOW()
+WHERE id = @id;
+
+-- name: RevokeSession :exec
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE id = $1
+  AND revoked_at IS NULL;
+
+-- name: RevokeUserSession :execrows
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL
+  AND expiry > NOW();
+
+-- name: RevokeOtherUserSessions :execrows
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE user_id = $1
+  AND id <> $2
+  AND revoked_at IS NULL
+  AND expiry > NOW();
+
+-- name: RevokeUserSessions :exec
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE user_id = $1
+  AND revoked_at IS NULL;
+
+-- name: DeleteRefreshTokenFamily :exec
+DELETE
+FROM refresh_tokens
+WHERE family_id = $1;
+
+-- name: DeleteOtherUserRefreshTokens :exec
+DELETE
+FROM refresh_tokens
+WHERE user_id = $1
+  AND family_id <> $2;
+
+-- name: DeleteExpiredUserInvitations :execrows
+DELETE
+FROM user_invitations
+WHERE expiry <= NOW();
+
+-- name: DeleteUnactivatedUsers :execrows
+DELETE
+FROM users u
+WHERE u.activated_at IS NULL
+  AND u.banned_at IS NULL
+  AND u.created_at < @created_before
+  AND NOT EXISTS (SELECT 1
+                  FROM user_invitations i
+                  WHERE i.user_id = u.id
+                    AND i.expiry > NOW());
+
+-- name: UpdateUserProfile :execrows
+UPDATE users
+SET display_name = COALESCE(sqlc.narg(display_name), display_name),
+    bio          = COALESCE(sqlc.narg(bio), bio),
+    location     = COALESCE(sqlc.narg(location), location),
+    website      = COALESCE(sqlc.narg(website), website),
+    avatar_url   = COALESCE(sqlc.narg(avatar_url), avatar_url)
+WHERE id = @id;
@@ -8,18 +8,22 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
-const activeUserByInvitationToken = `-- name: ActiveUserByInvitationToken :exec
+const activeUserByInvitationToken = `-- name: ActiveUserByInvitationToken :one
 UPDATE users u
-SET is_active = TRUE
+SET is_active    = TRUE,
+    activated_at = COALESCE(u.activated_at, NOW())
 FROM user_invitations i
 WHERE i.user_id = u.id
   AND i.token = $1
   AND i.expiry > $2
+RETURNING u.id
 `
 
 type ActiveUserByInvitationTokenParams struct {
@@ -27,21 +31,189 @@ type ActiveUserByInvitationTokenParams struct {
 	Expiry time.Time
 }
 
-func (q *Queries) ActiveUserByInvitationToken(ctx context.Context, arg ActiveUserByInvitationTokenParams) error {
-	_, err := q.db.ExecContext(ctx, activeUserByInvitationToken, arg.Token, arg.Expiry)
+func (q *Queries) ActiveUserByInvitationToken(ctx context.Context, arg ActiveUserByInvitationTokenParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, activeUserByInvitationToken, arg.Token, arg.Expiry)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const attemptMFAChallenge = `-- name: AttemptMFAChallenge :one
+UPDATE mfa_challenges
+SET attempts = attempts + 1
+WHERE token = $1
+RETURNING user_id, expiry, attempts
+`
+
+type AttemptMFAChallengeRow struct {
+	UserID   int64
+	Expiry   time.Time
+	Attempts int32
+}
+
+func (q *Queries) AttemptMFAChallenge(ctx context.Context, token []byte) (AttemptMFAChallengeRow, error) {
+	row := q.db.QueryRowContext(ctx, attemptMFAChallenge, token)
+	var i AttemptMFAChallengeRow
+	err := row.Scan(&i.UserID, &i.Expiry, &i.Attempts)
+	return i, err
+}
+
+const claimOutboxMessages = `-- name: ClaimOutboxMessages :many
+UPDATE outbox_messages
+SET attempts     = attempts + 1,
+    available_at = $1
+WHERE id IN (SELECT o.id
+             FROM outbox_messages o
+             WHERE o.status = 'pending'
+               AND o.available_at <= $2
+             ORDER BY o.id
+             LIMIT $3 FOR UPDATE SKIP LOCKED)
+RETURNING id, kind, payload, attempts, created_at
+`
+
+type ClaimOutboxMessagesParams struct {
+	LeasedUntil time.Time
+	Now         time.Time
+	BatchSize   int32
+}
+
+type ClaimOutboxMessagesRow struct {
+	ID        int64
+	Kind      string
+	Payload   json.RawMessage
+	Attempts  int32
+	CreatedAt time.Time
+}
+
+func (q *Queries) ClaimOutboxMessages(ctx context.Context, arg ClaimOutboxMessagesParams) ([]ClaimOutboxMessagesRow, error) {
+	rows, err := q.db.QueryContext(ctx, claimOutboxMessages, arg.LeasedUntil, arg.Now, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimOutboxMessagesRow
+	for rows.Next() {
+		var i ClaimOutboxMessagesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Kind,
+			&i.Payload,
+			&i.Attempts,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const confirmUserTOTP = `-- name: ConfirmUserTOTP :exec
+UPDATE user_totp
+SET confirmed_at = NOW()
+WHERE user_id = $1
+`
+
+func (q *Queries) ConfirmUserTOTP(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, confirmUserTOTP, userID)
+	return err
+}
+
+const consumePasswordReset = `-- name: ConsumePasswordReset :one
+DELETE
+FROM password_resets
+WHERE token = $1
+  AND expiry > $2
+RETURNING user_id
+`
+
+type ConsumePasswordResetParams struct {
+	Token  []byte
+	Expiry time.Time
+}
+
+func (q *Queries) ConsumePasswordReset(ctx context.Context, arg ConsumePasswordResetParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, consumePasswordReset, arg.Token, arg.Expiry)
+	var user_id int64
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const countPendingOutboxMessages = `-- name: CountPendingOutboxMessages :one
+SELECT COUNT(*)
+FROM outbox_messages
+WHERE status = 'pending'
+`
+
+func (q *Queries) CountPendingOutboxMessages(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPendingOutboxMessages)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUsers = `-- name: CountUsers :one
+SELECT COUNT(*)
+FROM users
+WHERE ($1::text = '' OR LOWER(users.username) LIKE LOWER('%' || $1 || '%') OR
+       LOWER(users.email) LIKE LOWER('%' || $1 || '%'))
+`
+
+func (q *Queries) CountUsers(ctx context.Context, search string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUsers, search)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAuditEvent = `-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (actor_id, action, target_type, target_id, ip, user_agent, trace_id, metadata)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type CreateAuditEventParams struct {
+	ActorID    sql.NullInt64
+	Action     string
+	TargetType string
+	TargetID   sql.NullInt64
+	Ip         string
+	UserAgent  string
+	TraceID    string
+	Metadata   json.RawMessage
+}
+
+func (q *Queries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error {
+	_, err := q.db.ExecContext(ctx, createAuditEvent,
+		arg.ActorID,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Ip,
+		arg.UserAgent,
+		arg.TraceID,
+		arg.Metadata,
+	)
 	return err
 }
 
 const createComment = `-- name: CreateComment :one
-INSERT INTO comments (post_id, user_id, content)
-VALUES ($1, $2, $3)
+INSERT INTO comments (post_id, user_id, content, parent_comment_id, depth)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING id, created_at
 `
 
 type CreateCommentParams struct {
-	PostID  int64
-	UserID  int64
-	Content sql.NullString
+	PostID          int64
+	UserID          int64
+	Content         sql.NullString
+	ParentCommentID sql.NullInt64
+	Depth           int32
 }
 
 type CreateCommentRow struct {
@@ -50,12 +222,35 @@ type CreateCommentRow struct {
 }
 
 func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (CreateCommentRow, error) {
-	row := q.db.QueryRowContext(ctx, createComment, arg.PostID, arg.UserID, arg.Content)
+	row := q.db.QueryRowContext(ctx, createComment,
+		arg.PostID,
+		arg.UserID,
+		arg.Content,
+		arg.ParentCommentID,
+		arg.Depth,
+	)
 	var i CreateCommentRow
 	err := row.Scan(&i.ID, &i.CreatedAt)
 	return i, err
 }
 
+const createCommentReaction = `-- name: CreateCommentReaction :exec
+INSERT INTO comment_reactions (comment_id, user_id, type)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING
+`
+
+type CreateCommentReactionParams struct {
+	CommentID int64
+	UserID    int64
+	Type      string
+}
+
+func (q *Queries) CreateCommentReaction(ctx context.Context, arg CreateCommentReactionParams) error {
+	_, err := q.db.ExecContext(ctx, createCommentReaction, arg.CommentID, arg.UserID, arg.Type)
+	return err
+}
+
 const createFollow = `-- name: CreateFollow :exec
 INSERT INTO followers (user_id, follower_id)
 VALUES ($1, $2)
@@ -71,6 +266,111 @@ func (q *Queries) CreateFollow(ctx context.Context, arg CreateFollowParams) erro
 	return err
 }
 
+const createMFAChallenge = `-- name: CreateMFAChallenge :exec
+INSERT INTO mfa_challenges (token, user_id, expiry)
+VALUES ($1, $2, $3)
+`
+
+type CreateMFAChallengeParams struct {
+	Token  []byte
+	UserID int64
+	Expiry time.Time
+}
+
+func (q *Queries) CreateMFAChallenge(ctx context.Context, arg CreateMFAChallengeParams) error {
+	_, err := q.db.ExecContext(ctx, createMFAChallenge, arg.Token, arg.UserID, arg.Expiry)
+	return err
+}
+
+const createOIDCLoginState = `-- name: CreateOIDCLoginState :exec
+INSERT INTO oidc_login_states (state, provider, nonce, code_verifier, expiry)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateOIDCLoginStateParams struct {
+	State        []byte
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	Expiry       time.Time
+}
+
+func (q *Queries) CreateOIDCLoginState(ctx context.Context, arg CreateOIDCLoginStateParams) error {
+	_, err := q.db.ExecContext(ctx, createOIDCLoginState,
+		arg.State,
+		arg.Provider,
+		arg.Nonce,
+		arg.CodeVerifier,
+		arg.Expiry,
+	)
+	return err
+}
+
+const createOutboxMessage = `-- name: CreateOutboxMessage :exec
+INSERT INTO outbox_messages (kind, payload)
+VALUES ($1, $2)
+`
+
+type CreateOutboxMessageParams struct {
+	Kind    string
+	Payload json.RawMessage
+}
+
+func (q *Queries) CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) error {
+	_, err := q.db.ExecContext(ctx, createOutboxMessage, arg.Kind, arg.Payload)
+	return err
+}
+
+const createPasswordReset = `-- name: CreatePasswordReset :exec
+INSERT INTO password_resets (token, user_id, expiry)
+VALUES ($1, $2, $3)
+`
+
+type CreatePasswordResetParams struct {
+	Token  []byte
+	UserID int64
+	Expiry time.Time
+}
+
+func (q *Queries) CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) error {
+	_, err := q.db.ExecContext(ctx, createPasswordReset, arg.Token, arg.UserID, arg.Expiry)
+	return err
+}
+
+const createPersonalAccessToken = `-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (user_id, name, token, prefix, scopes, expiry)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at
+`
+
+type CreatePersonalAccessTokenParams struct {
+	UserID int64
+	Name   string
+	Token  []byte
+	Prefix string
+	Scopes []string
+	Expiry time.Time
+}
+
+type CreatePersonalAccessTokenRow struct {
+	ID        int64
+	CreatedAt time.Time
+}
+
+func (q *Queries) CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (CreatePersonalAccessTokenRow, error) {
+	row := q.db.QueryRowContext(ctx, createPersonalAccessToken,
+		arg.UserID,
+		arg.Name,
+		arg.Token,
+		arg.Prefix,
+		pq.Array(arg.Scopes),
+		arg.Expiry,
+	)
+	var i CreatePersonalAccessTokenRow
+	err := row.Scan(&i.ID, &i.CreatedAt)
+	return i, err
+}
+
 const createPost = `-- name: CreatePost :one
 INSERT INTO posts (content, title, user_id, tags)
 VALUES ($1, $2, $3, $4)
@@ -102,6 +402,89 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (CreateP
 	return i, err
 }
 
+const createPostReaction = `-- name: CreatePostReaction :exec
+INSERT INTO post_reactions (post_id, user_id, type)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING
+`
+
+type CreatePostReactionParams struct {
+	PostID int64
+	UserID int64
+	Type   string
+}
+
+func (q *Queries) CreatePostReaction(ctx context.Context, arg CreatePostReactionParams) error {
+	_, err := q.db.ExecContext(ctx, createPostReaction, arg.PostID, arg.UserID, arg.Type)
+	return err
+}
+
+const createRefreshToken = `-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (token, user_id, family_id, expiry)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateRefreshTokenParams struct {
+	Token    []byte
+	UserID   int64
+	FamilyID uuid.UUID
+	Expiry   time.Time
+}
+
+func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error {
+	_, err := q.db.ExecContext(ctx, createRefreshToken,
+		arg.Token,
+		arg.UserID,
+		arg.FamilyID,
+		arg.Expiry,
+	)
+	return err
+}
+
+const createRevokedToken = `-- name: CreateRevokedToken :exec
+INSERT INTO revoked_tokens (jti, expiry)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type CreateRevokedTokenParams struct {
+	Jti    string
+	Expiry time.Time
+}
+
+func (q *Queries) CreateRevokedToken(ctx context.Context, arg CreateRevokedTokenParams) error {
+	_, err := q.db.ExecContext(ctx, createRevokedToken, arg.Jti, arg.Expiry)
+	return err
+}
+
+const createSession = `-- name: CreateSession :exec
+INSERT INTO sessions (id, user_id, user_agent, ip, expiry, last_used_at, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type CreateSessionParams struct {
+	ID         uuid.UUID
+	UserID     int64
+	UserAgent  string
+	Ip         string
+	Expiry     time.Time
+	LastUsedAt time.Time
+	CreatedAt  time.Time
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) error {
+	_, err := q.db.ExecContext(ctx, createSession,
+		arg.ID,
+		arg.UserID,
+		arg.UserAgent,
+		arg.Ip,
+		arg.Expiry,
+		arg.LastUsedAt,
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (username, email, password, role_id)
 VALUES ($1, $2, $3, $4)
@@ -132,6 +515,28 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateU
 	return i, err
 }
 
+const createUserIdentity = `-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (provider, subject, user_id, email)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateUserIdentityParams struct {
+	Provider string
+	Subject  string
+	UserID   int64
+	Email    string
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) error {
+	_, err := q.db.ExecContext(ctx, createUserIdentity,
+		arg.Provider,
+		arg.Subject,
+		arg.UserID,
+		arg.Email,
+	)
+	return err
+}
+
 const createUserInvitation = `-- name: CreateUserInvitation :exec
 INSERT INTO user_invitations (token, user_id, expiry)
 VALUES ($1, $2, $3)
@@ -148,114 +553,450 @@ func (q *Queries) CreateUserInvitation(ctx context.Context, arg CreateUserInvita
 	return err
 }
 
-const deleteFollow = `-- name: DeleteFollow :execrows
-DELETE
-FROM followers
-WHERE user_id = $1
-  AND follower_id = $2
+const createUserRecoveryCodes = `-- name: CreateUserRecoveryCodes :exec
+INSERT INTO user_recovery_codes (user_id, code)
+SELECT $1::bigint, UNNEST($2::bytea[])
 `
 
-type DeleteFollowParams struct {
-	UserID     int64
-	FollowerID int64
+type CreateUserRecoveryCodesParams struct {
+	UserID int64
+	Codes  [][]byte
 }
 
-func (q *Queries) DeleteFollow(ctx context.Context, arg DeleteFollowParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deleteFollow, arg.UserID, arg.FollowerID)
+func (q *Queries) CreateUserRecoveryCodes(ctx context.Context, arg CreateUserRecoveryCodesParams) error {
+	_, err := q.db.ExecContext(ctx, createUserRecoveryCodes, arg.UserID, pq.Array(arg.Codes))
+	return err
+}
+
+const deleteCommentByID = `-- name: DeleteCommentByID :execrows
+DELETE
+FROM comments
+WHERE id = $1
+`
+
+func (q *Queries) DeleteCommentByID(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCommentByID, id)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected()
 }
 
-const deletePostByID = `-- name: DeletePostByID :execrows
+const deleteCommentReaction = `-- name: DeleteCommentReaction :execrows
 DELETE
-FROM posts
-WHERE id = $1
+FROM comment_reactions
+WHERE comment_id = $1
+  AND user_id = $2
+  AND type = $3
 `
 
-func (q *Queries) DeletePostByID(ctx context.Context, id int64) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deletePostByID, id)
+type DeleteCommentReactionParams struct {
+	CommentID int64
+	UserID    int64
+	Type      string
+}
+
+func (q *Queries) DeleteCommentReaction(ctx context.Context, arg DeleteCommentReactionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCommentReaction, arg.CommentID, arg.UserID, arg.Type)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected()
 }
 
-const deleteUserByID = `-- name: DeleteUserByID :exec
+const deleteExpiredOIDCLoginStates = `-- name: DeleteExpiredOIDCLoginStates :exec
 DELETE
-FROM users
-WHERE id = $1
+FROM oidc_login_states
+WHERE expiry <= NOW()
 `
 
-func (q *Queries) DeleteUserByID(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteUserByID, id)
+func (q *Queries) DeleteExpiredOIDCLoginStates(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredOIDCLoginStates)
 	return err
 }
 
-const deleteUserInvitationByToken = `-- name: DeleteUserInvitationByToken :exec
+const deleteExpiredRevokedTokens = `-- name: DeleteExpiredRevokedTokens :exec
 DELETE
-FROM user_invitations
-WHERE token = $1
+FROM revoked_tokens
+WHERE expiry < NOW()
 `
 
-func (q *Queries) DeleteUserInvitationByToken(ctx context.Context, token []byte) error {
-	_, err := q.db.ExecContext(ctx, deleteUserInvitationByToken, token)
+func (q *Queries) DeleteExpiredRevokedTokens(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteExpiredRevokedTokens)
 	return err
 }
 
-const deleteUserInvitationByUserID = `-- name: DeleteUserInvitationByUserID :exec
+const deleteExpiredUserInvitations = `-- name: DeleteExpiredUserInvitations :execrows
 DELETE
 FROM user_invitations
-WHERE user_id = $1
+WHERE expiry <= NOW()
 `
 
-func (q *Queries) DeleteUserInvitationByUserID(ctx context.Context, userID int64) error {
-	_, err := q.db.ExecContext(ctx, deleteUserInvitationByUserID, userID)
-	return err
+func (q *Queries) DeleteExpiredUserInvitations(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredUserInvitations)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const getAllCommentsByPostID = `-- name: GetAllCommentsByPostID :many
-SELECT c.id,
-       c.post_id,
-       c.user_id,
-       c.content,
-       c.created_at,
-       u.username,
-       u.id
-FROM comments c
-         JOIN users u ON u.id = c.user_id
-WHERE c.post_id = $1
-ORDER BY c.created_at DESC
+const deleteFollow = `-- name: DeleteFollow :execrows
+DELETE
+FROM followers
+WHERE user_id = $1
+  AND follower_id = $2
 `
 
-type GetAllCommentsByPostIDRow struct {
-	ID        int64
-	PostID    int64
-	UserID    int64
-	Content   sql.NullString
-	CreatedAt time.Time
-	Username  string
-	ID_2      int64
+type DeleteFollowParams struct {
+	UserID     int64
+	FollowerID int64
 }
 
-func (q *Queries) GetAllCommentsByPostID(ctx context.Context, postID int64) ([]GetAllCommentsByPostIDRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllCommentsByPostID, postID)
-	if err != nil {
+func (q *Queries) DeleteFollow(ctx context.Context, arg DeleteFollowParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFollow, arg.UserID, arg.FollowerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteMFAChallenge = `-- name: DeleteMFAChallenge :exec
+DELETE
+FROM mfa_challenges
+WHERE token = $1
+`
+
+func (q *Queries) DeleteMFAChallenge(ctx context.Context, token []byte) error {
+	_, err := q.db.ExecContext(ctx, deleteMFAChallenge, token)
+	return err
+}
+
+const deleteOtherUserRefreshTokens = `-- name: DeleteOtherUserRefreshTokens :exec
+DELETE
+FROM refresh_tokens
+WHERE user_id = $1
+  AND family_id <> $2
+`
+
+type DeleteOtherUserRefreshTokensParams struct {
+	UserID   int64
+	FamilyID uuid.UUID
+}
+
+func (q *Queries) DeleteOtherUserRefreshTokens(ctx context.Context, arg DeleteOtherUserRefreshTokensParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOtherUserRefreshTokens, arg.UserID, arg.FamilyID)
+	return err
+}
+
+const deletePasswordResetsByUserID = `-- name: DeletePasswordResetsByUserID :exec
+DELETE
+FROM password_resets
+WHERE user_id = $1
+`
+
+func (q *Queries) DeletePasswordResetsByUserID(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deletePasswordResetsByUserID, userID)
+	return err
+}
+
+const deletePersonalAccessToken = `-- name: DeletePersonalAccessToken :execrows
+DELETE
+FROM personal_access_tokens
+WHERE id = $1
+  AND user_id = $2
+`
+
+type DeletePersonalAccessTokenParams struct {
+	ID     int64
+	UserID int64
+}
+
+func (q *Queries) DeletePersonalAccessToken(ctx context.Context, arg DeletePersonalAccessTokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePersonalAccessToken, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePostByID = `-- name: DeletePostByID :execrows
+DELETE
+FROM posts
+WHERE id = $1
+`
+
+func (q *Queries) DeletePostByID(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePostByID, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePostReaction = `-- name: DeletePostReaction :execrows
+DELETE
+FROM post_reactions
+WHERE post_id = $1
+  AND user_id = $2
+  AND type = $3
+`
+
+type DeletePostReactionParams struct {
+	PostID int64
+	UserID int64
+	Type   string
+}
+
+func (q *Queries) DeletePostReaction(ctx context.Context, arg DeletePostReactionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePostReaction, arg.PostID, arg.UserID, arg.Type)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteRefreshTokenFamily = `-- name: DeleteRefreshTokenFamily :exec
+DELETE
+FROM refresh_tokens
+WHERE family_id = $1
+`
+
+func (q *Queries) DeleteRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteRefreshTokenFamily, familyID)
+	return err
+}
+
+const deleteUnactivatedUsers = `-- name: DeleteUnactivatedUsers :execrows
+DELETE
+FROM users u
+WHERE u.activated_at IS NULL
+  AND u.banned_at IS NULL
+  AND u.created_at < $1
+  AND NOT EXISTS (SELECT 1
+                  FROM user_invitations i
+                  WHERE i.user_id = u.id
+                    AND i.expiry > NOW())
+`
+
+func (q *Queries) DeleteUnactivatedUsers(ctx context.Context, createdBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUnactivatedUsers, createdBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteUserByID = `-- name: DeleteUserByID :exec
+DELETE
+FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUserByID(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteUserByID, id)
+	return err
+}
+
+const deleteUserInvitationByToken = `-- name: DeleteUserInvitationByToken :exec
+DELETE
+FROM user_invitations
+WHERE token = $1
+`
+
+func (q *Queries) DeleteUserInvitationByToken(ctx context.Context, token []byte) error {
+	_, err := q.db.ExecContext(ctx, deleteUserInvitationByToken, token)
+	return err
+}
+
+const deleteUserInvitationByUserID = `-- name: DeleteUserInvitationByUserID :exec
+DELETE
+FROM user_invitations
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserInvitationByUserID(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteUserInvitationByUserID, userID)
+	return err
+}
+
+const deleteUserPersonalAccessTokens = `-- name: DeleteUserPersonalAccessTokens :exec
+DELETE
+FROM personal_access_tokens
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserPersonalAccessTokens(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteUserPersonalAccessTokens, userID)
+	return err
+}
+
+const deleteUserRecoveryCodes = `-- name: DeleteUserRecoveryCodes :exec
+DELETE
+FROM user_recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserRecoveryCodes(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteUserRecoveryCodes, userID)
+	return err
+}
+
+const deleteUserTOTP = `-- name: DeleteUserTOTP :exec
+DELETE
+FROM user_totp
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserTOTP(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteUserTOTP, userID)
+	return err
+}
+
+const extendSession = `-- name: ExtendSession :exec
+UPDATE sessions
+SET expiry       = $1,
+    last_used_at = NOW()
+WHERE id = $2
+`
+
+type ExtendSessionParams struct {
+	Expiry time.Time
+	ID     uuid.UUID
+}
+
+func (q *Queries) ExtendSession(ctx context.Context, arg ExtendSessionParams) error {
+	_, err := q.db.ExecContext(ctx, extendSession, arg.Expiry, arg.ID)
+	return err
+}
+
+const getCommentByID = `-- name: GetCommentByID :one
+SELECT c.id,
+       c.post_id,
+       c.user_id,
+       c.content,
+       c.created_at,
+       c.updated_at,
+       c.parent_comment_id,
+       c.depth,
+       (SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id) AS replies_count,
+       u.username
+FROM comments c
+         JOIN users u ON u.id = c.user_id
+WHERE c.id = $1
+`
+
+type GetCommentByIDRow struct {
+	ID              int64
+	PostID          int64
+	UserID          int64
+	Content         sql.NullString
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ParentCommentID sql.NullInt64
+	Depth           int32
+	RepliesCount    int64
+	Username        string
+}
+
+func (q *Queries) GetCommentByID(ctx context.Context, id int64) (GetCommentByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getCommentByID, id)
+	var i GetCommentByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.UserID,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentCommentID,
+		&i.Depth,
+		&i.RepliesCount,
+		&i.Username,
+	)
+	return i, err
+}
+
+const getCommentReplies = `-- name: GetCommentReplies :many
+SELECT c.id,
+       c.post_id,
+       c.user_id,
+       c.content,
+       c.created_at,
+       c.updated_at,
+       c.parent_comment_id,
+       c.depth,
+       (SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id) AS replies_count,
+       (SELECT COALESCE(jsonb_object_agg(r.type, r.count), '{}')
+        FROM (SELECT cr.type, COUNT(*) AS count
+              FROM comment_reactions cr
+              WHERE cr.comment_id = c.id
+              GROUP BY cr.type) r)::jsonb AS reaction_counts,
+       ARRAY(SELECT cr.type
+             FROM comment_reactions cr
+             WHERE cr.comment_id = c.id
+               AND cr.user_id = $1
+             ORDER BY cr.type)::varchar[] AS my_reactions,
+       u.username
+FROM comments c
+         JOIN users u ON u.id = c.user_id
+WHERE c.parent_comment_id = $2::bigint
+  AND ($3::timestamptz IS NULL OR
+       (c.created_at, c.id) > ($3::timestamptz, $4::bigint))
+ORDER BY c.created_at, c.id
+LIMIT $5
+`
+
+type GetCommentRepliesParams struct {
+	ViewerID        int64
+	ParentID        int64
+	CursorCreatedAt sql.NullTime
+	CursorID        sql.NullInt64
+	PageLimit       int32
+}
+
+type GetCommentRepliesRow struct {
+	ID              int64
+	PostID          int64
+	UserID          int64
+	Content         sql.NullString
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ParentCommentID sql.NullInt64
+	Depth           int32
+	RepliesCount    int64
+	ReactionCounts  json.RawMessage
+	MyReactions     []string
+	Username        string
+}
+
+func (q *Queries) GetCommentReplies(ctx context.Context, arg GetCommentRepliesParams) ([]GetCommentRepliesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCommentReplies,
+		arg.ViewerID,
+		arg.ParentID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetAllCommentsByPostIDRow
+	var items []GetCommentRepliesRow
 	for rows.Next() {
-		var i GetAllCommentsByPostIDRow
+		var i GetCommentRepliesRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.PostID,
 			&i.UserID,
 			&i.Content,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ParentCommentID,
+			&i.Depth,
+			&i.RepliesCount,
+			&i.ReactionCounts,
+			pq.Array(&i.MyReactions),
 			&i.Username,
-			&i.ID_2,
 		); err != nil {
 			return nil, err
 		}
@@ -267,223 +1008,1208 @@ func (q *Queries) GetAllCommentsByPostID(ctx context.Context, postID int64) ([]G
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return items, nil
+	return items, nil
+}
+
+const getCommentThread = `-- name: GetCommentThread :many
+-- Walks the replies to a comment at every depth. The path of a reply is the
+-- ids of its ancestors below the comment and its own, so sorting by it lists
+-- every reply after its parent and the siblings oldest first.
+WITH RECURSIVE thread AS (SELECT c.id,
+                                 ARRAY [c.id] AS path
+                          FROM comments c
+                          WHERE c.parent_comment_id = $1::bigint
+                          UNION ALL
+                          SELECT c.id,
+                                 t.path || c.id
+                          FROM comments c
+                                   JOIN thread t ON c.parent_comment_id = t.id)
+SELECT c.id,
+       c.post_id,
+       c.user_id,
+       c.content,
+       c.created_at,
+       c.updated_at,
+       c.parent_comment_id,
+       c.depth,
+       (SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id) AS replies_count,
+       (SELECT COALESCE(jsonb_object_agg(r.type, r.count), '{}')
+        FROM (SELECT cr.type, COUNT(*) AS count
+              FROM comment_reactions cr
+              WHERE cr.comment_id = c.id
+              GROUP BY cr.type) r)::jsonb AS reaction_counts,
+       ARRAY(SELECT cr.type
+             FROM comment_reactions cr
+             WHERE cr.comment_id = c.id
+               AND cr.user_id = $2
+             ORDER BY cr.type)::varchar[] AS my_reactions,
+       u.username
+FROM thread t
+         JOIN comments c ON c.id = t.id
+         JOIN users u ON u.id = c.user_id
+ORDER BY t.path
+LIMIT $3
+`
+
+type GetCommentThreadParams struct {
+	CommentID   int64
+	ViewerID    int64
+	ThreadLimit int32
+}
+
+type GetCommentThreadRow struct {
+	ID              int64
+	PostID          int64
+	UserID          int64
+	Content         sql.NullString
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ParentCommentID sql.NullInt64
+	Depth           int32
+	RepliesCount    int64
+	ReactionCounts  json.RawMessage
+	MyReactions     []string
+	Username        string
+}
+
+func (q *Queries) GetCommentThread(ctx context.Context, arg GetCommentThreadParams) ([]GetCommentThreadRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCommentThread, arg.CommentID, arg.ViewerID, arg.ThreadLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCommentThreadRow
+	for rows.Next() {
+		var i GetCommentThreadRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.UserID,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ParentCommentID,
+			&i.Depth,
+			&i.RepliesCount,
+			&i.ReactionCounts,
+			pq.Array(&i.MyReactions),
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCommentsByPostID = `-- name: GetCommentsByPostID :many
+SELECT c.id,
+       c.post_id,
+       c.user_id,
+       c.content,
+       c.created_at,
+       c.updated_at,
+       c.parent_comment_id,
+       c.depth,
+       (SELECT COUNT(*) FROM comments r WHERE r.parent_comment_id = c.id) AS replies_count,
+       (SELECT COALESCE(jsonb_object_agg(r.type, r.count), '{}')
+        FROM (SELECT cr.type, COUNT(*) AS count
+              FROM comment_reactions cr
+              WHERE cr.comment_id = c.id
+              GROUP BY cr.type) r)::jsonb AS reaction_counts,
+       ARRAY(SELECT cr.type
+             FROM comment_reactions cr
+             WHERE cr.comment_id = c.id
+               AND cr.user_id = $1
+             ORDER BY cr.type)::varchar[] AS my_reactions,
+       u.username
+FROM comments c
+         JOIN users u ON u.id = c.user_id
+WHERE c.post_id = $2
+  AND c.parent_comment_id IS NULL
+  AND ($3::timestamptz IS NULL OR
+       (c.created_at, c.id) < ($3::timestamptz, $4::bigint))
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT $5
+`
+
+type GetCommentsByPostIDParams struct {
+	ViewerID        int64
+	PostID          int64
+	CursorCreatedAt sql.NullTime
+	CursorID        sql.NullInt64
+	PageLimit       int32
+}
+
+type GetCommentsByPostIDRow struct {
+	ID              int64
+	PostID          int64
+	UserID          int64
+	Content         sql.NullString
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ParentCommentID sql.NullInt64
+	Depth           int32
+	RepliesCount    int64
+	ReactionCounts  json.RawMessage
+	MyReactions     []string
+	Username        string
+}
+
+func (q *Queries) GetCommentsByPostID(ctx context.Context, arg GetCommentsByPostIDParams) ([]GetCommentsByPostIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCommentsByPostID,
+		arg.ViewerID,
+		arg.PostID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCommentsByPostIDRow
+	for rows.Next() {
+		var i GetCommentsByPostIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.UserID,
+			&i.Content,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ParentCommentID,
+			&i.Depth,
+			&i.RepliesCount,
+			&i.ReactionCounts,
+			pq.Array(&i.MyReactions),
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPermissionsByRoleID = `-- name: GetPermissionsByRoleID :many
+SELECT p.name
+FROM permissions p
+         JOIN role_permissions rp ON rp.permission_id = p.id
+WHERE rp.role_id = $1
+ORDER BY p.name
+`
+
+func (q *Queries) GetPermissionsByRoleID(ctx context.Context, roleID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getPermissionsByRoleID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPersonalAccessTokenByToken = `-- name: GetPersonalAccessTokenByToken :one
+SELECT id, user_id, name, token, prefix, scopes, expiry, last_used_at, created_at
+FROM personal_access_tokens
+WHERE token = $1
+`
+
+func (q *Queries) GetPersonalAccessTokenByToken(ctx context.Context, token []byte) (PersonalAccessToken, error) {
+	row := q.db.QueryRowContext(ctx, getPersonalAccessTokenByToken, token)
+	var i PersonalAccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Token,
+		&i.Prefix,
+		pq.Array(&i.Scopes),
+		&i.Expiry,
+		&i.LastUsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPostByID = `-- name: GetPostByID :one
+SELECT id,
+       content,
+       title,
+       user_id,
+       created_at,
+       updated_at,
+       tags,
+       version
+FROM posts
+WHERE id = $1
+`
+
+type GetPostByIDRow struct {
+	ID        int64
+	Content   string
+	Title     string
+	UserID    int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Tags      []string
+	Version   sql.NullInt32
+}
+
+func (q *Queries) GetPostByID(ctx context.Context, id int64) (GetPostByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getPostByID, id)
+	var i GetPostByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Content,
+		&i.Title,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		pq.Array(&i.Tags),
+		&i.Version,
+	)
+	return i, err
+}
+
+const getRefreshTokenByToken = `-- name: GetRefreshTokenByToken :one
+SELECT id, user_id, family_id, expiry, revoked_at
+FROM refresh_tokens
+WHERE token = $1
+`
+
+type GetRefreshTokenByTokenRow struct {
+	ID        int64
+	UserID    int64
+	FamilyID  uuid.UUID
+	Expiry    time.Time
+	RevokedAt sql.NullTime
+}
+
+func (q *Queries) GetRefreshTokenByToken(ctx context.Context, token []byte) (GetRefreshTokenByTokenRow, error) {
+	row := q.db.QueryRowContext(ctx, getRefreshTokenByToken, token)
+	var i GetRefreshTokenByTokenRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FamilyID,
+		&i.Expiry,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const getRoleByName = `-- name: GetRoleByName :one
+SELECT id, name, description, level
+FROM roles
+WHERE name = $1
+`
+
+type GetRoleByNameRow struct {
+	ID          int64
+	Name        string
+	Description sql.NullString
+	Level       int32
+}
+
+func (q *Queries) GetRoleByName(ctx context.Context, name string) (GetRoleByNameRow, error) {
+	row := q.db.QueryRowContext(ctx, getRoleByName, name)
+	var i GetRoleByNameRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Level,
+	)
+	return i, err
+}
+
+const getSession = `-- name: GetSession :one
+SELECT id, user_id, user_agent, ip, expiry, last_used_at, revoked_at, created_at
+FROM sessions
+WHERE id = $1
+`
+
+func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRowContext(ctx, getSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.UserAgent,
+		&i.Ip,
+		&i.Expiry,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, email, password, username, created_at, is_active, banned_at, activated_at
+FROM users
+WHERE email = $1
+`
+
+type GetUserByEmailRow struct {
+	ID          int64
+	Email       string
+	Password    []byte
+	Username    string
+	CreatedAt   time.Time
+	IsActive    bool
+	BannedAt    sql.NullTime
+	ActivatedAt sql.NullTime
+}
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i GetUserByEmailRow
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Password,
+		&i.Username,
+		&i.CreatedAt,
+		&i.IsActive,
+		&i.BannedAt,
+		&i.ActivatedAt,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT users.id,
+       users.username,
+       users.email,
+       users.created_at,
+       users.is_active,
+       users.banned_at,
+       users.activated_at,
+       users.display_name,
+       users.bio,
+       users.location,
+       users.website,
+       users.avatar_url,
+       r.id          as role_id,
+       r.name        as role_name,
+       r.description as role_description,
+       r.level       as role_level
+FROM users
+         JOIN roles r ON (users.role_id = r.id)
+WHERE users.id = $1
+`
+
+type GetUserByIDRow struct {
+	ID              int64
+	Username        string
+	Email           string
+	CreatedAt       time.Time
+	IsActive        bool
+	BannedAt        sql.NullTime
+	ActivatedAt     sql.NullTime
+	DisplayName     string
+	Bio             string
+	Location        string
+	Website         string
+	AvatarUrl       string
+	RoleID          int64
+	RoleName        string
+	RoleDescription sql.NullString
+	RoleLevel       int32
+}
+
+func (q *Queries) GetUserByID(ctx context.Context, id int64) (GetUserByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i GetUserByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.CreatedAt,
+		&i.IsActive,
+		&i.BannedAt,
+		&i.ActivatedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Location,
+		&i.Website,
+		&i.AvatarUrl,
+		&i.RoleID,
+		&i.RoleName,
+		&i.RoleDescription,
+		&i.RoleLevel,
+	)
+	return i, err
+}
+
+const getUserFeed = `-- name: GetUserFeed :many
+SELECT p.id,
+       p.user_id,
+       p.title,
+       p.content,
+       p.created_at,
+       p.tags,
+       COUNT(c.id) AS comments_count,
+       (SELECT COALESCE(jsonb_object_agg(r.type, r.count), '{}')
+        FROM (SELECT pr.type, COUNT(*) AS count
+              FROM post_reactions pr
+              WHERE pr.post_id = p.id
+              GROUP BY pr.type) r)::jsonb AS reaction_counts,
+       ARRAY(SELECT pr.type
+             FROM post_reactions pr
+             WHERE pr.post_id = p.id
+               AND pr.user_id = $1
+             ORDER BY pr.type)::varchar[] AS my_reactions,
+       u.username
+FROM posts p
+         LEFT JOIN comments c ON c.post_id = p.id
+         LEFT JOIN users u ON p.user_id = u.id
+WHERE (p.user_id = $1 OR p.user_id IN (SELECT f.follower_id
+                                             FROM followers f
+                                             WHERE f.user_id = $1))
+  AND ($2::text = '' OR LOWER(p.title) LIKE LOWER('%' || $2 || '%') OR
+       LOWER(p.content) LIKE LOWER('%' || $2 || '%'))
+  AND (p.tags @> $3::varchar[] OR $3::varchar[] = '{}')
+  AND ($4::timestamptz IS NULL OR p.created_at >= $4::timestamptz)
+  AND ($5::timestamptz IS NULL OR p.created_at <= $5::timestamptz)
+  AND ($6::timestamptz IS NULL OR
+       ($7::bool AND
+        (p.created_at, p.id) > ($6::timestamptz, $8::bigint)) OR
+       (NOT $7::bool AND
+        (p.created_at, p.id) < ($6::timestamptz, $8::bigint)))
+GROUP BY p.id, u.username
+ORDER BY CASE WHEN $7::bool THEN p.created_at END,
+         CASE WHEN $7::bool THEN p.id END,
+         p.created_at DESC,
+         p.id DESC
+LIMIT $9 OFFSET $10
+`
+
+type GetUserFeedParams struct {
+	UserID          int64
+	Search          string
+	Tags            []string
+	Since           sql.NullTime
+	Until           sql.NullTime
+	CursorCreatedAt sql.NullTime
+	SortAsc         bool
+	CursorID        sql.NullInt64
+	PageLimit       int32
+	PageOffset      int32
+}
+
+type GetUserFeedRow struct {
+	ID             int64
+	UserID         int64
+	Title          string
+	Content        string
+	CreatedAt      time.Time
+	Tags           []string
+	CommentsCount  int64
+	ReactionCounts json.RawMessage
+	MyReactions    []string
+	Username       sql.NullString
+}
+
+func (q *Queries) GetUserFeed(ctx context.Context, arg GetUserFeedParams) ([]GetUserFeedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserFeed,
+		arg.UserID,
+		arg.Search,
+		pq.Array(arg.Tags),
+		arg.Since,
+		arg.Until,
+		arg.CursorCreatedAt,
+		arg.SortAsc,
+		arg.CursorID,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserFeedRow
+	for rows.Next() {
+		var i GetUserFeedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			pq.Array(&i.Tags),
+			&i.CommentsCount,
+			&i.ReactionCounts,
+			pq.Array(&i.MyReactions),
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserIdentity = `-- name: GetUserIdentity :one
+SELECT provider, subject, user_id, email, created_at
+FROM user_identities
+WHERE provider = $1
+  AND subject = $2
+`
+
+type GetUserIdentityParams struct {
+	Provider string
+	Subject  string
+}
+
+func (q *Queries) GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRowContext(ctx, getUserIdentity, arg.Provider, arg.Subject)
+	var i UserIdentity
+	err := row.Scan(
+		&i.Provider,
+		&i.Subject,
+		&i.UserID,
+		&i.Email,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserTOTP = `-- name: GetUserTOTP :one
+SELECT user_id, secret, confirmed_at, last_used_step
+FROM user_totp
+WHERE user_id = $1
+`
+
+type GetUserTOTPRow struct {
+	UserID       int64
+	Secret       string
+	ConfirmedAt  sql.NullTime
+	LastUsedStep int64
+}
+
+func (q *Queries) GetUserTOTP(ctx context.Context, userID int64) (GetUserTOTPRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserTOTP, userID)
+	var i GetUserTOTPRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.ConfirmedAt,
+		&i.LastUsedStep,
+	)
+	return i, err
+}
+
+const isTokenRevoked = `-- name: IsTokenRevoked :one
+SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)
+`
+
+func (q *Queries) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isTokenRevoked, jti)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listActiveSessionsByUser = `-- name: ListActiveSessionsByUser :many
+SELECT id, user_id, user_agent, ip, expiry, last_used_at, revoked_at, created_at
+FROM sessions
+WHERE user_id = $1
+  AND revoked_at IS NULL
+  AND expiry > NOW()
+ORDER BY last_used_at DESC
+`
+
+func (q *Queries) ListActiveSessionsByUser(ctx context.Context, userID int64) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveSessionsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Session
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.UserAgent,
+			&i.Ip,
+			&i.Expiry,
+			&i.LastUsedAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuditEvents = `-- name: ListAuditEvents :many
+SELECT id,
+       actor_id,
+       action,
+       target_type,
+       target_id,
+       ip,
+       user_agent,
+       trace_id,
+       metadata,
+       created_at
+FROM audit_events
+WHERE ($1::bigint IS NULL OR actor_id = $1::bigint)
+  AND ($2::text = '' OR action = $2)
+  AND ($3::text = '' OR target_type = $3)
+  AND ($4::bigint IS NULL OR target_id = $4::bigint)
+  AND ($5::timestamptz IS NULL OR created_at >= $5::timestamptz)
+  AND ($6::timestamptz IS NULL OR created_at <= $6::timestamptz)
+  AND ($7::timestamptz IS NULL OR
+       (created_at, id) < ($7::timestamptz, $8::bigint))
+ORDER BY created_at DESC, id DESC
+LIMIT $9
+`
+
+type ListAuditEventsParams struct {
+	ActorID         sql.NullInt64
+	Action          string
+	TargetType      string
+	TargetID        sql.NullInt64
+	Since           sql.NullTime
+	Until           sql.NullTime
+	CursorCreatedAt sql.NullTime
+	CursorID        sql.NullInt64
+	PageLimit       int32
+}
+
+func (q *Queries) ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listAuditEvents,
+		arg.ActorID,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Since,
+		arg.Until,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	for rows.Next() {
+		var i AuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActorID,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.TraceID,
+			&i.Metadata,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPersonalAccessTokensByUser = `-- name: ListPersonalAccessTokensByUser :many
+SELECT id, user_id, name, token, prefix, scopes, expiry, last_used_at, created_at
+FROM personal_access_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) ListPersonalAccessTokensByUser(ctx context.Context, userID int64) ([]PersonalAccessToken, error) {
+	rows, err := q.db.QueryContext(ctx, listPersonalAccessTokensByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PersonalAccessToken
+	for rows.Next() {
+		var i PersonalAccessToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Token,
+			&i.Prefix,
+			pq.Array(&i.Scopes),
+			&i.Expiry,
+			&i.LastUsedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoles = `-- name: ListRoles :many
+SELECT id, name, description, level
+FROM roles
+ORDER BY level
+`
+
+func (q *Queries) ListRoles(ctx context.Context) ([]Role, error) {
+	rows, err := q.db.QueryContext(ctx, listRoles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Role
+	for rows.Next() {
+		var i Role
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Level,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT users.id,
+       users.username,
+       users.email,
+       users.created_at,
+       users.is_active,
+       users.banned_at,
+       users.activated_at,
+       r.id          as role_id,
+       r.name        as role_name,
+       r.description as role_description,
+       r.level       as role_level
+FROM users
+         JOIN roles r ON (users.role_id = r.id)
+WHERE ($1::text = '' OR LOWER(users.username) LIKE LOWER('%' || $1 || '%') OR
+       LOWER(users.email) LIKE LOWER('%' || $1 || '%'))
+ORDER BY users.id
+LIMIT $2 OFFSET $3
+`
+
+type ListUsersParams struct {
+	Search     string
+	PageLimit  int32
+	PageOffset int32
+}
+
+type ListUsersRow struct {
+	ID              int64
+	Username        string
+	Email           string
+	CreatedAt       time.Time
+	IsActive        bool
+	BannedAt        sql.NullTime
+	ActivatedAt     sql.NullTime
+	RoleID          int64
+	RoleName        string
+	RoleDescription sql.NullString
+	RoleLevel       int32
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers, arg.Search, arg.PageLimit, arg.PageOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersRow
+	for rows.Next() {
+		var i ListUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Email,
+			&i.CreatedAt,
+			&i.IsActive,
+			&i.BannedAt,
+			&i.ActivatedAt,
+			&i.RoleID,
+			&i.RoleName,
+			&i.RoleDescription,
+			&i.RoleLevel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxMessageDead = `-- name: MarkOutboxMessageDead :exec
+UPDATE outbox_messages
+SET status       = 'dead',
+    processed_at = NOW(),
+    last_error   = $2
+WHERE id = $1
+`
+
+type MarkOutboxMessageDeadParams struct {
+	ID        int64
+	LastError sql.NullString
+}
+
+func (q *Queries) MarkOutboxMessageDead(ctx context.Context, arg MarkOutboxMessageDeadParams) error {
+	_, err := q.db.ExecContext(ctx, markOutboxMessageDead, arg.ID, arg.LastError)
+	return err
+}
+
+const markOutboxMessageSent = `-- name: MarkOutboxMessageSent :exec
+UPDATE outbox_messages
+SET status       = 'sent',
+    processed_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) MarkOutboxMessageSent(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markOutboxMessageSent, id)
+	return err
+}
+
+const postExists = `-- name: PostExists :one
+SELECT EXISTS(SELECT 1 FROM posts WHERE id = $1)
+`
+
+func (q *Queries) PostExists(ctx context.Context, id int64) (bool, error) {
+	row := q.db.QueryRowContext(ctx, postExists, id)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const releaseOutboxMessage = `-- name: ReleaseOutboxMessage :exec
+UPDATE outbox_messages
+SET attempts     = attempts - 1,
+    available_at = $2
+WHERE id = $1
+`
+
+type ReleaseOutboxMessageParams struct {
+	ID          int64
+	AvailableAt time.Time
+}
+
+func (q *Queries) ReleaseOutboxMessage(ctx context.Context, arg ReleaseOutboxMessageParams) error {
+	_, err := q.db.ExecContext(ctx, releaseOutboxMessage, arg.ID, arg.AvailableAt)
+	return err
+}
+
+const retryOutboxMessage = `-- name: RetryOutboxMessage :exec
+UPDATE outbox_messages
+SET available_at = $2,
+    last_error   = $3
+WHERE id = $1
+`
+
+type RetryOutboxMessageParams struct {
+	ID          int64
+	AvailableAt time.Time
+	LastError   sql.NullString
+}
+
+func (q *Queries) RetryOutboxMessage(ctx context.Context, arg RetryOutboxMessageParams) error {
+	_, err := q.db.ExecContext(ctx, retryOutboxMessage, arg.ID, arg.AvailableAt, arg.LastError)
+	return err
+}
+
+const revokeOtherUserSessions = `-- name: RevokeOtherUserSessions :execrows
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE user_id = $1
+  AND id <> $2
+  AND revoked_at IS NULL
+  AND expiry > NOW()
+`
+
+type RevokeOtherUserSessionsParams struct {
+	UserID int64
+	ID     uuid.UUID
+}
+
+func (q *Queries) RevokeOtherUserSessions(ctx context.Context, arg RevokeOtherUserSessionsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeOtherUserSessions, arg.UserID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeRefreshToken = `-- name: RevokeRefreshToken :execrows
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE id = $1
+  AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeRefreshToken(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeRefreshToken, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE family_id = $1
+  AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, revokeRefreshTokenFamily, familyID)
+	return err
+}
+
+const revokeSession = `-- name: RevokeSession :exec
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE id = $1
+  AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeSession(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, revokeSession, id)
+	return err
+}
+
+const revokeUserRefreshTokens = `-- name: RevokeUserRefreshTokens :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE user_id = $1
+  AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeUserRefreshTokens(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, revokeUserRefreshTokens, userID)
+	return err
+}
+
+const revokeUserSession = `-- name: RevokeUserSession :execrows
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL
+  AND expiry > NOW()
+`
+
+type RevokeUserSessionParams struct {
+	ID     uuid.UUID
+	UserID int64
+}
+
+func (q *Queries) RevokeUserSession(ctx context.Context, arg RevokeUserSessionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeUserSession, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const getPostByID = `-- name: GetPostByID :one
-SELECT id,
-       content,
-       title,
-       user_id,
-       created_at,
-       updated_at,
-       tags,
-       version
-FROM posts
+const revokeUserSessions = `-- name: RevokeUserSessions :exec
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE user_id = $1
+  AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeUserSessions(ctx context.Context, userID int64) error {
+	_, err := q.db.ExecContext(ctx, revokeUserSessions, userID)
+	return err
+}
+
+const setUserActive = `-- name: SetUserActive :exec
+UPDATE users
+SET is_active    = $2,
+    activated_at = CASE WHEN $2 THEN COALESCE(activated_at, NOW()) ELSE activated_at END
 WHERE id = $1
 `
 
-type GetPostByIDRow struct {
-	ID        int64
-	Content   string
-	Title     string
-	UserID    int64
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Tags      []string
-	Version   sql.NullInt32
+type SetUserActiveParams struct {
+	ID       int64
+	IsActive bool
 }
 
-func (q *Queries) GetPostByID(ctx context.Context, id int64) (GetPostByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getPostByID, id)
-	var i GetPostByIDRow
-	err := row.Scan(
-		&i.ID,
-		&i.Content,
-		&i.Title,
-		&i.UserID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		pq.Array(&i.Tags),
-		&i.Version,
-	)
-	return i, err
+func (q *Queries) SetUserActive(ctx context.Context, arg SetUserActiveParams) error {
+	_, err := q.db.ExecContext(ctx, setUserActive, arg.ID, arg.IsActive)
+	return err
 }
 
-const getRoleByName = `-- name: GetRoleByName :one
-SELECT id, name, description, level
-FROM roles
-WHERE name = $1
+const setUserBannedAt = `-- name: SetUserBannedAt :exec
+UPDATE users
+SET banned_at = $1
+WHERE id = $2
 `
 
-type GetRoleByNameRow struct {
-	ID          int64
-	Name        string
-	Description sql.NullString
-	Level       int32
+type SetUserBannedAtParams struct {
+	BannedAt sql.NullTime
+	ID       int64
 }
 
-func (q *Queries) GetRoleByName(ctx context.Context, name string) (GetRoleByNameRow, error) {
-	row := q.db.QueryRowContext(ctx, getRoleByName, name)
-	var i GetRoleByNameRow
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Description,
-		&i.Level,
-	)
-	return i, err
+func (q *Queries) SetUserBannedAt(ctx context.Context, arg SetUserBannedAtParams) error {
+	_, err := q.db.ExecContext(ctx, setUserBannedAt, arg.BannedAt, arg.ID)
+	return err
 }
 
-const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, password, username, created_at, is_active
-FROM users
-WHERE email = $1
+const takeOIDCLoginState = `-- name: TakeOIDCLoginState :one
+DELETE
+FROM oidc_login_states
+WHERE state = $1
+  AND expiry > NOW()
+RETURNING provider, nonce, code_verifier, expiry
 `
 
-type GetUserByEmailRow struct {
-	ID        int64
-	Email     string
-	Password  []byte
-	Username  string
-	CreatedAt time.Time
-	IsActive  bool
+type TakeOIDCLoginStateRow struct {
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	Expiry       time.Time
 }
 
-func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error) {
-	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
-	var i GetUserByEmailRow
+func (q *Queries) TakeOIDCLoginState(ctx context.Context, state []byte) (TakeOIDCLoginStateRow, error) {
+	row := q.db.QueryRowContext(ctx, takeOIDCLoginState, state)
+	var i TakeOIDCLoginStateRow
 	err := row.Scan(
-		&i.ID,
-		&i.Email,
-		&i.Password,
-		&i.Username,
-		&i.CreatedAt,
-		&i.IsActive,
+		&i.Provider,
+		&i.Nonce,
+		&i.CodeVerifier,
+		&i.Expiry,
 	)
 	return i, err
 }
 
-const getUserByID = `-- name: GetUserByID :one
-SELECT users.id,
-       users.username,
-       users.email,
-       users.created_at,
-       users.is_active,
-       r.id          as role_id,
-       r.name        as role_name,
-       r.description as role_description,
-       r.level       as role_level
-FROM users
-         JOIN roles r ON (users.role_id = r.id)
-WHERE users.id = $1
+const touchPersonalAccessToken = `-- name: TouchPersonalAccessToken :exec
+UPDATE personal_access_tokens
+SET last_used_at = $1
+WHERE id = $2
 `
 
-type GetUserByIDRow struct {
-	ID              int64
-	Username        string
-	Email           string
-	CreatedAt       time.Time
-	IsActive        bool
-	RoleID          int64
-	RoleName        string
-	RoleDescription sql.NullString
-	RoleLevel       int32
+type TouchPersonalAccessTokenParams struct {
+	UsedAt sql.NullTime
+	ID     int64
 }
 
-func (q *Queries) GetUserByID(ctx context.Context, id int64) (GetUserByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getUserByID, id)
-	var i GetUserByIDRow
-	err := row.Scan(
-		&i.ID,
-		&i.Username,
-		&i.Email,
-		&i.CreatedAt,
-		&i.IsActive,
-		&i.RoleID,
-		&i.RoleName,
-		&i.RoleDescription,
-		&i.RoleLevel,
-	)
-	return i, err
+func (q *Queries) TouchPersonalAccessToken(ctx context.Context, arg TouchPersonalAccessTokenParams) error {
+	_, err := q.db.ExecContext(ctx, touchPersonalAccessToken, arg.UsedAt, arg.ID)
+	return err
 }
 
-const getUserFeed = `-- name: GetUserFeed :many
-SELECT p.id,
-       p.user_id,
-       p.title,
-       p.content,
-       p.created_at,
-       p.tags,
-       COUNT(c.id) AS comments_count,
-       u.username
-FROM posts p
-         LEFT JOIN comments c ON c.post_id = p.id
-         LEFT JOIN users u ON p.user_id = u.id
-    OR p.user_id = $1
-WHERE p.user_id = $1
-  AND ($4 = '' OR LOWER(p.title) LIKE LOWER('%' || $4 || '%') OR LOWER(p.content) LIKE LOWER('%' || $4 || '%'))
-  AND (p.tags @> $5 OR $5 = '{}')
-GROUP BY p.id, u.username
-ORDER BY p.created_at DESC
-LIMIT $2 OFFSET $3
+const touchSession = `-- name: TouchSession :exec
+UPDATE sessions
+SET last_used_at = $1,
+    ip           = $2
+WHERE id = $3
 `
 
-type GetUserFeedParams struct {
-	UserID  int64
-	Limit   int32
-	Offset  int32
-	Column4 interface{}
-	Tags    []string
+type TouchSessionParams struct {
+	UsedAt time.Time
+	Ip     string
+	ID     uuid.UUID
 }
 
-type GetUserFeedRow struct {
-	ID            int64
-	UserID        int64
-	Title         string
-	Content       string
-	CreatedAt     time.Time
-	Tags          []string
-	CommentsCount int64
-	Username      sql.NullString
+func (q *Queries) TouchSession(ctx context.Context, arg TouchSessionParams) error {
+	_, err := q.db.ExecContext(ctx, touchSession, arg.UsedAt, arg.Ip, arg.ID)
+	return err
 }
 
-func (q *Queries) GetUserFeed(ctx context.Context, arg GetUserFeedParams) ([]GetUserFeedRow, error) {
-	rows, err := q.db.QueryContext(ctx, getUserFeed,
-		arg.UserID,
-		arg.Limit,
-		arg.Offset,
-		arg.Column4,
-		pq.Array(arg.Tags),
-	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetUserFeedRow
-	for rows.Next() {
-		var i GetUserFeedRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.Title,
-			&i.Content,
-			&i.CreatedAt,
-			pq.Array(&i.Tags),
-			&i.CommentsCount,
-			&i.Username,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+const updateComment = `-- name: UpdateComment :one
+UPDATE comments
+SET content    = $1,
+    updated_at = NOW()
+WHERE id = $2
+RETURNING updated_at
+`
+
+type UpdateCommentParams struct {
+	Content sql.NullString
+	ID      int64
+}
+
+func (q *Queries) UpdateComment(ctx context.Context, arg UpdateCommentParams) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, updateComment, arg.Content, arg.ID)
+	var updated_at time.Time
+	err := row.Scan(&updated_at)
+	return updated_at, err
 }
 
 const updatePost = `-- name: UpdatePost :one
@@ -514,3 +2240,130 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (sql.Nul
 	err := row.Scan(&version)
 	return version, err
 }
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET password = $2
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID       int64
+	Password []byte
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPassword, arg.ID, arg.Password)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :execrows
+UPDATE users
+SET display_name = COALESCE($1, display_name),
+    bio          = COALESCE($2, bio),
+    location     = COALESCE($3, location),
+    website      = COALESCE($4, website),
+    avatar_url   = COALESCE($5, avatar_url)
+WHERE id = $6
+`
+
+type UpdateUserProfileParams struct {
+	DisplayName sql.NullString
+	Bio         sql.NullString
+	Location    sql.NullString
+	Website     sql.NullString
+	AvatarUrl   sql.NullString
+	ID          int64
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserProfile,
+		arg.DisplayName,
+		arg.Bio,
+		arg.Location,
+		arg.Website,
+		arg.AvatarUrl,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUserRole = `-- name: UpdateUserRole :exec
+UPDATE users
+SET role_id = $2
+WHERE id = $1
+`
+
+type UpdateUserRoleParams struct {
+	ID     int64
+	RoleID int32
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserRole, arg.ID, arg.RoleID)
+	return err
+}
+
+const upsertUserTOTP = `-- name: UpsertUserTOTP :exec
+INSERT INTO user_totp (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+    SET secret         = EXCLUDED.secret,
+        confirmed_at   = NULL,
+        last_used_step = 0,
+        created_at     = NOW()
+`
+
+type UpsertUserTOTPParams struct {
+	UserID int64
+	Secret string
+}
+
+func (q *Queries) UpsertUserTOTP(ctx context.Context, arg UpsertUserTOTPParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserTOTP, arg.UserID, arg.Secret)
+	return err
+}
+
+const useUserRecoveryCode = `-- name: UseUserRecoveryCode :execrows
+UPDATE user_recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1
+  AND code = $2
+  AND used_at IS NULL
+`
+
+type UseUserRecoveryCodeParams struct {
+	UserID int64
+	Code   []byte
+}
+
+func (q *Queries) UseUserRecoveryCode(ctx context.Context, arg UseUserRecoveryCodeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useUserRecoveryCode, arg.UserID, arg.Code)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const useUserTOTPStep = `-- name: UseUserTOTPStep :execrows
+UPDATE user_totp
+SET last_used_step = $1
+WHERE user_id = $2
+  AND last_used_step < $1
+`
+
+type UseUserTOTPStepParams struct {
+	Step   int64
+	UserID int64
+}
+
+func (q *Queries) UseUserTOTPStep(ctx context.Context, arg UseUserTOTPStepParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useUserTOTPStep, arg.Step, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -11,22 +11,40 @@ import (
 const QueryTimeoutDuration = 5 * time.Second
 
 type Storage struct {
-	Posts    domain.PostsRepository
-	Users    domain.UsersRepository
-	Comments domain.CommentsRepository
-	Follows  domain.FollowsRepository
-	Roles    domain.RolesRepository
-	Feed     domain.FeedRepository
+	Posts          domain.PostsRepository
+	Users          domain.UsersRepository
+	Comments       domain.CommentsRepository
+	Follows        domain.FollowsRepository
+	Roles          domain.RolesRepository
+	Feed           domain.FeedRepository
+	Reactions      domain.ReactionsRepository
+	Outbox         domain.OutboxRepository
+	RefreshTokens  domain.RefreshTokensRepository
+	RevokedTokens  domain.RevokedTokensRepository
+	MFA            domain.MFARepository
+	Audit          domain.AuditRepository
+	PersonalTokens domain.PersonalTokensRepository
+	OIDC           domain.OIDCRepository
+	Sessions       domain.SessionsRepository
 }
 
 func NewStorage(db *sql.DB) Storage {
 	return Storage{
-		Posts:    &PostStore{sqlc.New(db)},
-		Users:    &UserStore{db, sqlc.New(db)},
-		Comments: &CommentStore{sqlc.New(db)},
-		Follows:  &FollowsStore{sqlc.New(db)},
-		Roles:    &RolesStore{queries: sqlc.New(db)},
-		Feed:     &FeedStore{sqlc.New(db)},
+		Posts:          &PostStore{sqlc.New(db)},
+		Users:          &UserStore{db, sqlc.New(db)},
+		Comments:       &CommentStore{sqlc.New(db)},
+		Follows:        &FollowsStore{sqlc.New(db)},
+		Roles:          &RolesStore{queries: sqlc.New(db)},
+		Feed:           &FeedStore{sqlc.New(db)},
+		Reactions:      &ReactionStore{sqlc.New(db)},
+		Outbox:         &OutboxStore{sqlc.New(db)},
+		RefreshTokens:  &RefreshTokenStore{db, sqlc.New(db)},
+		RevokedTokens:  &RevokedTokenStore{sqlc.New(db)},
+		MFA:            &MFAStore{db, sqlc.New(db)},
+		Audit:          &AuditStore{sqlc.New(db)},
+		PersonalTokens: &PersonalTokenStore{sqlc.New(db)},
+		OIDC:           &OIDCStore{db, sqlc.New(db), &UserStore{db, sqlc.New(db)}},
+		Sessions:       &SessionStore{db, sqlc.New(db)},
 	}
 }
 
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/sergdort/Social/business/domain"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+	"time"
+)
+
+type RefreshTokenStore struct {
+	db      *sql.DB
+	queries *sqlc2.Queries
+}
+
+func (s *RefreshTokenStore) Create(ctx context.Context, token string, userID int64, familyID uuid.UUID, expiry time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.CreateRefreshToken(ctx, sqlc2.CreateRefreshTokenParams{
+		Token:    []byte(token),
+		UserID:   userID,
+		FamilyID: familyID,
+		Expiry:   expiry,
+	})
+}
+
+func (s *RefreshTokenStore) GetByToken(ctx context.Context, token string) (*domain.RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	row, err := s.queries.GetRefreshTokenByToken(ctx, []byte(token))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, domain.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	refreshToken := &domain.RefreshToken{
+		ID:       row.ID,
+		UserID:   row.UserID,
+		FamilyID: row.FamilyID,
+		Expiry:   row.Expiry,
+	}
+	if row.RevokedAt.Valid {
+		refreshToken.RevokedAt = &row.RevokedAt.Time
+	}
+	return refreshToken, nil
+}
+
+func (s *RefreshTokenStore) Rotate(ctx context.Context, old *domain.RefreshToken, token string, expiry time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		queries := s.queries.WithTx(tx)
+
+		rows, err := queries.RevokeRefreshToken(ctx, old.ID)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return domain.ErrNotFound
+		}
+
+		err = queries.CreateRefreshToken(ctx, sqlc2.CreateRefreshTokenParams{
+			Token:    []byte(token),
+			UserID:   old.UserID,
+			FamilyID: old.FamilyID,
+			Expiry:   expiry,
+		})
+		if err != nil {
+			return err
+		}
+
+		return queries.ExtendSession(ctx, sqlc2.ExtendSessionParams{
+			Expiry: expiry,
+			ID:     old.FamilyID,
+		})
+	})
+}
+
+func (s *RefreshTokenStore) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		queries := s.queries.WithTx(tx)
+
+		if err := queries.RevokeRefreshTokenFamily(ctx, familyID); err != nil {
+			return err
+		}
+		return queries.RevokeSession(ctx, familyID)
+	})
+}
+
+type RevokedTokenStore struct {
+	queries *sqlc2.Queries
+}
+
+// Revoke adds the token to the denylist. Tokens past their expiry are
+// rejected anyway, so they are pruned on the way.
+func (s *RevokedTokenStore) Revoke(ctx context.Context, jti string, expiry time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	if err := s.queries.DeleteExpiredRevokedTokens(ctx); err != nil {
+		return err
+	}
+
+	return s.queries.CreateRevokedToken(ctx, sqlc2.CreateRevokedTokenParams{
+		Jti:    jti,
+		Expiry: expiry,
+	})
+}
+
+func (s *RevokedTokenStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.IsTokenRevoked(ctx, jti)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"github.com/sergdort/Social/business/domain"
 	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+	"github.com/sergdort/Social/foundation/slices"
 	"time"
 )
 
@@ -56,6 +57,40 @@ func (s *UserStore) RevertCreateAndInvite(ctx context.Context, id int64) error {
 	})
 }
 
+func (s *UserStore) Reinvite(
+	ctx context.Context,
+	userID int64,
+	token string,
+	expiration time.Duration,
+	invitation domain.OutboxMessage,
+) error {
+	return withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		if err := s.deleteInvitation(ctx, userID, tx); err != nil {
+			return err
+		}
+
+		if err := s.createUserInvitation(ctx, tx, token, userID, expiration); err != nil {
+			return err
+		}
+
+		return createOutboxMessage(ctx, tx, s.queries, invitation)
+	})
+}
+
+func (s *UserStore) DeleteExpiredInvitations(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.DeleteExpiredUserInvitations(ctx)
+}
+
+func (s *UserStore) DeleteUnactivated(ctx context.Context, createdBefore time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.DeleteUnactivatedUsers(ctx, createdBefore)
+}
+
 func (s *UserStore) GetByID(ctx context.Context, id int64) (*domain.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
@@ -71,18 +106,27 @@ func (s *UserStore) GetByID(ctx context.Context, id int64) (*domain.User, error)
 	}
 
 	return &domain.User{
-		ID:        row.ID,
-		Username:  row.Username,
-		Email:     row.Email,
-		CreatedAt: row.CreatedAt.String(),
-		IsActive:  row.IsActive,
-		RoleID:    row.RoleID,
+		ID:          row.ID,
+		Username:    row.Username,
+		Email:       row.Email,
+		CreatedAt:   row.CreatedAt.String(),
+		IsActive:    row.IsActive,
+		BannedAt:    fromNullTime(row.BannedAt),
+		ActivatedAt: fromNullTime(row.ActivatedAt),
+		RoleID:      row.RoleID,
 		Role: domain.Role{
 			ID:          row.RoleID,
 			Name:        row.RoleName,
 			Description: row.RoleDescription.String,
 			Level:       int64(row.RoleLevel),
 		},
+		Profile: domain.Profile{
+			DisplayName: row.DisplayName,
+			Bio:         row.Bio,
+			Location:    row.Location,
+			Website:     row.Website,
+			AvatarURL:   row.AvatarUrl,
+		},
 	}, nil
 }
 
@@ -92,14 +136,21 @@ func (s *UserStore) GetByEmail(ctx context.Context, email string) (*domain.User,
 
 	row, err := s.queries.GetUserByEmail(ctx, email)
 	if err != nil {
-		return nil, err
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, domain.ErrNotFound
+		default:
+			return nil, err
+		}
 	}
 	user := domain.User{
-		ID:        row.ID,
-		Username:  row.Username,
-		Email:     row.Email,
-		CreatedAt: row.CreatedAt.String(),
-		IsActive:  row.IsActive,
+		ID:          row.ID,
+		Username:    row.Username,
+		Email:       row.Email,
+		CreatedAt:   row.CreatedAt.String(),
+		IsActive:    row.IsActive,
+		BannedAt:    fromNullTime(row.BannedAt),
+		ActivatedAt: fromNullTime(row.ActivatedAt),
 		Password: domain.Password{
 			Hash: row.Password,
 		},
@@ -107,7 +158,13 @@ func (s *UserStore) GetByEmail(ctx context.Context, email string) (*domain.User,
 	return &user, nil
 }
 
-func (s *UserStore) CreateAndInvite(ctx context.Context, user *domain.User, token string, expiration time.Duration) error {
+func (s *UserStore) CreateAndInvite(
+	ctx context.Context,
+	user *domain.User,
+	token string,
+	expiration time.Duration,
+	invitation domain.OutboxMessage,
+) error {
 	return withTransaction(s.db, ctx, func(tx *sql.Tx) error {
 		if err := s.Create(ctx, tx, user); err != nil {
 			return err
@@ -116,40 +173,258 @@ func (s *UserStore) CreateAndInvite(ctx context.Context, user *domain.User, toke
 		if err := s.createUserInvitation(ctx, tx, token, user.ID, expiration); err != nil {
 			return err
 		}
+
+		if err := createOutboxMessage(ctx, tx, s.queries, invitation); err != nil {
+			return err
+		}
 		return nil
 	})
 }
 
-func (s *UserStore) Activate(ctx context.Context, token string) error {
-	return withTransaction(s.db, ctx, func(tx *sql.Tx) error {
-		if err := s.activateUserByInvitationToken(ctx, token, tx); err != nil {
+func (s *UserStore) Activate(ctx context.Context, token string) (int64, error) {
+	var userID int64
+	err := withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		id, err := s.activateUserByInvitationToken(ctx, token, tx)
+		if err != nil {
 			return err
 		}
 		if err := s.deleteUserInvitation(ctx, tx, token); err != nil {
 			return err
 		}
+		userID = id
 		return nil
 	})
+	return userID, err
+}
+
+func (s *UserStore) CreatePasswordReset(
+	ctx context.Context,
+	userID int64,
+	token string,
+	expiration time.Duration,
+	email domain.OutboxMessage,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		queries := s.queries.WithTx(tx)
+
+		if err := queries.DeletePasswordResetsByUserID(ctx, userID); err != nil {
+			return err
+		}
+
+		err := queries.CreatePasswordReset(ctx, sqlc2.CreatePasswordResetParams{
+			Token:  []byte(token),
+			UserID: userID,
+			Expiry: time.Now().Add(expiration),
+		})
+		if err != nil {
+			return err
+		}
+
+		return createOutboxMessage(ctx, tx, s.queries, email)
+	})
+}
+
+func (s *UserStore) ResetPassword(ctx context.Context, token string, password []byte) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var userID int64
+	err := withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		queries := s.queries.WithTx(tx)
+
+		id, err := queries.ConsumePasswordReset(ctx, sqlc2.ConsumePasswordResetParams{
+			Token:  []byte(token),
+			Expiry: time.Now(),
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return domain.ErrNotFound
+			default:
+				return err
+			}
+		}
+
+		if err := setPassword(ctx, queries, id, password); err != nil {
+			return err
+		}
+		userID = id
+		return nil
+	})
+	return userID, err
+}
+
+// setPassword sets the password of the user and logs them out everywhere, so
+// whoever knew the old password loses the access they got with it.
+func setPassword(ctx context.Context, queries *sqlc2.Queries, id int64, password []byte) error {
+	err := queries.UpdateUserPassword(ctx, sqlc2.UpdateUserPasswordParams{
+		ID:       id,
+		Password: password,
+	})
+	if err != nil {
+		return err
+	}
+	return revokeUserAccess(ctx, queries, id)
+}
+
+func (s *UserStore) List(ctx context.Context, query domain.UsersQuery) (domain.UsersPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.queries.ListUsers(ctx, sqlc2.ListUsersParams{
+		Search:     query.Search,
+		PageLimit:  int32(query.Limit),
+		PageOffset: int32(query.Offset),
+	})
+	if err != nil {
+		return domain.UsersPage{}, err
+	}
+
+	total, err := s.queries.CountUsers(ctx, query.Search)
+	if err != nil {
+		return domain.UsersPage{}, err
+	}
+
+	users := slices.Map(rows, func(row sqlc2.ListUsersRow) domain.User {
+		return domain.User{
+			ID:          row.ID,
+			Username:    row.Username,
+			Email:       row.Email,
+			CreatedAt:   row.CreatedAt.String(),
+			IsActive:    row.IsActive,
+			BannedAt:    fromNullTime(row.BannedAt),
+			ActivatedAt: fromNullTime(row.ActivatedAt),
+			RoleID:      row.RoleID,
+			Role: domain.Role{
+				ID:          row.RoleID,
+				Name:        row.RoleName,
+				Description: row.RoleDescription.String,
+				Level:       int64(row.RoleLevel),
+			},
+		}
+	})
+	return domain.UsersPage{Users: users, Total: total}, nil
+}
+
+func (s *UserStore) UpdateRole(ctx context.Context, id int64, roleID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.UpdateUserRole(ctx, sqlc2.UpdateUserRoleParams{
+		ID:     id,
+		RoleID: int32(roleID),
+	})
+}
+
+func (s *UserStore) UpdateProfile(ctx context.Context, id int64, payload domain.UpdateProfilePayload) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.queries.UpdateUserProfile(ctx, sqlc2.UpdateUserProfileParams{
+		DisplayName: toNullString(payload.DisplayName),
+		Bio:         toNullString(payload.Bio),
+		Location:    toNullString(payload.Location),
+		Website:     toNullString(payload.Website),
+		AvatarUrl:   toNullString(payload.AvatarURL),
+		ID:          id,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (s *UserStore) SetActive(ctx context.Context, id int64, active bool) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		queries := s.queries.WithTx(tx)
+
+		err := queries.SetUserActive(ctx, sqlc2.SetUserActiveParams{
+			ID:       id,
+			IsActive: active,
+		})
+		if err != nil {
+			return err
+		}
+
+		if active {
+			return nil
+		}
+		return revokeUserAccess(ctx, queries, id)
+	})
+}
+
+func (s *UserStore) Ban(ctx context.Context, id int64, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTransaction(s.db, ctx, func(tx *sql.Tx) error {
+		queries := s.queries.WithTx(tx)
+
+		err := queries.SetUserBannedAt(ctx, sqlc2.SetUserBannedAtParams{
+			ID:       id,
+			BannedAt: sql.NullTime{Time: at, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		return revokeUserAccess(ctx, queries, id)
+	})
+}
+
+// revokeUserAccess logs the user out everywhere: their refresh tokens and
+// sessions are revoked and their personal access tokens deleted.
+func revokeUserAccess(ctx context.Context, queries *sqlc2.Queries, id int64) error {
+	if err := queries.RevokeUserRefreshTokens(ctx, id); err != nil {
+		return err
+	}
+	if err := queries.RevokeUserSessions(ctx, id); err != nil {
+		return err
+	}
+	return queries.DeleteUserPersonalAccessTokens(ctx, id)
 }
 
-func (s *UserStore) activateUserByInvitationToken(ctx context.Context, token string, tx *sql.Tx) error {
+func (s *UserStore) Unban(ctx context.Context, id int64) error {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	err := s.queries.WithTx(tx).ActiveUserByInvitationToken(ctx, sqlc2.ActiveUserByInvitationTokenParams{
+	return s.queries.SetUserBannedAt(ctx, sqlc2.SetUserBannedAtParams{ID: id})
+}
+
+func (s *UserStore) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.queries.DeleteUserByID(ctx, id)
+}
+
+func (s *UserStore) activateUserByInvitationToken(ctx context.Context, token string, tx *sql.Tx) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	userID, err := s.queries.WithTx(tx).ActiveUserByInvitationToken(ctx, sqlc2.ActiveUserByInvitationTokenParams{
 		Token:  []byte(token),
 		Expiry: time.Now(),
 	})
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return domain.ErrNotFound
+			return 0, domain.ErrNotFound
 		default:
-			return err
+			return 0, err
 		}
 	}
 
-	return nil
+	return userID, nil
 }
 
 func (s *UserStore) createUserInvitation(
@@ -195,3 +470,17 @@ func (s *UserStore) deleteInvitation(ctx context.Context, userID int64, tx *sql.
 	err := s.queries.WithTx(tx).DeleteUserInvitationByUserID(ctx, userID)
 	return err
 }
+
+func toNullString(v *string) sql.NullString {
+	if v == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *v, Valid: true}
+}
+
+func fromNullTime(v sql.NullTime) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Time
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/sergdort/Social/business/domain"
+	sqlc2 "github.com/sergdort/Social/business/platform/store/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRevokeUserAccess(t *testing.T) {
+	t.Run("it should revoke the refresh tokens, sessions and personal tokens of the user", func(t *testing.T) {
+		id := int64(42)
+		mockDB := sqlc2.NewMockDBTX(t)
+		mockDB.On("ExecContext", mock.Anything, mock.Anything, mock.Anything).Return(&FakeSqlResult{}, nil)
+
+		err := revokeUserAccess(context.Background(), sqlc2.New(mockDB), id)
+
+		assert.NoError(t, err)
+		for _, name := range []string{"RevokeUserRefreshTokens", "RevokeUserSessions", "DeleteUserPersonalAccessTokens"} {
+			mockDB.AssertCalled(t, "ExecContext", mock.Anything, mock.MatchedBy(func(query string) bool {
+				return strings.HasPrefix(query, "-- name: "+name+" ")
+			}), id)
+		}
+		mockDB.AssertNumberOfCalls(t, "ExecContext", 3)
+	})
+}
+
+func TestSetPassword(t *testing.T) {
+	t.Run("it should set the password and revoke the access of the user", func(t *testing.T) {
+		id := int64(42)
+		password := []byte("hash")
+		mockDB := sqlc2.NewMockDBTX(t)
+		mockDB.On("ExecContext", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&FakeSqlResult{}, nil)
+		mockDB.On("ExecContext", mock.Anything, mock.Anything, mock.Anything).Return(&FakeSqlResult{}, nil)
+
+		err := setPassword(context.Background(), sqlc2.New(mockDB), id, password)
+
+		assert.NoError(t, err)
+		mockDB.AssertCalled(t, "ExecContext", mock.Anything, mock.MatchedBy(func(query string) bool {
+			return strings.HasPrefix(query, "-- name: UpdateUserPassword ")
+		}), mock.Anything, mock.Anything)
+		for _, name := range []string{"RevokeUserRefreshTokens", "RevokeUserSessions", "DeleteUserPersonalAccessTokens"} {
+			mockDB.AssertCalled(t, "ExecContext", mock.Anything, mock.MatchedBy(func(query string) bool {
+				return strings.HasPrefix(query, "-- name: "+name+" ")
+			}), id)
+		}
+	})
+}
+
+func TestUserStore_UpdateProfile(t *testing.T) {
+	t.Run("it should only write the fields of the payload", func(t *testing.T) {
+		bio := "Not today."
+		website := ""
+		mockDB := sqlc2.NewMockDBTX(t)
+		mockDB.On(
+			"ExecContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(&FakeSqlResult{AffectedRows: 1}, nil)
+		store := UserStore{queries: sqlc2.New(mockDB)}
+
+		err := store.UpdateProfile(context.Background(), 42, domain.UpdateProfilePayload{Bio: &bio, Website: &website})
+
+		assert.NoError(t, err)
+		mockDB.AssertCalled(
+			t,
+			"ExecContext",
+			mock.Anything,
+			mock.MatchedBy(func(query string) bool {
+				return strings.HasPrefix(query, "-- name: UpdateUserProfile ") &&
+					strings.Contains(query, "bio          = COALESCE($2, bio)")
+			}),
+			sql.NullString{},
+			sql.NullString{String: bio, Valid: true},
+			sql.NullString{},
+			sql.NullString{String: "", Valid: true},
+			sql.NullString{},
+			int64(42),
+		)
+	})
+
+	t.Run("it should return NotFound for an unknown user", func(t *testing.T) {
+		mockDB := sqlc2.NewMockDBTX(t)
+		mockDB.On(
+			"ExecContext",
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+			mock.Anything,
+		).Return(&FakeSqlResult{}, nil)
+		store := UserStore{queries: sqlc2.New(mockDB)}
+
+		err := store.UpdateProfile(context.Background(), 42, domain.UpdateProfilePayload{})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
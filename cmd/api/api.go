@@ -2,16 +2,20 @@ package main
 
 import (
 	"context"
+	"github.com/sergdort/Social/app/domain/adminapp"
 	"github.com/sergdort/Social/app/domain/authapp"
+	"github.com/sergdort/Social/app/domain/commentsapp"
 	"github.com/sergdort/Social/app/domain/feedapp"
 	"github.com/sergdort/Social/app/domain/postsapp"
 	"github.com/sergdort/Social/app/domain/usersapp"
 	"github.com/sergdort/Social/app/shared/mid"
 	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/business/platform/jwt"
 	"github.com/sergdort/Social/business/platform/mailer"
 	s "github.com/sergdort/Social/business/platform/store"
 	"github.com/sergdort/Social/business/platform/store/cache"
 	"github.com/sergdort/Social/docs" // This is required to generate Swagger docs
+	"github.com/sergdort/Social/foundation/cursor"
 	"github.com/sergdort/Social/foundation/logger"
 	"github.com/sergdort/Social/foundation/otel"
 	"github.com/sergdort/Social/foundation/web"
@@ -33,14 +37,19 @@ type application struct {
 	logger  *logger.Logger
 	mailer  mailer.Mailer
 	cache   cache.Storage
+	jwtAuth *jwt.JWTAutheticator
 	useCase useCases
 }
 
 type useCases struct {
-	Users *domain.UsersUseCase
-	Auth  *domain.AuthUseCase
-	Feed  domain.FeedRepository
-	Posts domain.PostsRepository
+	Users      *domain.UsersUseCase
+	Auth       *domain.AuthUseCase
+	Authorizer *domain.Authorizer
+	Admin      *domain.AdminUseCase
+	OIDC       *domain.OIDCUseCase
+	Audit      domain.AuditLogger
+	Feed       domain.FeedRepository
+	Posts      domain.PostsRepository
 }
 
 type redisConfig struct {
@@ -62,17 +71,50 @@ type config struct {
 	auth            authConfig
 	redisCfg        redisConfig
 	serviceName     string
+	pagination      paginationConfig
+	outbox          outboxConfig
+	cleanup         invitationCleanupConfig
+	// trustProxy takes the client IP from X-Forwarded-For, only for
+	// deployments behind a proxy setting it.
+	trustProxy bool
 }
 
 type mailConfig struct {
 	exp            time.Duration
 	fromEmail      string
+	provider       string
 	sendGridConfig sendGridConfig
+	smtp           smtpConfig
+	inboxDir       string
 }
 
 type authConfig struct {
-	basic basicAuthConfig
-	jwt   jwtAuthConfig
+	basic            basicAuthConfig
+	jwt              jwtAuthConfig
+	passwordResetExp time.Duration
+	loginThrottle    loginThrottleConfig
+	oidc             oidcConfig
+}
+
+// oidcConfig configures logging in with an OpenID Connect provider, disabled
+// without an issuer.
+type oidcConfig struct {
+	providerName string
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	stateExp     time.Duration
+}
+
+type loginThrottleConfig struct {
+	maxAccountFailures int
+	maxIPFailures      int
+	window             time.Duration
+	lockout            time.Duration
+	maxLockout         time.Duration
+	delay              time.Duration
+	maxDelay           time.Duration
 }
 type basicAuthConfig struct {
 	username string
@@ -80,15 +122,33 @@ type basicAuthConfig struct {
 }
 
 type jwtAuthConfig struct {
-	secret    string
-	exp       time.Duration
-	tokenHost string
+	secret     string
+	exp        time.Duration
+	refreshExp time.Duration
+	tokenHost  string
+	keysDir    string
+	activeKID  string
+	// hs256Fallback keeps accepting tokens signed with the secret while
+	// switching to the keys of keysDir.
+	hs256Fallback bool
 }
 
 type sendGridConfig struct {
 	apiKey string
 }
 
+type smtpConfig struct {
+	host     string
+	port     int
+	username string
+	password string
+	security string
+}
+
+type paginationConfig struct {
+	cursorSecret string
+}
+
 func (app *application) mount(ctx context.Context, log *logger.Logger) http.Handler {
 	traceProvider, teardown, err := otel.InitTracing(log, otel.Config{
 		ServiceName: app.config.frontEndURL,
@@ -109,6 +169,7 @@ func (app *application) mount(ctx context.Context, log *logger.Logger) http.Hand
 			mux.HandleFunc("GET /v1/swagger/", httpSwagger.Handler())
 		},
 		mid.Otel(tracer),
+		mid.ClientInfo(app.config.trustProxy),
 		mid.Logger(log),
 		mid.Errors(log),
 		mid.Metrics(),
@@ -154,10 +215,55 @@ func (app *application) mount(ctx context.Context, log *logger.Logger) http.Hand
 	//	})
 	//})
 
-	authapp.Routes(webApp, authapp.Config{UseCase: app.useCase.Auth})
-	usersapp.Routes(webApp, usersapp.Config{Auth: app.useCase.Auth, UseCase: app.useCase.Users})
-	postsapp.Routes(webApp, postsapp.Config{Auth: app.useCase.Auth, PostsRepo: app.useCase.Posts})
-	feedapp.Routes(webApp, feedapp.Config{Auth: app.useCase.Auth, FeedUseCase: app.useCase.Feed})
+	cursors := cursor.New(app.config.pagination.cursorSecret)
+
+	authapp.Routes(webApp, authapp.Config{
+		UseCase:               app.useCase.Auth,
+		Users:                 app.useCase.Users,
+		ExposeInvitationToken: app.config.env == "development",
+		JWKS:                  app.jwtAuth,
+		RateLimits:            app.cache.RateLimits,
+		OIDC:                  app.useCase.OIDC,
+	})
+	usersapp.Routes(webApp, usersapp.Config{
+		Auth:       app.useCase.Auth,
+		Authorizer: app.useCase.Authorizer,
+		UseCase:    app.useCase.Users,
+	})
+	postsapp.Routes(webApp, postsapp.Config{
+		Auth:       app.useCase.Auth,
+		Users:      app.useCase.Users,
+		Authorizer: app.useCase.Authorizer,
+		PostsRepo:  app.useCase.Posts,
+		Reactions:  app.store.Reactions,
+		Audit:      app.useCase.Audit,
+		RateLimits: app.cache.RateLimits,
+	})
+	feedapp.Routes(webApp, feedapp.Config{
+		Auth:        app.useCase.Auth,
+		Users:       app.useCase.Users,
+		FeedUseCase: app.useCase.Feed,
+		Cursors:     cursors,
+	})
+	commentsapp.Routes(webApp, commentsapp.Config{
+		Auth:         app.useCase.Auth,
+		Users:        app.useCase.Users,
+		Authorizer:   app.useCase.Authorizer,
+		PostsRepo:    app.useCase.Posts,
+		CommentsRepo: app.store.Comments,
+		Reactions:    app.store.Reactions,
+		Audit:        app.useCase.Audit,
+		Cursors:      cursors,
+		RateLimits:   app.cache.RateLimits,
+	})
+	adminapp.Routes(webApp, adminapp.Config{
+		Auth:       app.useCase.Auth,
+		Authorizer: app.useCase.Authorizer,
+		Users:      app.useCase.Users,
+		UseCase:    app.useCase.Admin,
+		Audit:      app.store.Audit,
+		Cursors:    cursors,
+	})
 	defer teardown(ctx)
 
 	return webApp
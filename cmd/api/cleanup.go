@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/logger"
+)
+
+type invitationCleanupConfig struct {
+	interval    time.Duration
+	gracePeriod time.Duration
+}
+
+var (
+	invitationsExpiredDeleted = expvar.NewInt("invitations_expired_deleted")
+	usersUnactivatedPurged    = expvar.NewInt("users_unactivated_purged")
+)
+
+// runInvitationCleanup deletes the expired invitations and the users who never
+// activated their account every interval until the context is cancelled.
+func runInvitationCleanup(ctx context.Context, log *logger.Logger, cfg invitationCleanupConfig, cleaner *domain.InvitationCleaner) {
+	ticker := time.NewTicker(cfg.interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := cleaner.Cleanup(ctx)
+		invitationsExpiredDeleted.Add(result.ExpiredInvitations)
+		usersUnactivatedPurged.Add(result.UnactivatedUsers)
+		if result.ExpiredInvitations > 0 || result.UnactivatedUsers > 0 {
+			log.Info(ctx, "invitation cleanup", "expired_invitations", result.ExpiredInvitations, "unactivated_users", result.UnactivatedUsers)
+		}
+		if err != nil && ctx.Err() == nil {
+			log.Error(ctx, "invitation cleanup", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"expvar"
 	"net/http"
 	"net/http/pprof"
-	
+
 	"github.com/arl/statsviz"
 )
 
@@ -2,12 +2,17 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"expvar"
+	"fmt"
 	"github.com/redis/go-redis/v9"
 	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/business/platform/audit"
 	"github.com/sergdort/Social/business/platform/db"
 	"github.com/sergdort/Social/business/platform/jwt"
 	"github.com/sergdort/Social/business/platform/mailer"
+	"github.com/sergdort/Social/business/platform/oidc"
 	"github.com/sergdort/Social/business/platform/store"
 	"github.com/sergdort/Social/business/platform/store/cache"
 	"github.com/sergdort/Social/cmd/api/debug"
@@ -17,6 +22,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 )
@@ -60,10 +66,19 @@ func main() {
 		apiURL: env.GetString("EXTERNAL_URL", "http://localhost:8080"),
 		mail: mailConfig{
 			exp:       time.Hour * 24 * 3,
-			fromEmail: env.GetString("SENDGRID_FROM_EMAIL", "<EMAIL>"),
+			fromEmail: env.GetString("MAIL_FROM_EMAIL", env.GetString("SENDGRID_FROM_EMAIL", "<EMAIL>")),
+			provider:  env.GetString("MAILER", "sendgrid"),
 			sendGridConfig: sendGridConfig{
 				apiKey: env.GetString("SENDGRID_API_KEY", ""),
 			},
+			smtp: smtpConfig{
+				host:     env.GetString("SMTP_HOST", "localhost"),
+				port:     env.GetInt("SMTP_PORT", 587),
+				username: env.GetString("SMTP_USERNAME", ""),
+				password: env.GetString("SMTP_PASSWORD", ""),
+				security: env.GetString("SMTP_SECURITY", string(mailer.SMTPSecurityStartTLS)),
+			},
+			inboxDir: env.GetString("MAILER_INBOX_DIR", filepath.Join(os.TempDir(), "social-inbox")),
 		},
 		frontEndURL: env.GetString("FRONTEND_URL", "http://localhost:5173"),
 		auth: authConfig{
@@ -72,12 +87,51 @@ func main() {
 				password: env.GetString("AUTH_BASIC_PASSWORD", "admin"),
 			},
 			jwt: jwtAuthConfig{
-				secret:    env.GetString("JWT_SECRET", "secret"),
-				exp:       time.Hour * 24 * 7,
-				tokenHost: env.GetString("JWT_TOKEN_HOST", "social"),
+				secret:        env.GetString("JWT_SECRET", ""),
+				exp:           env.GetDuration("JWT_EXP", 15*time.Minute),
+				refreshExp:    env.GetDuration("JWT_REFRESH_EXP", 30*24*time.Hour),
+				tokenHost:     env.GetString("JWT_TOKEN_HOST", "social"),
+				keysDir:       env.GetString("JWT_KEYS_DIR", ""),
+				activeKID:     env.GetString("JWT_ACTIVE_KID", ""),
+				hs256Fallback: env.GetBool("JWT_HS256_FALLBACK", false),
+			},
+			passwordResetExp: env.GetDuration("PASSWORD_RESET_EXP", time.Hour),
+			loginThrottle: loginThrottleConfig{
+				maxAccountFailures: env.GetInt("LOGIN_MAX_ACCOUNT_FAILURES", 5),
+				maxIPFailures:      env.GetInt("LOGIN_MAX_IP_FAILURES", 20),
+				window:             env.GetDuration("LOGIN_FAILURE_WINDOW", time.Hour),
+				lockout:            env.GetDuration("LOGIN_LOCKOUT", time.Minute),
+				maxLockout:         env.GetDuration("LOGIN_MAX_LOCKOUT", time.Hour),
+				delay:              env.GetDuration("LOGIN_FAILURE_DELAY", 250*time.Millisecond),
+				maxDelay:           env.GetDuration("LOGIN_MAX_FAILURE_DELAY", 2*time.Second),
+			},
+			oidc: oidcConfig{
+				providerName: env.GetString("OIDC_PROVIDER_NAME", "oidc"),
+				issuer:       env.GetString("OIDC_ISSUER", ""),
+				clientID:     env.GetString("OIDC_CLIENT_ID", ""),
+				clientSecret: env.GetString("OIDC_CLIENT_SECRET", ""),
+				redirectURL:  env.GetString("OIDC_REDIRECT_URL", "http://localhost:5173/login/callback"),
+				stateExp:     env.GetDuration("OIDC_STATE_EXP", 10*time.Minute),
 			},
 		},
 		serviceName: env.GetString("SERVICE_NAME", "social"),
+		pagination: paginationConfig{
+			cursorSecret: env.GetString("CURSOR_SECRET", ""),
+		},
+		outbox: outboxConfig{
+			interval:        env.GetDuration("OUTBOX_INTERVAL", 5*time.Second),
+			batchSize:       env.GetInt("OUTBOX_BATCH_SIZE", 50),
+			maxAttempts:     env.GetInt("OUTBOX_MAX_ATTEMPTS", 8),
+			backoff:         env.GetDuration("OUTBOX_BACKOFF", 30*time.Second),
+			maxBackoff:      env.GetDuration("OUTBOX_MAX_BACKOFF", time.Hour),
+			lease:           time.Minute,
+			deliveryTimeout: env.GetDuration("OUTBOX_DELIVERY_TIMEOUT", 15*time.Second),
+		},
+		cleanup: invitationCleanupConfig{
+			interval:    env.GetDuration("INVITATION_CLEANUP_INTERVAL", time.Hour),
+			gracePeriod: env.GetDuration("UNACTIVATED_USER_GRACE_PERIOD", 7*24*time.Hour),
+		},
+		trustProxy: env.GetBool("TRUST_PROXY", false),
 	}
 	ctx := context.Background()
 	var log *logger.Logger
@@ -90,8 +144,37 @@ func main() {
 		return otel.GetTraceID(ctx)
 	}
 	log = logger.NewWithEvents(os.Stdout, logger.LevelInfo, "SOCIAL", traceIDFn, events)
+
+	// Secrets
+	cursorSecret, err := requireSecret("CURSOR_SECRET", cfg.pagination.cursorSecret, cfg.env)
+	if err != nil {
+		log.Error(ctx, "startup", "err", err)
+		os.Exit(1)
+	}
+	cfg.pagination.cursorSecret = cursorSecret
+	// The secret only signs tokens without JWT_KEYS_DIR, or verifies the old
+	// ones while switching to the keys.
+	if cfg.auth.jwt.keysDir == "" || cfg.auth.jwt.hs256Fallback {
+		jwtSecret, err := requireSecret("JWT_SECRET", cfg.auth.jwt.secret, cfg.env)
+		if err != nil {
+			log.Error(ctx, "startup", "err", err)
+			os.Exit(1)
+		}
+		cfg.auth.jwt.secret = jwtSecret
+	}
+
+	if cfg.outbox.deliveryTimeout <= 0 || cfg.outbox.deliveryTimeout >= cfg.outbox.lease {
+		log.Error(ctx, "startup", "err", fmt.Errorf("OUTBOX_DELIVERY_TIMEOUT has to be positive and shorter than the %s outbox lease", cfg.outbox.lease))
+		os.Exit(1)
+	}
+
 	// Mailer
-	mail := mailer.NewSendgridMailer(cfg.mail.fromEmail, cfg.mail.sendGridConfig.apiKey)
+	mail, inbox, err := newMailer(cfg.mail)
+	if err != nil {
+		log.Error(ctx, "startup", "err", err)
+		os.Exit(1)
+	}
+	log.Info(ctx, "Mailer configured", "provider", cfg.mail.provider)
 
 	// Database
 	database, err := db.New(
@@ -125,36 +208,86 @@ func main() {
 
 	s := store.NewStorage(database)
 
-	jwtAuth := jwt.NewJWTAutheticator(
-		cfg.auth.jwt.secret,
-		cfg.auth.jwt.tokenHost,
-		cfg.auth.jwt.tokenHost,
-		cfg.auth.jwt.tokenHost,
-		cfg.auth.jwt.exp,
-	)
+	jwtAuth, err := newJWTAuthenticator(cfg.auth.jwt)
+	if err != nil {
+		log.Error(ctx, "startup", "err", err)
+		os.Exit(1)
+	}
+
+	auditLog := audit.NewLogger(log, s.Audit)
+	users := domain.NewUsersUseCase(cacheStorage.Users, s.Users, s.Follows, auditLog)
 
 	var app = &application{
-		config: cfg,
-		store:  s,
-		logger: log,
-		mailer: mail,
-		cache:  cacheStorage,
+		config:  cfg,
+		store:   s,
+		logger:  log,
+		mailer:  mail,
+		cache:   cacheStorage,
+		jwtAuth: jwtAuth,
 		useCase: useCases{
-			Users: domain.NewUsersUseCase(cacheStorage.Users, s.Users, s.Follows),
+			Users: users,
 			Auth: domain.NewAuthUseCase(
 				domain.AuthConfig{
-					InvitationExp: cfg.mail.exp,
-					FrontendURL:   cfg.frontEndURL,
+					InvitationExp:    cfg.mail.exp,
+					RefreshTokenExp:  cfg.auth.jwt.refreshExp,
+					PasswordResetExp: cfg.auth.passwordResetExp,
+					MFAChallengeExp:  5 * time.Minute,
+					MFAIssuer:        cfg.serviceName,
+					FrontendURL:      cfg.frontEndURL,
 				},
 				s.Roles,
 				s.Users,
+				s.RefreshTokens,
+				s.RevokedTokens,
+				s.MFA,
 				jwtAuth,
 				jwtAuth,
+				mail,
+				auditLog,
+				domain.NewLoginThrottle(
+					domain.LoginThrottleConfig{
+						MaxAccountFailures: cfg.auth.loginThrottle.maxAccountFailures,
+						MaxIPFailures:      cfg.auth.loginThrottle.maxIPFailures,
+						Window:             cfg.auth.loginThrottle.window,
+						Lockout:            cfg.auth.loginThrottle.lockout,
+						MaxLockout:         cfg.auth.loginThrottle.maxLockout,
+						Delay:              cfg.auth.loginThrottle.delay,
+						MaxDelay:           cfg.auth.loginThrottle.maxDelay,
+						FrontendURL:        cfg.frontEndURL,
+					},
+					cacheStorage.LoginAttempts,
+					s.Outbox,
+				),
+				s.PersonalTokens,
+				s.Sessions,
 			),
-			Feed:  s.Feed,
-			Posts: s.Posts,
+			Authorizer: domain.NewAuthorizer(users, s.Roles),
+			Admin:      domain.NewAdminUseCase(s.Users, s.Roles, cacheStorage.Users, auditLog),
+			Audit:      auditLog,
+			Feed:       s.Feed,
+			Posts:      s.Posts,
 		},
 	}
+	if cfg.auth.oidc.issuer != "" {
+		provider := oidc.New(oidc.Config{
+			Name:         cfg.auth.oidc.providerName,
+			Issuer:       cfg.auth.oidc.issuer,
+			ClientID:     cfg.auth.oidc.clientID,
+			ClientSecret: cfg.auth.oidc.clientSecret,
+			RedirectURL:  cfg.auth.oidc.redirectURL,
+		}, &http.Client{Timeout: 10 * time.Second})
+		app.useCase.OIDC = domain.NewOIDCUseCase(
+			domain.OIDCConfig{StateExp: cfg.auth.oidc.stateExp},
+			app.useCase.Auth,
+			[]domain.OIDCProvider{provider},
+			s.OIDC,
+			s.Users,
+			s.Roles,
+			auditLog,
+		)
+		log.Info(ctx, "OIDC login configured", "provider", cfg.auth.oidc.providerName, "issuer", cfg.auth.oidc.issuer)
+	}
+
 	// TODO: Pass build type
 	expvar.NewString("build").Set("develop")
 
@@ -164,11 +297,59 @@ func main() {
 			"debug v1 router started",
 			"host", cfg.debugHost,
 		)
-		if err := http.ListenAndServe(cfg.debugHost, debug.Mux()); err != nil {
+		debugMux := debug.Mux()
+		if inbox != nil {
+			debugMux.Handle("/debug/mail/", inbox.Handler())
+		}
+		if err := http.ListenAndServe(cfg.debugHost, debugMux); err != nil {
 			log.Error(ctx, "debug v1 router stopped", "host", cfg.debugHost, "err", err)
 		}
 	}()
 
+	outboxCtx, stopOutbox := context.WithCancel(ctx)
+	outboxDone := make(chan struct{})
+	go func() {
+		defer close(outboxDone)
+		processor := domain.NewOutboxProcessor(
+			domain.OutboxConfig{
+				BatchSize:       cfg.outbox.batchSize,
+				MaxAttempts:     cfg.outbox.maxAttempts,
+				Backoff:         cfg.outbox.backoff,
+				MaxBackoff:      cfg.outbox.maxBackoff,
+				Lease:           cfg.outbox.lease,
+				DeliveryTimeout: cfg.outbox.deliveryTimeout,
+			},
+			s.Outbox,
+			map[string]domain.OutboxHandler{
+				domain.OutboxKindUserInvitation: app.useCase.Auth.InvitationHandler(),
+				domain.OutboxKindPasswordReset:  app.useCase.Auth.PasswordResetHandler(),
+				domain.OutboxKindAccountLocked:  app.useCase.Auth.AccountLockedHandler(),
+			},
+		)
+		log.Info(ctx, "outbox worker started", "interval", cfg.outbox.interval)
+		runOutbox(outboxCtx, log, cfg.outbox, processor)
+	}()
+	defer func() {
+		stopOutbox()
+		<-outboxDone
+	}()
+
+	cleanupCtx, stopCleanup := context.WithCancel(ctx)
+	cleanupDone := make(chan struct{})
+	go func() {
+		defer close(cleanupDone)
+		cleaner := domain.NewInvitationCleaner(
+			domain.InvitationCleanupConfig{GracePeriod: cfg.cleanup.gracePeriod},
+			s.Users,
+		)
+		log.Info(ctx, "invitation cleanup started", "interval", cfg.cleanup.interval, "grace_period", cfg.cleanup.gracePeriod)
+		runInvitationCleanup(cleanupCtx, log, cfg.cleanup, cleaner)
+	}()
+	defer func() {
+		stopCleanup()
+		<-cleanupDone
+	}()
+
 	server := app.makeServer(app.mount(ctx, log))
 	serverErrors := make(chan error, 1)
 
@@ -200,3 +381,67 @@ func main() {
 		}
 	}
 }
+
+// requireSecret returns the secret set in the name variable. Outside
+// development it refuses to start without one, in development it generates
+// a random one, so what it signs does not outlive the process.
+func requireSecret(name string, secret string, env string) (string, error) {
+	if secret != "" {
+		return secret, nil
+	}
+	if env != "development" {
+		return "", fmt.Errorf("%s is required outside development", name)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating %s: %w", name, err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// newMailer creates the mailer selected by the MAILER setting. The inbox is
+// only returned for the "inbox" mailer, to be exposed on the debug mux.
+func newMailer(cfg mailConfig) (mailer.Mailer, *mailer.InboxMailer, error) {
+	switch cfg.provider {
+	case "sendgrid":
+		return mailer.NewSendgridMailer(cfg.fromEmail, cfg.sendGridConfig.apiKey), nil, nil
+	case "smtp":
+		smtpMailer, err := mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:      cfg.smtp.host,
+			Port:      cfg.smtp.port,
+			Username:  cfg.smtp.username,
+			Password:  cfg.smtp.password,
+			FromEmail: cfg.fromEmail,
+			Security:  mailer.SMTPSecurity(cfg.smtp.security),
+		})
+		return smtpMailer, nil, err
+	case "inbox":
+		inbox, err := mailer.NewInboxMailer(cfg.fromEmail, cfg.inboxDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		return inbox, inbox, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown mailer %q", cfg.provider)
+	}
+}
+
+// newJWTAuthenticator signs tokens with the keys of JWT_KEYS_DIR when set,
+// and with the JWT_SECRET shared secret otherwise.
+func newJWTAuthenticator(cfg jwtAuthConfig) (*jwt.JWTAutheticator, error) {
+	if cfg.keysDir == "" {
+		return jwt.NewJWTAutheticator(cfg.secret, cfg.tokenHost, cfg.tokenHost, cfg.tokenHost, cfg.exp), nil
+	}
+
+	keys, err := jwt.LoadKeys(cfg.keysDir, cfg.activeKID)
+	if err != nil {
+		return nil, fmt.Errorf("loading jwt keys: %w", err)
+	}
+
+	secret := ""
+	if cfg.hs256Fallback {
+		secret = cfg.secret
+	}
+	return jwt.NewJWTAutheticatorWithKeys(keys, secret, cfg.tokenHost, cfg.tokenHost, cfg.tokenHost, cfg.exp), nil
+}
@@ -9,10 +9,6 @@ import (
 	"strings"
 )
 
-type authUserKey string
-
-const authUserCtx authUserKey = "authUser"
-
 func (app *application) BasicAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
@@ -43,42 +39,6 @@ func (app *application) BasicAuthMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func (app *application) checkPostOwnershipMiddleware(roleType domain.RoleType, next http.HandlerFunc) http.HandlerFunc {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		currentUser := getAuthUserFromContext(r)
-		post := getPostFromContext(r)
-
-		if currentUser.ID == post.UserID {
-			next.ServeHTTP(w, r)
-			return
-		}
-
-		// Role precedence check
-		isValid, err := app.checkRolePrecedenceForPost(currentUser, r.Context(), roleType)
-		if err != nil {
-			app.internalServerError(w, r, err)
-			return
-		}
-		if !isValid {
-			app.forbiddenResponse(w, r, fmt.Errorf("forbidden"))
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
-func getAuthUserFromContext(r *http.Request) *domain.User {
-	return r.Context().Value(authUserCtx).(*domain.User)
-}
-
-func (app *application) checkRolePrecedenceForPost(user *domain.User, ctx context.Context, roleType domain.RoleType) (bool, error) {
-	role, err := app.store.Roles.GetByRoleType(ctx, roleType)
-	if err != nil {
-		return false, err
-	}
-	return user.Role.Level >= role.Level, nil
-}
-
 func (app *application) getUser(ctx context.Context, userID int64) (*domain.User, error) {
 	// Try to get user from cache
 	if user, err := app.cache.Users.Get(ctx, userID); err == nil && user != nil {
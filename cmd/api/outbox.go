@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"github.com/sergdort/Social/business/domain"
+	"github.com/sergdort/Social/foundation/logger"
+)
+
+type outboxConfig struct {
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	lease       time.Duration
+	// deliveryTimeout bounds every delivery, shorter than the lease so a
+	// message is not claimed again while it is delivered.
+	deliveryTimeout time.Duration
+}
+
+var (
+	outboxSent    = expvar.NewInt("outbox_sent")
+	outboxFailed  = expvar.NewInt("outbox_failed")
+	outboxDead    = expvar.NewInt("outbox_dead")
+	outboxPending = expvar.NewInt("outbox_pending")
+)
+
+// runOutbox delivers outbox messages every interval until the context is
+// cancelled. A tick keeps claiming batches while they come back full, so a
+// backlog is drained without waiting for the next tick.
+func runOutbox(ctx context.Context, log *logger.Logger, cfg outboxConfig, processor *domain.OutboxProcessor) {
+	ticker := time.NewTicker(cfg.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			result, err := processor.ProcessBatch(ctx)
+			outboxSent.Add(int64(result.Sent))
+			outboxFailed.Add(int64(result.Failed))
+			outboxDead.Add(int64(result.Dead))
+			if result.Failed > 0 || result.Dead > 0 {
+				log.Info(ctx, "outbox", "sent", result.Sent, "failed", result.Failed, "dead", result.Dead)
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Error(ctx, "outbox", "err", err)
+				}
+				break
+			}
+			if result.Total() < cfg.batchSize {
+				break
+			}
+		}
+
+		if pending, err := processor.Pending(ctx); err == nil {
+			outboxPending.Set(pending)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
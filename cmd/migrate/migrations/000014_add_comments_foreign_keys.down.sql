@@ -0,0 +1,3 @@
+ALTER TABLE comments
+    DROP CONSTRAINT IF EXISTS fk_comments_user,
+    DROP CONSTRAINT IF EXISTS fk_comments_post;
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE comments
+    ALTER COLUMN post_id DROP DEFAULT,
+    ALTER COLUMN user_id DROP DEFAULT;
+
+DROP SEQUENCE IF EXISTS comments_post_id_seq;
+DROP SEQUENCE IF EXISTS comments_user_id_seq;
+
+-- Comments of deleted posts or users were never cleaned up.
+DELETE
+FROM comments c
+WHERE NOT EXISTS (SELECT 1 FROM posts p WHERE p.id = c.post_id)
+   OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = c.user_id);
+
+ALTER TABLE comments
+    ADD CONSTRAINT fk_comments_post FOREIGN KEY (post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_comments_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
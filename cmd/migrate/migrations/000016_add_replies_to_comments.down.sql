@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_comments_parent_comment_id;
+
+ALTER TABLE comments
+    DROP CONSTRAINT IF EXISTS fk_comments_parent,
+    DROP COLUMN IF EXISTS depth,
+    DROP COLUMN IF EXISTS parent_comment_id;
@@ -0,0 +1,6 @@
+ALTER TABLE comments
+    ADD COLUMN parent_comment_id bigint NULL,
+    ADD COLUMN depth             int    NOT NULL DEFAULT 0 CHECK (depth >= 0),
+    ADD CONSTRAINT fk_comments_parent FOREIGN KEY (parent_comment_id) REFERENCES comments (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_comments_parent_comment_id ON comments (parent_comment_id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS outbox_messages
+(
+    id           bigserial PRIMARY KEY,
+    kind         varchar(64) NOT NULL,
+    payload      jsonb       NOT NULL,
+    status       varchar(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'dead')),
+    attempts     int         NOT NULL DEFAULT 0,
+    last_error   text,
+    available_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    created_at   timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    processed_at timestamp(0) with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_pending ON outbox_messages (available_at) WHERE status = 'pending';
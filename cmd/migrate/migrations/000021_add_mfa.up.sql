@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS user_totp
+(
+    user_id        bigint PRIMARY KEY,
+    secret         varchar(64)                 NOT NULL,
+    confirmed_at   timestamp(0) with time zone,
+    last_used_step bigint                      NOT NULL DEFAULT 0,
+    created_at     timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes
+(
+    id      bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    code    bytea  NOT NULL,
+    used_at timestamp(0) with time zone,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    UNIQUE (user_id, code)
+);
+
+CREATE TABLE IF NOT EXISTS mfa_challenges
+(
+    token    bytea PRIMARY KEY,
+    user_id  bigint                      NOT NULL,
+    expiry   timestamp(0) with time zone NOT NULL,
+    attempts int                         NOT NULL DEFAULT 0,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
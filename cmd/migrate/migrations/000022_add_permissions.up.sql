@@ -0,0 +1,64 @@
+CREATE TABLE IF NOT EXISTS permissions
+(
+    id          bigserial PRIMARY KEY,
+    name        varchar(100) NOT NULL UNIQUE,
+    description text
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions
+(
+    role_id       bigint NOT NULL,
+    permission_id bigint NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES permissions (id) ON DELETE CASCADE
+);
+
+-- Permissions ending with :own only apply to the resources of the user,
+-- the ones ending with :any to every resource.
+INSERT INTO permissions (name, description)
+VALUES ('posts:create', 'Create posts'),
+       ('posts:update:own', 'Update own posts'),
+       ('posts:update:any', 'Update any post'),
+       ('posts:delete:own', 'Delete own posts'),
+       ('posts:delete:any', 'Delete any post'),
+       ('comments:create', 'Comment on posts'),
+       ('comments:update:own', 'Update own comments'),
+       ('comments:update:any', 'Update any comment'),
+       ('comments:delete:own', 'Delete own comments'),
+       ('comments:delete:any', 'Delete any comment'),
+       ('users:ban', 'Ban users');
+
+-- The grants keep the behaviour of the role levels: moderators can update
+-- and delete any comment and update any post, admins can also delete any post.
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON p.name IN (
+                                          'posts:create',
+                                          'posts:update:own',
+                                          'posts:delete:own',
+                                          'comments:create',
+                                          'comments:update:own',
+                                          'comments:delete:own'
+    )
+WHERE r.name IN ('user', 'moderator', 'admin');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON p.name IN (
+                                          'posts:update:any',
+                                          'comments:update:any',
+                                          'comments:delete:any'
+    )
+WHERE r.name IN ('moderator', 'admin');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON p.name IN (
+                                          'posts:delete:any',
+                                          'users:ban'
+    )
+WHERE r.name = 'admin';
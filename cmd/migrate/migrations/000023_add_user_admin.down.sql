@@ -0,0 +1,10 @@
+DELETE
+FROM permissions
+WHERE name IN ('users:read', 'users:update', 'users:delete');
+
+ALTER TABLE posts
+    DROP CONSTRAINT fk_user,
+    ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id);
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS banned_at;
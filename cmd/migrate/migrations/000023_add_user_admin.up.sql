@@ -0,0 +1,22 @@
+ALTER TABLE users
+    ADD COLUMN banned_at timestamp(0) with time zone;
+
+-- Deleting a user deletes their posts, like it already does for comments.
+ALTER TABLE posts
+    DROP CONSTRAINT fk_user,
+    ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
+
+INSERT INTO permissions (name, description)
+VALUES ('users:read', 'List and view users'),
+       ('users:update', 'Change the role and activation of users'),
+       ('users:delete', 'Delete users');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON p.name IN (
+                                          'users:read',
+                                          'users:update',
+                                          'users:delete'
+    )
+WHERE r.name = 'admin';
@@ -0,0 +1,49 @@
+-- Audit events outlive the users they refer to, so actor_id and target_id
+-- are not foreign keys.
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id          bigserial PRIMARY KEY,
+    actor_id    bigint,
+    action      varchar(64)                 NOT NULL,
+    target_type varchar(32)                 NOT NULL,
+    target_id   bigint,
+    ip          varchar(45)                 NOT NULL DEFAULT '',
+    user_agent  text                        NOT NULL DEFAULT '',
+    trace_id    varchar(64)                 NOT NULL DEFAULT '',
+    metadata    jsonb                       NOT NULL DEFAULT '{}',
+    created_at  timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events (target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events (action);
+
+-- Audit events are append-only.
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_update_delete
+    BEFORE UPDATE OR DELETE
+    ON audit_events
+    FOR EACH ROW
+EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE
+    ON audit_events
+    FOR EACH STATEMENT
+EXECUTE FUNCTION audit_events_append_only();
+
+INSERT INTO permissions (name, description)
+VALUES ('audit:read', 'Query the audit log');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON p.name = 'audit:read'
+WHERE r.name = 'admin';
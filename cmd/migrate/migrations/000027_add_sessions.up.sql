@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS sessions
+(
+    id           uuid PRIMARY KEY,
+    user_id      bigint                      NOT NULL,
+    user_agent   text                        NOT NULL DEFAULT '',
+    ip           text                        NOT NULL DEFAULT '',
+    expiry       timestamp(0) with time zone NOT NULL,
+    last_used_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    revoked_at   timestamp(0) with time zone,
+    created_at   timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+
+-- A session is a refresh token family, the ones in use before sessions
+-- existed get one with an unknown client.
+INSERT INTO sessions (id, user_id, expiry, last_used_at, created_at)
+SELECT family_id, user_id, MAX(expiry), MAX(created_at), MIN(created_at)
+FROM refresh_tokens
+WHERE revoked_at IS NULL
+  AND expiry > NOW()
+GROUP BY family_id, user_id
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_unactivated;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS activated_at;
@@ -0,0 +1,10 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS activated_at timestamp(0) with time zone;
+
+-- Users without an invitation activated theirs, even when deactivated since.
+UPDATE users u
+SET activated_at = u.created_at
+WHERE u.is_active
+   OR NOT EXISTS (SELECT 1 FROM user_invitations i WHERE i.user_id = u.id);
+
+CREATE INDEX IF NOT EXISTS idx_users_unactivated ON users (created_at) WHERE activated_at IS NULL;
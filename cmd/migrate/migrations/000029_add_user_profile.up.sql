@@ -0,0 +1,6 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS display_name varchar(50)  NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS bio          text         NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS location     varchar(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS website      varchar(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS avatar_url   varchar(255) NOT NULL DEFAULT '';
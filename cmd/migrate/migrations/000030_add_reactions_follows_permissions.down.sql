@@ -0,0 +1,3 @@
+DELETE
+FROM permissions
+WHERE name IN ('reactions:write', 'follows:write');
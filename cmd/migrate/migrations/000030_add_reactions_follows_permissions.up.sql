@@ -0,0 +1,10 @@
+INSERT INTO permissions (name, description)
+VALUES ('reactions:write', 'React to posts and comments'),
+       ('follows:write', 'Follow and unfollow users');
+
+-- Every role could react and follow before these permissions existed.
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON p.name IN ('reactions:write', 'follows:write')
+WHERE r.name IN ('user', 'moderator', 'admin');
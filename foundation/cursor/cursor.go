@@ -0,0 +1,76 @@
+// Package cursor provides support for opaque, tamper proof pagination cursors.
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalid is returned when a cursor is malformed or its signature does
+// not match.
+var ErrInvalid = errors.New("invalid cursor")
+
+// Codec encodes values into signed cursors and decodes them back. The
+// cursor content is not encrypted, the signature only guarantees that the
+// client did not craft or alter it.
+type Codec struct {
+	secret []byte
+}
+
+// New constructs a Codec that signs cursors with the given secret.
+func New(secret string) *Codec {
+	return &Codec{
+		secret: []byte(secret),
+	}
+}
+
+// Encode serializes the value into a signed cursor.
+func (c *Codec) Encode(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	signature := base64.RawURLEncoding.EncodeToString(c.sign(payload))
+
+	return payload + "." + signature, nil
+}
+
+// Decode verifies the cursor signature and deserializes it into v.
+func (c *Codec) Decode(cursor string, v any) error {
+	payload, signature, found := strings.Cut(cursor, ".")
+	if !found {
+		return ErrInvalid
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	if !hmac.Equal(sig, c.sign(payload)) {
+		return ErrInvalid
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrInvalid
+	}
+
+	return nil
+}
+
+func (c *Codec) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,77 @@
+package cursor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type position struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+}
+
+func TestCodec(t *testing.T) {
+	codec := New("secret")
+
+	t.Run("decodes what it encodes", func(t *testing.T) {
+		want := position{CreatedAt: time.Date(2025, 3, 19, 10, 8, 25, 0, time.UTC), ID: 117}
+
+		c, err := codec.Encode(want)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		var got position
+		if err := codec.Decode(c, &got); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("rejects tampered cursors", func(t *testing.T) {
+		c, err := codec.Encode(position{ID: 1})
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		forged, err := codec.Encode(position{ID: 2})
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		payload, _, _ := strings.Cut(forged, ".")
+		_, signature, _ := strings.Cut(c, ".")
+		tampered := payload + "." + signature
+
+		var got position
+		if err := codec.Decode(tampered, &got); !errors.Is(err, ErrInvalid) {
+			t.Errorf("expected ErrInvalid, got %v", err)
+		}
+	})
+
+	t.Run("rejects cursors signed with another secret", func(t *testing.T) {
+		c, err := New("other").Encode(position{ID: 1})
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		var got position
+		if err := codec.Decode(c, &got); !errors.Is(err, ErrInvalid) {
+			t.Errorf("expected ErrInvalid, got %v", err)
+		}
+	})
+
+	t.Run("rejects malformed cursors", func(t *testing.T) {
+		var got position
+		for _, c := range []string{"", "abc", "abc.def", "!!!.???"} {
+			if err := codec.Decode(c, &got); !errors.Is(err, ErrInvalid) {
+				t.Errorf("%q: expected ErrInvalid, got %v", c, err)
+			}
+		}
+	})
+}
@@ -3,6 +3,7 @@ package env
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 func GetString(key, fallback string) string {
@@ -31,3 +32,13 @@ func GetBool(key string, fallback bool) bool {
 	}
 	return fallback
 }
+
+func GetDuration(key string, fallback time.Duration) time.Duration {
+	if value, ok := os.LookupEnv(key); ok {
+		if valueDuration, err := time.ParseDuration(value); err == nil {
+			return valueDuration
+		}
+		return fallback
+	}
+	return fallback
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops expired keys.
+const sweepInterval = time.Minute
+
+type entry struct {
+	state  State
+	expiry time.Time
+}
+
+// MemoryStore counts requests in memory, per process.
+type MemoryStore struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]entry
+	nextSweep time.Time
+}
+
+// NewMemoryStore constructs an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		now:     time.Now,
+		entries: make(map[string]entry),
+	}
+}
+
+// Allow counts a request of the key against the limit.
+func (s *MemoryStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	var state State
+	if e, ok := s.entries[key]; ok && e.expiry.After(now) {
+		state = e.state
+	}
+
+	state, result := limit.Take(state, now)
+	s.entries[key] = entry{state: state, expiry: now.Add(limit.TTL())}
+
+	return result, nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	for key, e := range s.entries {
+		if !e.expiry.After(now) {
+			delete(s.entries, key)
+		}
+	}
+	s.nextSweep = now.Add(sweepInterval)
+}
@@ -0,0 +1,155 @@
+// Package ratelimit provides support for limiting how often a key, such as
+// a user or an IP address, may do something.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Algorithm decides how the requests of a Limit are counted.
+type Algorithm int
+
+const (
+	// TokenBucket refills a bucket of Burst tokens at Requests per Period
+	// and takes a token per request, allowing bursts after idle periods.
+	TokenBucket Algorithm = iota
+	// SlidingWindow allows Requests within any Period, approximated from
+	// the counts of the current and the previous fixed window.
+	SlidingWindow
+)
+
+// Limit allows Requests per Period.
+type Limit struct {
+	Algorithm Algorithm
+	Requests  int
+	Period    time.Duration
+	// Burst is the capacity of a token bucket, Requests when zero. Sliding
+	// windows do not use it.
+	Burst int
+}
+
+// Result is the outcome of a request against a Limit.
+type Result struct {
+	Allowed bool
+	// Limit and Remaining are the requests of the quota and how many of them
+	// are left.
+	Limit     int
+	Remaining int
+	// Reset is the time until the quota is fully available again.
+	Reset time.Duration
+	// RetryAfter is the time until a request is allowed again, only set
+	// when the request was not allowed.
+	RetryAfter time.Duration
+}
+
+// State is what a Store keeps per key between requests. The zero State is
+// the one of a key without any request.
+type State struct {
+	// Start is when a token bucket was last refilled, or when the current
+	// window of a sliding window started.
+	Start    time.Time `json:"start"`
+	Tokens   float64   `json:"tokens,omitempty"`
+	Current  int       `json:"current,omitempty"`
+	Previous int       `json:"previous,omitempty"`
+}
+
+// Store counts the requests of keys against their limit.
+type Store interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Take counts a request made at now against the limit and returns the new
+// state of the key.
+func (l Limit) Take(state State, now time.Time) (State, Result) {
+	if l.Algorithm == SlidingWindow {
+		return l.slidingWindow(state, now)
+	}
+	return l.tokenBucket(state, now)
+}
+
+// TTL is how long the state of a key must be kept after a request.
+func (l Limit) TTL() time.Duration {
+	if l.Algorithm == SlidingWindow {
+		return 2 * l.Period
+	}
+	return l.refill(float64(l.capacity()))
+}
+
+func (l Limit) capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// refill returns the time to refill the tokens.
+func (l Limit) refill(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens * float64(l.Period) / float64(l.Requests)))
+}
+
+func (l Limit) tokenBucket(state State, now time.Time) (State, Result) {
+	capacity := float64(l.capacity())
+
+	tokens := capacity
+	if !state.Start.IsZero() {
+		elapsed := max(now.Sub(state.Start), 0)
+		tokens = min(capacity, state.Tokens+float64(elapsed)*float64(l.Requests)/float64(l.Period))
+	}
+
+	result := Result{Limit: l.capacity()}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = l.refill(1 - tokens)
+	}
+	result.Remaining = int(tokens)
+	result.Reset = l.refill(capacity - tokens)
+
+	return State{Start: now, Tokens: tokens}, result
+}
+
+func (l Limit) slidingWindow(state State, now time.Time) (State, Result) {
+	start := now.Truncate(l.Period)
+	switch {
+	case state.Start.Equal(start):
+	case state.Start.Equal(start.Add(-l.Period)):
+		state = State{Start: start, Previous: state.Current}
+	default:
+		state = State{Start: start}
+	}
+
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(l.Period)
+	count := int(float64(state.Previous)*weight) + state.Current
+
+	result := Result{Limit: l.Requests, Reset: l.Period - elapsed}
+	if count < l.Requests {
+		state.Current++
+		count++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = l.retryAfter(state, elapsed)
+	}
+	result.Remaining = max(l.Requests-count, 0)
+
+	return state, result
+}
+
+// retryAfter returns the time until the count of a full sliding window
+// drops below the limit, as the previous window weighs less and less.
+func (l Limit) retryAfter(state State, elapsed time.Duration) time.Duration {
+	period := float64(l.Period)
+
+	// Only the current window, once it becomes the previous one, can lower
+	// the count enough.
+	if state.Current >= l.Requests {
+		next := period * (1 - float64(l.Requests)/float64(state.Current))
+		return l.Period - elapsed + time.Duration(math.Ceil(next))
+	}
+
+	at := period * (1 - float64(l.Requests-state.Current)/float64(state.Previous))
+	return max(time.Duration(math.Ceil(at))-elapsed, time.Nanosecond)
+}
@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	limit := Limit{Algorithm: TokenBucket, Requests: 60, Period: time.Minute, Burst: 3}
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+
+	t.Run("allows bursts up to the capacity", func(t *testing.T) {
+		var state State
+		var result Result
+		for range 3 {
+			state, result = limit.Take(state, now)
+			if !result.Allowed {
+				t.Fatalf("expected the request to be allowed")
+			}
+		}
+		if result.Remaining != 0 || result.Limit != 3 || result.Reset != 3*time.Second {
+			t.Errorf("unexpected result %+v", result)
+		}
+
+		_, result = limit.Take(state, now)
+		if result.Allowed || result.RetryAfter != time.Second {
+			t.Errorf("expected a denied request retrying in 1s, got %+v", result)
+		}
+	})
+
+	t.Run("refills at the rate of the limit", func(t *testing.T) {
+		state := State{Start: now, Tokens: 0}
+
+		state, result := limit.Take(state, now.Add(1500*time.Millisecond))
+		if !result.Allowed || result.Remaining != 0 {
+			t.Errorf("unexpected result %+v", result)
+		}
+
+		_, result = limit.Take(state, now.Add(time.Hour))
+		if !result.Allowed || result.Remaining != 2 {
+			t.Errorf("expected a full bucket, got %+v", result)
+		}
+	})
+}
+
+func TestSlidingWindow(t *testing.T) {
+	limit := Limit{Algorithm: SlidingWindow, Requests: 10, Period: time.Minute}
+	start := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+
+	t.Run("denies requests past the limit of the window", func(t *testing.T) {
+		var state State
+		var result Result
+		for range 10 {
+			state, result = limit.Take(state, start.Add(45*time.Second))
+		}
+		if !result.Allowed || result.Remaining != 0 || result.Reset != 15*time.Second {
+			t.Errorf("unexpected result %+v", result)
+		}
+
+		_, result = limit.Take(state, start.Add(45*time.Second))
+		if result.Allowed {
+			t.Fatalf("expected the request to be denied")
+		}
+		// Any request of the next window lowers the count below the limit.
+		if result.RetryAfter != 15*time.Second {
+			t.Errorf("expected to retry in 15s, got %s", result.RetryAfter)
+		}
+	})
+
+	t.Run("weighs the previous window by its overlap", func(t *testing.T) {
+		state := State{Start: start.Add(time.Minute), Current: 5, Previous: 10}
+
+		// 3/4 of the previous window counts.
+		state, result := limit.Take(state, start.Add(75*time.Second))
+		if result.Allowed {
+			t.Fatalf("expected the request to be denied")
+		}
+		if result.RetryAfter != 15*time.Second {
+			t.Errorf("expected to retry in 15s, got %s", result.RetryAfter)
+		}
+
+		_, result = limit.Take(state, start.Add(91*time.Second))
+		if !result.Allowed {
+			t.Errorf("expected the request to be allowed, got %+v", result)
+		}
+	})
+
+	t.Run("forgets windows older than the previous one", func(t *testing.T) {
+		state := State{Start: start, Current: 10}
+
+		_, result := limit.Take(state, start.Add(3*time.Minute))
+		if !result.Allowed || result.Remaining != 9 {
+			t.Errorf("unexpected result %+v", result)
+		}
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Algorithm: SlidingWindow, Requests: 1, Period: time.Minute}
+
+	if result, _ := store.Allow(context.Background(), "a", limit); !result.Allowed {
+		t.Errorf("expected the first request to be allowed")
+	}
+	if result, _ := store.Allow(context.Background(), "a", limit); result.Allowed {
+		t.Errorf("expected the second request to be denied")
+	}
+	if result, _ := store.Allow(context.Background(), "b", limit); !result.Allowed {
+		t.Errorf("expected keys to be limited separately")
+	}
+
+	now = now.Add(3 * time.Minute)
+	if result, _ := store.Allow(context.Background(), "a", limit); !result.Allowed {
+		t.Errorf("expected the request to be allowed once the state expired")
+	}
+}
@@ -0,0 +1,117 @@
+package totp
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test vectors from RFC 6238 Appendix B.
+func TestGenerate_RFC6238(t *testing.T) {
+	seeds := map[string][]byte{
+		"SHA1":   []byte("12345678901234567890"),
+		"SHA256": []byte("12345678901234567890123456789012"),
+		"SHA512": []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+	hashes := map[string]func() hash.Hash{
+		"SHA1":   sha1.New,
+		"SHA256": sha256.New,
+		"SHA512": sha512.New,
+	}
+
+	tests := []struct {
+		unix int64
+		mode string
+		code string
+	}{
+		{59, "SHA1", "94287082"},
+		{59, "SHA256", "46119246"},
+		{59, "SHA512", "90693936"},
+		{1111111109, "SHA1", "07081804"},
+		{1111111109, "SHA256", "68084774"},
+		{1111111109, "SHA512", "25091201"},
+		{1111111111, "SHA1", "14050471"},
+		{1111111111, "SHA256", "67062674"},
+		{1111111111, "SHA512", "99943326"},
+		{1234567890, "SHA1", "89005924"},
+		{1234567890, "SHA256", "91819424"},
+		{1234567890, "SHA512", "93441116"},
+		{2000000000, "SHA1", "69279037"},
+		{2000000000, "SHA256", "90698825"},
+		{2000000000, "SHA512", "38618901"},
+		{20000000000, "SHA1", "65353130"},
+		{20000000000, "SHA256", "77737706"},
+		{20000000000, "SHA512", "47863826"},
+	}
+
+	for _, tt := range tests {
+		step := Step(time.Unix(tt.unix, 0))
+		got := generate(hashes[tt.mode], seeds[tt.mode], uint64(step), 8)
+		if got != tt.code {
+			t.Errorf("%s at %d: got %s, want %s", tt.mode, tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	secret := encoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(59, 0)
+
+	code, err := Code(secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The last six digits of the RFC vector.
+	if code != "287082" {
+		t.Fatalf("got code %s, want 287082", code)
+	}
+
+	if step, ok := Verify(secret, code, now, 1); !ok || step != 1 {
+		t.Errorf("expected the code to match step 1, got %d %v", step, ok)
+	}
+	if _, ok := Verify(secret, code, now.Add(Period), 1); !ok {
+		t.Error("expected the code to be accepted one step later")
+	}
+	if _, ok := Verify(secret, code, now.Add(2*Period), 1); ok {
+		t.Error("expected the code to be rejected two steps later")
+	}
+	if _, ok := Verify(secret, "000000", now, 1); ok {
+		t.Error("expected a wrong code to be rejected")
+	}
+	if _, ok := Verify("not base32!", code, now, 1); ok {
+		t.Error("expected an invalid secret to be rejected")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := decode(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != secretSize {
+		t.Errorf("got a %d bytes secret, want %d", len(key), secretSize)
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Go Social", "arya@winterfell.com", "JBSWY3DPEHPK3PXP")
+
+	want := "otpauth://totp/Go%20Social:arya@winterfell.com?"
+	if !strings.HasPrefix(uri, want) {
+		t.Errorf("got %s, want prefix %s", uri, want)
+	}
+	for _, param := range []string{"secret=JBSWY3DPEHPK3PXP", "issuer=Go+Social", "digits=6", "period=30"} {
+		if !strings.Contains(uri, param) {
+			t.Errorf("expected %s in %s", param, uri)
+		}
+	}
+}
@@ -39,6 +39,29 @@ func (r Response[T]) Encode() ([]byte, string, error) {
 	return data, "application/json", err
 }
 
+// PageResponse represents a page of a cursor paginated listing
+// @Description Paginated API response wrapper
+type PageResponse[T any] struct {
+	// The actual payload of the response
+	Data T `json:"data"`
+	// Opaque cursor of the next page, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPageResponse creates a new PageResponse with the given data and cursor
+func NewPageResponse[T any](data T, nextCursor string) PageResponse[T] {
+	return PageResponse[T]{
+		Data:       data,
+		NextCursor: nextCursor,
+	}
+}
+
+// Encode implements the Encoder interface
+func (r PageResponse[T]) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(r)
+	return data, "application/json", err
+}
+
 type httpStatus interface {
 	HTTPStatus() int
 }